@@ -5,9 +5,12 @@ import (
     "micro-lending-platform/backend/internal/config"
     "micro-lending-platform/backend/internal/database"
     "micro-lending-platform/backend/internal/handlers"
+    "micro-lending-platform/backend/internal/jobs"
+    "micro-lending-platform/backend/internal/period"
     "micro-lending-platform/backend/internal/repositories"
     "micro-lending-platform/backend/internal/services"
     "path/filepath"
+    "time"
     "github.com/gin-gonic/gin"
 )
 
@@ -69,16 +72,110 @@ func main() {
     loanRepo := repositories.NewLoanRepository(db.DB)
     paymentRepo := repositories.NewPaymentRepository(db.DB)
     reportRepo := repositories.NewReportRepository(db.DB)
+    documentTemplateRepo := repositories.NewDocumentTemplateRepository(db.DB)
+    chargeRepo := repositories.NewLoanChargeRepository(db.DB)
+    branchRepo := repositories.NewBranchRepository(db.DB)
+    holidayRepo := repositories.NewHolidayRepository(db.DB)
+    complianceRepo := repositories.NewComplianceRepository(db.DB)
+    cashierRepo := repositories.NewCashierRepository(db.DB)
+    walletRepo := repositories.NewWalletRepository(db.DB)
+    bankImportRepo := repositories.NewBankImportRepository(db.DB)
+    snapshotRepo := repositories.NewSnapshotRepository(db.DB)
+    ledgerRepo := repositories.NewLedgerRepository(db.DB)
+    provisioningRepo := repositories.NewProvisioningRepository(db.DB)
+    accrualRepo := repositories.NewAccrualRepository(db.DB)
+    exportRepo := repositories.NewJournalExportRepository(db.DB)
+
+    // Report days and weeks are cut in the business timezone
+    reportPeriods, err := period.DefaultOptions().WithOverrides(cfg.BusinessTimezone, cfg.ReportWeekStart)
+    if err != nil {
+        log.Fatal("Invalid report period settings:", err)
+    }
 
     // Initialize services
     authService := services.NewAuthService(userRepo)
-    clientService := services.NewClientService(clientRepo)
-    loanService := services.NewLoanService(loanRepo, clientRepo)
-    paymentService := services.NewPaymentService(paymentRepo, loanRepo)
-    reportService := services.NewReportService(reportRepo) 
+    calendarService := services.NewCalendarService(holidayRepo, branchRepo)
+    complianceService := services.NewComplianceService(complianceRepo, loanRepo, calendarService)
+    ledgerService := services.NewLedgerService(ledgerRepo, loanRepo, paymentRepo, chargeRepo, calendarService, reportPeriods)
+    if err := ledgerService.EnsureChartOfAccounts(); err != nil {
+        log.Fatal("Failed to set up the general ledger:", err)
+    }
+    clientService := services.NewClientService(clientRepo, calendarService, complianceService, ledgerService)
+    loanService := services.NewLoanService(loanRepo, clientRepo, chargeRepo, paymentRepo, calendarService, complianceService, ledgerService)
+    paymentService := services.NewPaymentService(paymentRepo, loanRepo, ledgerService)
+    reportService := services.NewReportService(reportRepo, loanRepo, paymentRepo, chargeRepo, userRepo, snapshotRepo, calendarService, reportPeriods)
+    provisioningService := services.NewProvisioningService(provisioningRepo, ledgerService, reportService)
+    accrualService := services.NewAccrualService(accrualRepo, ledgerService)
+    exportService := services.NewJournalExportService(exportRepo, ledgerService)
+    documentService := services.NewDocumentService(documentTemplateRepo, loanRepo, calendarService)
+    statementService := services.NewStatementService(clientRepo, loanRepo, paymentRepo, chargeRepo, calendarService)
+    collectionService := services.NewCollectionService(loanRepo, paymentRepo, userRepo, calendarService, paymentService, reportPeriods)
+    syncService := services.NewSyncService(loanRepo, paymentRepo, userRepo, calendarService, paymentService)
+    cashierService := services.NewCashierService(cashierRepo, branchRepo)
+    walletService := services.NewWalletService(walletRepo, loanRepo, paymentService, cfg.WalletWebhookSecrets)
+    bankImportService := services.NewBankImportService(bankImportRepo, loanRepo, calendarService, paymentService)
 
     // Setup routes with all services
-    handlers.SetupRoutes(router, authService, clientService, loanService, paymentService, reportService)
+    handlers.SetupRoutes(router, authService, clientService, loanService, paymentService, reportService, documentService, statementService, calendarService, complianceService, collectionService, syncService, cashierService, walletService, bankImportService, ledgerService, provisioningService, accrualService, exportService)
+
+    // Nightly jobs
+    scheduler := jobs.NewScheduler(reportPeriods.Location)
+    scheduler.Daily("overdue-detection", 0, 30, func(now time.Time) error {
+        result, err := loanService.RefreshOverdueStatuses(now)
+        if err != nil {
+            return err
+        }
+        log.Printf("Overdue detection: %d checked, %d marked overdue, %d cleared", result.LoansChecked, result.MarkedOverdue, result.Cleared)
+        return nil
+    })
+    // Snapshot yesterday's closing portfolio just after midnight, before anything is posted for today.
+    // Days missed while the server was down are rebuilt at startup or the next night.
+    scheduler.DailyWithCatchUp("portfolio-snapshot", 0, 5, func(now time.Time) error {
+        summaries, err := reportService.CatchUpSnapshots(period.Day(now, reportPeriods).FirstDay().AddDate(0, 0, -1))
+        for _, summary := range summaries {
+            log.Printf("Portfolio snapshot for %s: %d loans, %.2f outstanding", summary.SnapshotDate, summary.Loans, summary.Outstanding)
+        }
+        return err
+    })
+    // Post anything the ledger missed during the day, e.g. loans edited while a posting failed
+    scheduler.Daily("ledger-sync", 0, 45, func(now time.Time) error {
+        result, err := ledgerService.SyncAll()
+        if err != nil {
+            return err
+        }
+        log.Printf("Ledger sync: %d loans checked, %d entries posted", result.LoansChecked, result.EntriesPosted)
+        return nil
+    })
+    // Close each month once the first day of the next has begun: recognize its interest, then provision
+    // for loan losses on the net balances that leaves. Every month ended since the last run of each is
+    // done, so one missed while the server was down is made up at startup or the next night.
+    scheduler.DailyWithCatchUp("month-end", 0, 50, func(now time.Time) error {
+        months, err := accrualService.PendingMonths()
+        if err != nil {
+            return err
+        }
+        for _, month := range months {
+            run, err := accrualService.RunAccruals(month, "system")
+            if err != nil {
+                return err
+            }
+            log.Printf("Interest accrual for %s: %.2f accrued, %.2f reversed", run.Month, run.Accrued, run.Reversed)
+        }
+
+        if months, err = provisioningService.PendingMonths(); err != nil {
+            return err
+        }
+        for _, month := range months {
+            run, err := provisioningService.RunProvisioning(month, "system")
+            if err != nil {
+                return err
+            }
+            log.Printf("Loan loss provisioning for %s: %.2f required, %.2f posted", run.Month, run.RequiredAllowance, run.Adjustment)
+        }
+        return nil
+    })
+    scheduler.Start()
+    defer scheduler.Stop()
 
     // Start the HTTP server on the configured port
     log.Printf("Server starting on port %s", cfg.ServerPort)
@@ -5,6 +5,7 @@ go 1.24.4
 require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/golang-jwt/jwt/v4 v4.5.2
+	github.com/jung-kurt/gofpdf v1.16.2
 	golang.org/x/crypto v0.9.0
 	gorm.io/driver/sqlite v1.5.0
 	gorm.io/gorm v1.25.0
@@ -3,6 +3,7 @@ package config
 import (
     "os"
     "strconv"
+    "strings"
 )
 
 type Config struct {
@@ -10,15 +11,35 @@ type Config struct {
     ServerPort   string
     JWTSecret    string
     Environment  string
+
+    // WalletWebhookSecrets maps an e-wallet provider name to its webhook signing secret
+    WalletWebhookSecrets map[string]string
+
+    // BusinessTimezone and ReportWeekStart decide where report days and weeks are cut;
+    // both can be overridden per request with ?tz= and ?week_start=
+    BusinessTimezone string
+    ReportWeekStart  string
 }
 
 func Load() *Config {
-    return &Config{
+    cfg := &Config{
         DBPath:      getEnv("DB_PATH", "./data/micro_lending.db"),
         ServerPort:  getEnv("SERVER_PORT", "8080"),
         JWTSecret:   getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
         Environment: getEnv("ENVIRONMENT", "development"),
+        WalletWebhookSecrets: getEnvMap("WALLET_WEBHOOK_SECRETS"),
+        BusinessTimezone: getEnv("BUSINESS_TIMEZONE", "Asia/Manila"),
+        ReportWeekStart:  getEnv("REPORT_WEEK_START", "sunday"),
+    }
+
+    // The fake provider (scripts/fake_wallet) is opt-in: it is only accepted outside production
+    // and only once FAKE_WALLET_SECRET is set, so a deploy that forgets ENVIRONMENT stays closed
+    if secret := os.Getenv("FAKE_WALLET_SECRET"); secret != "" && cfg.Environment != "production" {
+        if _, ok := cfg.WalletWebhookSecrets["fake"]; !ok {
+            cfg.WalletWebhookSecrets["fake"] = secret
+        }
     }
+    return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -36,3 +57,16 @@ func getEnvBool(key string, defaultValue bool) bool {
     }
     return defaultValue
 }
+
+// getEnvMap parses "key=value,key=value" pairs; keys are lower-cased
+func getEnvMap(key string) map[string]string {
+    values := make(map[string]string)
+    for _, pair := range strings.Split(os.Getenv(key), ",") {
+        k, v, ok := strings.Cut(pair, "=")
+        if !ok || strings.TrimSpace(k) == "" {
+            continue
+        }
+        values[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+    }
+    return values
+}
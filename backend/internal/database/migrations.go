@@ -29,6 +29,24 @@ func (d *Database) AutoMigrate() error {
     modelsToMigrate := []interface{}{
         &models.Client{}, &models.IncomeInfo{}, &models.Loan{}, &models.Payment{},
         &models.CoMaker{}, &models.FamilyMember{}, &models.Document{}, &models.User{},
+        &models.DocumentTemplate{}, &models.LoanCharge{}, &models.Branch{}, &models.Holiday{},
+        &models.ComplianceSettings{}, &models.ComplianceOverride{}, &models.CashierSession{},
+        &models.WalletNotification{},
+        &models.BankImport{},
+        &models.BankTransaction{},
+        &models.PortfolioSnapshot{},
+        &models.LedgerAccount{},
+        &models.JournalEntry{},
+        &models.JournalLine{},
+        &models.LedgerPeriodClose{},
+        &models.ProvisioningSettings{},
+        &models.ProvisionRun{},
+        &models.ProvisionRunBucket{},
+        &models.InterestAccrualSettings{},
+        &models.AccrualProductMethod{},
+        &models.InterestAccrualRun{},
+        &models.LedgerAccountMapping{},
+        &models.JournalExportBatch{},
     }
 
     // Create tables for each model
@@ -0,0 +1,202 @@
+package export
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "strings"
+    "time"
+
+    "github.com/jung-kurt/gofpdf"
+)
+
+// PDF is a thin document builder over gofpdf for the platform's printed forms and reports
+type PDF struct {
+    pdf       *gofpdf.Fpdf
+    translate func(string) string
+    rowHeight float64
+}
+
+// PDFOptions controls page layout and the footer printed on every page
+type PDFOptions struct {
+    Landscape bool
+    Footer    string    // Printed left of the page number, e.g. branch name
+    Printed   time.Time // Print date shown in the footer; defaults to now
+}
+
+// NewPDF starts a Letter-size document with the given title printed on the first page
+func NewPDF(title string, opts PDFOptions) *PDF {
+    orientation := "P"
+    if opts.Landscape {
+        orientation = "L"
+    }
+
+    pdf := gofpdf.New(orientation, "mm", "Letter", "")
+    pdf.SetTitle(title, true)
+    pdf.SetMargins(15, 15, 15)
+    pdf.SetAutoPageBreak(true, 18)
+
+    d := &PDF{pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor(""), rowHeight: 6}
+
+    printedAt := opts.Printed
+    if printedAt.IsZero() {
+        printedAt = time.Now()
+    }
+    printed := "Printed " + printedAt.Format("Jan 02, 2006 3:04 PM")
+    footer := printed
+    if opts.Footer != "" {
+        footer = opts.Footer + " | " + printed
+    }
+    pdf.SetFooterFunc(func() {
+        pdf.SetY(-12)
+        pdf.SetFont("Helvetica", "I", 8)
+        pdf.CellFormat(0, 5, d.translate(footer), "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 5, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "R", false, 0, "")
+    })
+
+    pdf.AddPage()
+    pdf.SetFont("Helvetica", "B", 14)
+    pdf.CellFormat(0, 8, d.translate(title), "", 1, "C", false, 0, "")
+    pdf.Ln(2)
+    return d
+}
+
+// Heading writes a bold section heading
+func (d *PDF) Heading(text string) {
+    d.pdf.Ln(2)
+    d.pdf.SetFont("Helvetica", "B", 11)
+    d.pdf.CellFormat(0, 6, d.translate(text), "", 1, "L", false, 0, "")
+}
+
+// Paragraph writes wrapped body text; blank lines in text start new paragraphs
+func (d *PDF) Paragraph(text string) {
+    d.pdf.SetFont("Helvetica", "", 10)
+    for _, line := range strings.Split(text, "\n") {
+        if strings.TrimSpace(line) == "" {
+            d.pdf.Ln(3)
+            continue
+        }
+        d.pdf.MultiCell(0, 5, d.translate(line), "", "L", false)
+    }
+}
+
+// KeyValues writes label/value pairs in two columns
+func (d *PDF) KeyValues(pairs [][2]string) {
+    for _, pair := range pairs {
+        d.pdf.SetFont("Helvetica", "", 10)
+        d.pdf.CellFormat(70, 5.5, d.translate(pair[0]), "", 0, "L", false, 0, "")
+        d.pdf.SetFont("Helvetica", "B", 10)
+        d.pdf.CellFormat(0, 5.5, d.translate(pair[1]), "", 1, "L", false, 0, "")
+    }
+}
+
+// SetRowHeight changes the height in mm of table body rows, e.g. to leave room for signatures
+func (d *PDF) SetRowHeight(height float64) {
+    d.rowHeight = height
+}
+
+// Table writes a bordered table. Widths are in mm; numeric columns should be aligned "R".
+// Rows in boldRows (by index) are printed in bold, e.g. totals.
+func (d *PDF) Table(headers []string, widths []float64, aligns []string, rows [][]string, boldRows map[int]bool) {
+    printHeader := func() {
+        d.pdf.SetFont("Helvetica", "B", 9)
+        d.pdf.SetFillColor(230, 230, 230)
+        for i, h := range headers {
+            d.pdf.CellFormat(widths[i], 6, d.translate(h), "1", 0, "C", true, 0, "")
+        }
+        d.pdf.Ln(-1)
+    }
+
+    printHeader()
+    _, pageHeight := d.pdf.GetPageSize()
+    _, _, _, bottom := d.pdf.GetMargins()
+    for r, row := range rows {
+        if d.pdf.GetY()+d.rowHeight > pageHeight-bottom-6 {
+            d.pdf.AddPage()
+            printHeader()
+        }
+        style := ""
+        if boldRows[r] {
+            style = "B"
+        }
+        d.pdf.SetFont("Helvetica", style, 9)
+        for i, cell := range row {
+            align := "L"
+            if i < len(aligns) && aligns[i] != "" {
+                align = aligns[i]
+            }
+            d.pdf.CellFormat(widths[i], d.rowHeight, d.translate(cell), "1", 0, align, false, 0, "")
+        }
+        d.pdf.Ln(-1)
+    }
+}
+
+// SignatureBlocks prints signature lines with the signer's name and role beneath, two per row
+func (d *PDF) SignatureBlocks(blocks [][2]string) {
+    pageWidth, _ := d.pdf.GetPageSize()
+    left, _, right, _ := d.pdf.GetMargins()
+    colWidth := (pageWidth - left - right) / 2
+
+    for i := 0; i < len(blocks); i += 2 {
+        d.pdf.Ln(14)
+        row := blocks[i:min(i+2, len(blocks))]
+
+        d.pdf.SetFont("Helvetica", "B", 10)
+        for _, b := range row {
+            d.pdf.CellFormat(colWidth-10, 5, d.translate(b[0]), "T", 0, "C", false, 0, "")
+            d.pdf.CellFormat(10, 5, "", "", 0, "", false, 0, "")
+        }
+        d.pdf.Ln(-1)
+        d.pdf.SetFont("Helvetica", "", 9)
+        for _, b := range row {
+            d.pdf.CellFormat(colWidth-10, 5, d.translate(b[1]), "", 0, "C", false, 0, "")
+            d.pdf.CellFormat(10, 5, "", "", 0, "", false, 0, "")
+        }
+        d.pdf.Ln(-1)
+    }
+}
+
+// Bytes finalizes the document
+func (d *PDF) Bytes() ([]byte, error) {
+    var buf bytes.Buffer
+    if err := d.pdf.Output(&buf); err != nil {
+        return nil, fmt.Errorf("failed to render PDF: %w", err)
+    }
+    return buf.Bytes(), nil
+}
+
+// Write finalizes the document straight to w
+func (d *PDF) Write(w io.Writer) error {
+    if err := d.pdf.Output(w); err != nil {
+        return fmt.Errorf("failed to render PDF: %w", err)
+    }
+    return nil
+}
+
+// Percent formats a percentage with two decimals, e.g. 12.50%
+func Percent(value float64) string {
+    return fmt.Sprintf("%.2f%%", value)
+}
+
+// Money formats an amount with thousands separators and two decimals, e.g. 12,345.60
+func Money(amount float64) string {
+    negative := amount < 0
+    if negative {
+        amount = -amount
+    }
+    whole := fmt.Sprintf("%.2f", amount)
+    intPart, frac := whole[:len(whole)-3], whole[len(whole)-3:]
+
+    var grouped strings.Builder
+    for i, ch := range intPart {
+        if i > 0 && (len(intPart)-i)%3 == 0 {
+            grouped.WriteByte(',')
+        }
+        grouped.WriteRune(ch)
+    }
+
+    if negative {
+        return "-" + grouped.String() + frac
+    }
+    return grouped.String() + frac
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+    "encoding/csv"
+    "fmt"
+    "io"
+    "time"
+)
+
+// Formats a table can be exported in
+const (
+    FormatCSV  = "csv"
+    FormatXLSX = "xlsx"
+    FormatPDF  = "pdf"
+)
+
+// ContentType is the MIME type for an export format
+func ContentType(format string) string {
+    switch format {
+    case FormatCSV:
+        return "text/csv"
+    case FormatXLSX:
+        return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+    case FormatPDF:
+        return "application/pdf"
+    }
+    return "application/octet-stream"
+}
+
+// MaxPDFRows is the most rows a table is rendered to PDF with. A PDF is laid out whole in memory
+// before any of it is written, so larger exports are only offered as CSV or XLSX, which stream.
+const MaxPDFRows = 5000
+
+// Column describes one column of a tabular export
+type Column struct {
+    Header string
+    Width  float64 // Width in mm for PDF output
+    Align  string  // "L", "C" or "R"
+}
+
+// Group is a titled section of rows, e.g. all borrowers in one area
+type Group struct {
+    Title    string
+    Rows     [][]string
+    Subtotal []string // Optional subtotal row
+}
+
+// Table is a titled grid of already-formatted cells that can be written as CSV or PDF
+type Table struct {
+    Title     string
+    Subtitle  [][2]string // Label/value pairs printed above the grid
+    Columns   []Column
+    Rows      [][]string
+    Totals    []string // Optional totals row
+    Footer    string
+    Printed   time.Time // Print date in the footer; defaults to now
+    Landscape bool
+    RowHeight float64 // PDF body row height in mm; defaults to 6
+
+    // When Groups is set it replaces Rows: the PDF prints one section per group,
+    // and the CSV gains a leading GroupHeader column holding each group's title
+    GroupHeader string
+    Groups      []Group
+}
+
+// Write streams the table to w as CSV, XLSX or PDF
+func (t *Table) Write(w io.Writer, format string) error {
+    switch format {
+    case FormatCSV:
+        return t.WriteCSV(w)
+    case FormatXLSX:
+        return t.WriteXLSX(w)
+    case FormatPDF:
+        return t.WritePDF(w)
+    }
+    return fmt.Errorf("unsupported export format %q", format)
+}
+
+// RowCount is the number of data rows in the table, across all its groups
+func (t *Table) RowCount() int {
+    count := len(t.Rows)
+    for _, group := range t.Groups {
+        count += len(group.Rows)
+    }
+    return count
+}
+
+// FooterText is the footer printed under the table: the table's own footer and the print date
+func (t *Table) FooterText() string {
+    printed := t.Printed
+    if printed.IsZero() {
+        printed = time.Now()
+    }
+    text := "Printed " + printed.Format("Jan 02, 2006 3:04 PM")
+    if t.Footer != "" {
+        text = t.Footer + " | " + text
+    }
+    return text
+}
+
+// WriteCSV writes the table as CSV, including the totals row and a closing footer line
+func (t *Table) WriteCSV(w io.Writer) error {
+    writer := csv.NewWriter(w)
+    grouped := len(t.Groups) > 0
+
+    headers := make([]string, 0, len(t.Columns)+1)
+    if grouped {
+        headers = append(headers, t.GroupHeader)
+    }
+    for _, col := range t.Columns {
+        headers = append(headers, col.Header)
+    }
+    if err := writer.Write(headers); err != nil {
+        return err
+    }
+
+    if grouped {
+        for _, group := range t.Groups {
+            for _, row := range group.Rows {
+                if err := writer.Write(append([]string{group.Title}, row...)); err != nil {
+                    return err
+                }
+            }
+            if group.Subtotal != nil {
+                if err := writer.Write(append([]string{group.Title + " subtotal"}, group.Subtotal...)); err != nil {
+                    return err
+                }
+            }
+        }
+    } else {
+        for _, row := range t.Rows {
+            if err := writer.Write(row); err != nil {
+                return err
+            }
+        }
+    }
+
+    if t.Totals != nil {
+        totals := t.Totals
+        if grouped {
+            totals = append([]string{"Total"}, t.Totals...)
+        }
+        if err := writer.Write(totals); err != nil {
+            return err
+        }
+    }
+    if err := writer.Write(nil); err != nil {
+        return err
+    }
+    if err := writer.Write([]string{t.FooterText()}); err != nil {
+        return err
+    }
+
+    writer.Flush()
+    return writer.Error()
+}
+
+// PDF renders the table as a PDF document
+func (t *Table) PDF() ([]byte, error) {
+    return t.document().Bytes()
+}
+
+// WritePDF renders the table as a PDF document straight to w
+func (t *Table) WritePDF(w io.Writer) error {
+    return t.document().Write(w)
+}
+
+func (t *Table) document() *PDF {
+    doc := NewPDF(t.Title, PDFOptions{Landscape: t.Landscape, Footer: t.Footer, Printed: t.Printed})
+    if len(t.Subtitle) > 0 {
+        doc.KeyValues(t.Subtitle)
+        doc.pdf.Ln(3)
+    }
+    t.writeGrid(doc)
+    return doc
+}
+
+// writeGrid appends the table grid, or one grid per group, to an existing document
+func (t *Table) writeGrid(doc *PDF) {
+    headers := make([]string, len(t.Columns))
+    widths := make([]float64, len(t.Columns))
+    aligns := make([]string, len(t.Columns))
+    for i, col := range t.Columns {
+        headers[i] = col.Header
+        widths[i] = col.Width
+        aligns[i] = col.Align
+    }
+    if t.RowHeight > 0 {
+        doc.SetRowHeight(t.RowHeight)
+    }
+
+    if len(t.Groups) == 0 {
+        rows := t.Rows
+        bold := map[int]bool{}
+        if t.Totals != nil {
+            rows = append(append([][]string{}, t.Rows...), t.Totals)
+            bold[len(rows)-1] = true
+        }
+        doc.Table(headers, widths, aligns, rows, bold)
+        return
+    }
+
+    for _, group := range t.Groups {
+        title := group.Title
+        if t.GroupHeader != "" {
+            title = t.GroupHeader + ": " + group.Title
+        }
+        doc.Heading(title)
+
+        rows := group.Rows
+        bold := map[int]bool{}
+        if group.Subtotal != nil {
+            rows = append(append([][]string{}, group.Rows...), group.Subtotal)
+            bold[len(rows)-1] = true
+        }
+        doc.Table(headers, widths, aligns, rows, bold)
+    }
+    if t.Totals != nil {
+        doc.pdf.Ln(3)
+        doc.Table(headers, widths, aligns, [][]string{t.Totals}, map[int]bool{0: true})
+    }
+}
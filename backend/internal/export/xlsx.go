@@ -0,0 +1,239 @@
+package export
+
+import (
+    "archive/zip"
+    "bufio"
+    "encoding/xml"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+)
+
+// Cell styles, by index into cellXfs in xlsxStyles
+const (
+    xlsxStyleText = iota
+    xlsxStyleMoney
+    xlsxStylePercent
+    xlsxStyleCount
+    xlsxStyleBoldText
+    xlsxStyleBoldMoney
+    xlsxStyleBoldPercent
+    xlsxStyleBoldCount
+)
+
+// WriteXLSX writes the table as a single-sheet Excel workbook. Rows are streamed into the zip as
+// they are written, so large tables never sit in memory twice. Cells in right-aligned columns that
+// hold a formatted number (12,345.60, 45.00% or 12) are written as numbers with a matching format.
+func (t *Table) WriteXLSX(w io.Writer) error {
+    zw := zip.NewWriter(w)
+    parts := []struct{ name, body string }{
+        {"[Content_Types].xml", xlsxContentTypes},
+        {"_rels/.rels", xlsxRootRels},
+        {"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlEscape(sheetName(t.Title)))},
+        {"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+        {"xl/styles.xml", xlsxStyles},
+    }
+    for _, part := range parts {
+        f, err := zw.Create(part.name)
+        if err != nil {
+            return err
+        }
+        if _, err := io.WriteString(f, part.body); err != nil {
+            return err
+        }
+    }
+
+    f, err := zw.Create("xl/worksheets/sheet1.xml")
+    if err != nil {
+        return err
+    }
+    sheet := &xlsxSheet{w: bufio.NewWriter(f)}
+    t.writeSheet(sheet)
+    if sheet.err != nil {
+        return sheet.err
+    }
+    if err := sheet.w.Flush(); err != nil {
+        return err
+    }
+    return zw.Close()
+}
+
+// writeSheet lays the table out as title, subtitle pairs, header, rows, totals and footer
+func (t *Table) writeSheet(s *xlsxSheet) {
+    grouped := len(t.Groups) > 0
+    offset := 0
+    if grouped {
+        offset = 1
+    }
+
+    s.printf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+    s.printf(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cols>`)
+    if grouped {
+        s.printf(`<col min="1" max="1" width="24" customWidth="1"/>`)
+    }
+    for i, col := range t.Columns {
+        width := col.Width/2 + 2
+        if width < 8 {
+            width = 8
+        }
+        s.printf(`<col min="%d" max="%d" width="%.1f" customWidth="1"/>`, i+offset+1, i+offset+1, width)
+    }
+    s.printf(`</cols><sheetData>`)
+
+    s.row([]string{t.Title}, nil, true)
+    for _, pair := range t.Subtitle {
+        s.row([]string{pair[0], pair[1]}, nil, false)
+    }
+    if t.Title != "" || len(t.Subtitle) > 0 {
+        s.row(nil, nil, false)
+    }
+
+    headers := make([]string, 0, len(t.Columns)+offset)
+    aligns := make([]string, 0, len(t.Columns)+offset)
+    if grouped {
+        headers = append(headers, t.GroupHeader)
+        aligns = append(aligns, "L")
+    }
+    for _, col := range t.Columns {
+        headers = append(headers, col.Header)
+        aligns = append(aligns, col.Align)
+    }
+    s.row(headers, nil, true)
+
+    if grouped {
+        for _, group := range t.Groups {
+            for _, row := range group.Rows {
+                s.row(append([]string{group.Title}, row...), aligns, false)
+            }
+            if group.Subtotal != nil {
+                s.row(append([]string{group.Title + " subtotal"}, group.Subtotal...), aligns, true)
+            }
+        }
+    } else {
+        for _, row := range t.Rows {
+            s.row(row, aligns, false)
+        }
+    }
+    if t.Totals != nil {
+        totals := t.Totals
+        if grouped {
+            totals = append([]string{"Total"}, t.Totals...)
+        }
+        s.row(totals, aligns, true)
+    }
+
+    s.row(nil, nil, false)
+    s.row([]string{t.FooterText()}, nil, false)
+    s.printf(`</sheetData></worksheet>`)
+}
+
+// xlsxSheet writes worksheet XML, remembering the first error so callers can check once at the end
+type xlsxSheet struct {
+    w    *bufio.Writer
+    rows int
+    err  error
+}
+
+func (s *xlsxSheet) printf(format string, args ...interface{}) {
+    if s.err == nil {
+        _, s.err = fmt.Fprintf(s.w, format, args...)
+    }
+}
+
+// row writes one row; cells in "R" aligned columns are written as numbers when they parse as one
+func (s *xlsxSheet) row(cells []string, aligns []string, bold bool) {
+    s.rows++
+    s.printf(`<row r="%d">`, s.rows)
+    for i, cell := range cells {
+        if cell == "" {
+            continue
+        }
+        ref := columnName(i) + strconv.Itoa(s.rows)
+        if i < len(aligns) && aligns[i] == "R" {
+            if value, style, ok := xlsxNumber(cell); ok {
+                if bold {
+                    style += xlsxStyleBoldText
+                }
+                s.printf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, style, value)
+                continue
+            }
+        }
+        style := xlsxStyleText
+        if bold {
+            style = xlsxStyleBoldText
+        }
+        s.printf(`<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xmlEscape(cell))
+    }
+    s.printf(`</row>`)
+}
+
+// xlsxNumber reads a cell formatted by Money, Percent or strconv.Itoa back into a number and its style
+func xlsxNumber(cell string) (string, int, bool) {
+    text := strings.ReplaceAll(strings.TrimSpace(cell), ",", "")
+    style := xlsxStyleCount
+    if strings.HasSuffix(text, "%") {
+        text = strings.TrimSuffix(text, "%")
+        style = xlsxStylePercent
+    } else if strings.Contains(text, ".") {
+        style = xlsxStyleMoney
+    }
+
+    value, err := strconv.ParseFloat(text, 64)
+    if err != nil {
+        return "", 0, false
+    }
+    if style == xlsxStylePercent {
+        value /= 100
+    }
+    return strconv.FormatFloat(value, 'f', -1, 64), style, true
+}
+
+// columnName turns a zero-based column index into its spreadsheet letters: A, B, ... Z, AA
+func columnName(index int) string {
+    name := ""
+    for index >= 0 {
+        name = string(rune('A'+index%26)) + name
+        index = index/26 - 1
+    }
+    return name
+}
+
+// sheetName trims a title to what Excel accepts as a sheet name
+func sheetName(title string) string {
+    name := strings.Map(func(r rune) rune {
+        if strings.ContainsRune(`[]:*?/\`, r) {
+            return '-'
+        }
+        return r
+    }, title)
+    if name == "" {
+        name = "Report"
+    }
+    if len(name) > 31 {
+        name = name[:31]
+    }
+    return name
+}
+
+func xmlEscape(s string) string {
+    var b strings.Builder
+    xml.EscapeText(&b, []byte(s))
+    return b.String()
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+// Styles in the order of the xlsxStyle constants: text, money, percent and count, then the same in bold
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="1"><numFmt numFmtId="164" formatCode="#,##0.00"/></numFmts><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="8"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="10" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="164" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/><xf numFmtId="10" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/><xf numFmtId="3" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/></cellXfs></styleSheet>`
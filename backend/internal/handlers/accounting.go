@@ -0,0 +1,647 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+    "time"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "micro-lending-platform/backend/internal/services"
+    "github.com/gin-gonic/gin"
+)
+
+type AccountingHandler struct {
+    ledgerService       *services.LedgerService
+    provisioningService *services.ProvisioningService
+    accrualService      *services.AccrualService
+    exportService       *services.JournalExportService
+}
+
+func NewAccountingHandler(ledgerService *services.LedgerService, provisioningService *services.ProvisioningService, accrualService *services.AccrualService, exportService *services.JournalExportService) *AccountingHandler {
+    return &AccountingHandler{
+        ledgerService:       ledgerService,
+        provisioningService: provisioningService,
+        accrualService:      accrualService,
+        exportService:       exportService,
+    }
+}
+
+// GetAccounts returns the chart of accounts
+func (h *AccountingHandler) GetAccounts(c *gin.Context) {
+    accounts, err := h.ledgerService.GetAccounts()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accounts"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "accounts": accounts,
+        "total":    len(accounts),
+    })
+}
+
+// CreateAccount adds an account to the chart
+func (h *AccountingHandler) CreateAccount(c *gin.Context) {
+    var req models.LedgerAccountRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    account, err := h.ledgerService.CreateAccount(&req)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Account created successfully",
+        "account": account,
+    })
+}
+
+// GetEntries lists journal entries, optionally for a range of days, a source or a loan
+func (h *AccountingHandler) GetEntries(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+    filter := &models.JournalEntryFilter{Source: c.Query("source")}
+    if from := c.Query("from"); from != "" {
+        day, err := time.Parse(period.DateLayout, from)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date, use YYYY-MM-DD"})
+            return
+        }
+        filter.From = &day
+    }
+    if to := c.Query("to"); to != "" {
+        day, err := time.Parse(period.DateLayout, to)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date, use YYYY-MM-DD"})
+            return
+        }
+        next := day.AddDate(0, 0, 1)
+        filter.To = &next
+    }
+    if loanIDStr := c.Query("loan_id"); loanIDStr != "" {
+        loanID, err := strconv.ParseUint(loanIDStr, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+            return
+        }
+        id := uint(loanID)
+        filter.LoanID = &id
+    }
+
+    entries, total, err := h.ledgerService.GetEntries(filter, page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch journal entries"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "entries": entries,
+        "total":   total,
+        "page":    page,
+        "limit":   limit,
+    })
+}
+
+// GetEntry returns one journal entry with its lines
+func (h *AccountingHandler) GetEntry(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid journal entry ID"})
+        return
+    }
+
+    entry, err := h.ledgerService.GetEntry(uint(id))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, entry)
+}
+
+// CreateEntry posts a manual journal entry
+func (h *AccountingHandler) CreateEntry(c *gin.Context) {
+    var req models.JournalEntryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    entry, err := h.ledgerService.CreateManualEntry(&req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Journal entry posted successfully",
+        "entry":   entry,
+    })
+}
+
+// ReverseEntry posts the opposite of a manual journal entry
+func (h *AccountingHandler) ReverseEntry(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid journal entry ID"})
+        return
+    }
+
+    var req models.JournalReversalRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    reversal, err := h.ledgerService.ReverseEntry(uint(id), &req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Journal entry reversed successfully",
+        "entry":   reversal,
+    })
+}
+
+// SyncLedger posts every loan event the ledger has not seen yet
+func (h *AccountingHandler) SyncLedger(c *gin.Context) {
+    result, err := h.ledgerService.SyncAll()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
+
+// GetTrialBalance returns every account's balance at the end of ?as_of= (default today),
+// tied out to the loans' outstanding balances for the current position
+func (h *AccountingHandler) GetTrialBalance(c *gin.Context) {
+    format, ok := reportFormat(c)
+    if !ok {
+        return
+    }
+    asOf, ok := h.ledgerAsOf(c)
+    if !ok {
+        return
+    }
+
+    report, err := h.ledgerService.GetTrialBalance(asOf)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+
+    if format != "json" {
+        writeReport(c, h.ledgerService.TrialBalanceTable(report), format, "trial-balance-"+report.AsOf)
+        return
+    }
+    c.JSON(http.StatusOK, report)
+}
+
+// GetIncomeStatement returns income and expenses for ?from= to ?to=, default this month
+func (h *AccountingHandler) GetIncomeStatement(c *gin.Context) {
+    format, ok := reportFormat(c)
+    if !ok {
+        return
+    }
+    opts, ok := h.ledgerOptions(c)
+    if !ok {
+        return
+    }
+    r, err := period.Parse(c.Query("from"), c.Query("to"), period.Month(time.Now(), opts), opts)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    report, err := h.ledgerService.GetIncomeStatement(r)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+
+    if format != "json" {
+        writeReport(c, h.ledgerService.IncomeStatementTable(report), format, "income-statement-"+report.From+"-to-"+report.To)
+        return
+    }
+    c.JSON(http.StatusOK, report)
+}
+
+// GetBalanceSheet returns assets, liabilities and equity at the end of ?as_of= (default today)
+func (h *AccountingHandler) GetBalanceSheet(c *gin.Context) {
+    format, ok := reportFormat(c)
+    if !ok {
+        return
+    }
+    asOf, ok := h.ledgerAsOf(c)
+    if !ok {
+        return
+    }
+
+    report, err := h.ledgerService.GetBalanceSheet(asOf)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+
+    if format != "json" {
+        writeReport(c, h.ledgerService.BalanceSheetTable(report), format, "balance-sheet-"+report.AsOf)
+        return
+    }
+    c.JSON(http.StatusOK, report)
+}
+
+// GetPeriods lists closed periods, including reopened ones
+func (h *AccountingHandler) GetPeriods(c *gin.Context) {
+    closes, err := h.ledgerService.GetPeriodCloses()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch closed periods"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "periods": closes,
+        "total":   len(closes),
+    })
+}
+
+// ClosePeriod locks the books for a month that has ended
+func (h *AccountingHandler) ClosePeriod(c *gin.Context) {
+    var req models.LedgerPeriodCloseRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    periodClose, err := h.ledgerService.ClosePeriod(&req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Period closed successfully",
+        "period":  periodClose,
+    })
+}
+
+// ReopenPeriod reopens the most recently closed month
+func (h *AccountingHandler) ReopenPeriod(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+        return
+    }
+
+    var req models.LedgerPeriodReopenRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    periodClose, err := h.ledgerService.ReopenPeriod(uint(id), &req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Period reopened successfully",
+        "period":  periodClose,
+    })
+}
+
+// GetProvisioningSettings returns the provisioning rate for each days-past-due bucket
+func (h *AccountingHandler) GetProvisioningSettings(c *gin.Context) {
+    settings, err := h.provisioningService.GetSettings()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch provisioning settings"})
+        return
+    }
+
+    c.JSON(http.StatusOK, settings)
+}
+
+// UpdateProvisioningSettings changes the provisioning rates
+func (h *AccountingHandler) UpdateProvisioningSettings(c *gin.Context) {
+    var req models.ProvisioningSettingsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    settings, err := h.provisioningService.UpdateSettings(&req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Provisioning settings updated successfully",
+        "settings": settings,
+    })
+}
+
+// RunProvisioning computes the month-end allowance for loan losses and posts the adjustment
+func (h *AccountingHandler) RunProvisioning(c *gin.Context) {
+    var req models.ProvisionRunRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    run, err := h.provisioningService.RunProvisioning(req.Month, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Provisioning run completed successfully",
+        "run":     run,
+    })
+}
+
+// GetProvisionRuns lists provisioning runs
+func (h *AccountingHandler) GetProvisionRuns(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    runs, total, err := h.provisioningService.GetRuns(page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch provisioning runs"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "runs":  runs,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// GetProvisionMovement returns the allowance's movement for each month from ?from= to ?to=,
+// default the last six months
+func (h *AccountingHandler) GetProvisionMovement(c *gin.Context) {
+    format, ok := reportFormat(c)
+    if !ok {
+        return
+    }
+    opts, ok := h.ledgerOptions(c)
+    if !ok {
+        return
+    }
+    months := period.Months(time.Now(), 6, opts)
+    fallback := period.Between(months[len(months)-1].FirstDay(), months[0].LastDay(), opts)
+    r, err := period.Parse(c.Query("from"), c.Query("to"), fallback, opts)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    report, err := h.provisioningService.GetMovement(r)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+
+    if format != "json" {
+        writeReport(c, h.provisioningService.MovementTable(report), format, "loan-loss-allowance-"+report.From+"-to-"+report.To)
+        return
+    }
+    c.JSON(http.StatusOK, report)
+}
+
+// GetAccrualSettings returns the interest accrual methods and the non-performing threshold
+func (h *AccountingHandler) GetAccrualSettings(c *gin.Context) {
+    settings, err := h.accrualService.GetSettings()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accrual settings"})
+        return
+    }
+
+    c.JSON(http.StatusOK, settings)
+}
+
+// UpdateAccrualSettings changes the interest accrual methods
+func (h *AccountingHandler) UpdateAccrualSettings(c *gin.Context) {
+    var req models.InterestAccrualSettingsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    settings, err := h.accrualService.UpdateSettings(&req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Accrual settings updated successfully",
+        "settings": settings,
+    })
+}
+
+// RunAccruals recognizes the interest earned through a month-end
+func (h *AccountingHandler) RunAccruals(c *gin.Context) {
+    var req models.InterestAccrualRunRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    run, err := h.accrualService.RunAccruals(req.Month, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Accrual run completed successfully",
+        "run":     run,
+    })
+}
+
+// GetAccrualRuns lists interest accrual runs
+func (h *AccountingHandler) GetAccrualRuns(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    runs, total, err := h.accrualService.GetRuns(page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accrual runs"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "runs":  runs,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// GetExportMappings returns the external code and name set for each account
+func (h *AccountingHandler) GetExportMappings(c *gin.Context) {
+    mappings, err := h.exportService.GetMappings()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch account mapping"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"mappings": mappings})
+}
+
+// UpdateExportMappings replaces the account mapping used by exports
+func (h *AccountingHandler) UpdateExportMappings(c *gin.Context) {
+    var req models.LedgerAccountMappingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    mappings, err := h.exportService.UpdateMappings(&req)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Account mapping updated successfully",
+        "mappings": mappings,
+    })
+}
+
+// CreateExport exports the journal entries dated in a range that were not exported before
+func (h *AccountingHandler) CreateExport(c *gin.Context) {
+    var req models.JournalExportRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    opts, ok := h.ledgerOptions(c)
+    if !ok {
+        return
+    }
+    r, err := period.Parse(req.From, req.To, period.Today(opts), opts)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    batch, err := h.exportService.Export(r, req.Format, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Journal entries exported successfully",
+        "batch":   batch,
+    })
+}
+
+// GetExports lists export batches
+func (h *AccountingHandler) GetExports(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    batches, total, err := h.exportService.GetBatches(page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch export batches"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "batches": batches,
+        "total":   total,
+        "page":    page,
+        "limit":   limit,
+    })
+}
+
+// DownloadExport returns the file an export batch produced
+func (h *AccountingHandler) DownloadExport(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export batch ID"})
+        return
+    }
+
+    batch, err := h.exportService.GetBatch(uint(id))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+
+    filename, contentType := services.JournalExportFile(batch)
+    c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+    c.Data(http.StatusOK, contentType, []byte(batch.Content))
+}
+
+// VoidExport voids a batch that was not imported so its entries are exported again
+func (h *AccountingHandler) VoidExport(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export batch ID"})
+        return
+    }
+    var req models.JournalExportVoidRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    batch, err := h.exportService.VoidBatch(uint(id), &req, c.GetString("username"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Export batch voided successfully",
+        "batch":   batch,
+    })
+}
+
+// ledgerOptions reads ?tz= over the configured business timezone
+func (h *AccountingHandler) ledgerOptions(c *gin.Context) (period.Options, bool) {
+    opts, err := h.ledgerService.PeriodOptions().WithOverrides(c.Query("tz"), "")
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return opts, false
+    }
+    return opts, true
+}
+
+// ledgerAsOf reads the ?as_of= day (or ?to=), defaulting to today in the business timezone
+func (h *AccountingHandler) ledgerAsOf(c *gin.Context) (period.Range, bool) {
+    opts, ok := h.ledgerOptions(c)
+    if !ok {
+        return period.Range{}, false
+    }
+    asOfStr := c.Query("as_of")
+    if asOfStr == "" {
+        asOfStr = c.Query("to")
+    }
+    if asOfStr == "" {
+        return period.Today(opts), true
+    }
+    asOf, err := time.Parse(period.DateLayout, asOfStr)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of date, use YYYY-MM-DD"})
+        return period.Range{}, false
+    }
+    return period.Between(asOf, asOf, opts), true
+}
@@ -74,3 +74,22 @@ func (h *AuthHandler) Logout(c *gin.Context) {
     c.JSON(501, gin.H{"message": "Logout - coming soon"})
 }
 
+// currentUser returns the signed-in user's ID and whether they are an admin
+func currentUser(c *gin.Context) (uint, bool) {
+    var userID uint
+    if id, ok := c.Get("user_id"); ok {
+        userID, _ = id.(uint)
+    }
+    isAdmin, _ := c.Get("is_admin")
+    admin, _ := isAdmin.(bool)
+    return userID, admin
+}
+
+// signedInUserID returns the signed-in user's ID for stamping records, or nil if unknown
+func signedInUserID(c *gin.Context) *uint {
+    userID, _ := currentUser(c)
+    if userID == 0 {
+        return nil
+    }
+    return &userID
+}
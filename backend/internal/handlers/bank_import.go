@@ -0,0 +1,197 @@
+package handlers
+
+import (
+    "encoding/json"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+// maxBankStatementSize caps the size of an uploaded statement
+const maxBankStatementSize = 5 << 20
+
+type BankImportHandler struct {
+    bankImportService *services.BankImportService
+}
+
+func NewBankImportHandler(bankImportService *services.BankImportService) *BankImportHandler {
+    return &BankImportHandler{bankImportService: bankImportService}
+}
+
+// ImportStatement uploads a bank statement CSV as multipart "file", with its column
+// mapping as JSON in the "mapping" form field
+func (h *BankImportHandler) ImportStatement(c *gin.Context) {
+    fileHeader, err := c.FormFile("file")
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Statement file is required"})
+        return
+    }
+    if fileHeader.Size > maxBankStatementSize {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Statement file is too large"})
+        return
+    }
+
+    var mapping models.BankColumnMapping
+    if err := json.Unmarshal([]byte(c.PostForm("mapping")), &mapping); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid column mapping",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    file, err := fileHeader.Open()
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read statement file"})
+        return
+    }
+    defer file.Close()
+
+    bankImport, err := h.bankImportService.ImportStatement(fileHeader.Filename, file, &mapping, c.GetString("username"), signedInUserID(c))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to import statement",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Statement imported successfully",
+        "import":  bankImport,
+    })
+}
+
+// GetImports lists statement imports
+func (h *BankImportHandler) GetImports(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    imports, total, err := h.bankImportService.GetImports(page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch imports"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "imports": imports,
+        "total":   total,
+        "page":    page,
+        "limit":   limit,
+    })
+}
+
+// GetImport returns an import with every statement line and its match result
+func (h *BankImportHandler) GetImport(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import ID"})
+        return
+    }
+
+    bankImport, err := h.bankImportService.GetImport(uint(id))
+    if err != nil {
+        if err.Error() == "import not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"import": bankImport})
+}
+
+// GetReviewQueue lists statement lines waiting for review, or those with ?status=
+func (h *BankImportHandler) GetReviewQueue(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    txns, total, err := h.bankImportService.GetReviewQueue(c.Query("status"), page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "transactions": txns,
+        "total":        total,
+        "page":         page,
+        "limit":        limit,
+    })
+}
+
+// ConfirmTransaction posts a queued statement line to a loan
+func (h *BankImportHandler) ConfirmTransaction(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+        return
+    }
+
+    var req models.BankTransactionConfirmRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    txn, err := h.bankImportService.ConfirmTransaction(uint(id), &req, c.GetString("username"), signedInUserID(c))
+    if err != nil {
+        if err.Error() == "transaction not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to confirm transaction",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":     "Transaction posted successfully",
+        "transaction": txn,
+    })
+}
+
+// IgnoreTransaction closes a queued statement line without posting it
+func (h *BankImportHandler) IgnoreTransaction(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+        return
+    }
+
+    var req models.BankTransactionIgnoreRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    txn, err := h.bankImportService.IgnoreTransaction(uint(id), &req, c.GetString("username"))
+    if err != nil {
+        if err.Error() == "transaction not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to ignore transaction",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":     "Transaction ignored",
+        "transaction": txn,
+    })
+}
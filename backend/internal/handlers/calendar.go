@@ -0,0 +1,198 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+    calendarService *services.CalendarService
+}
+
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+    return &CalendarHandler{calendarService: calendarService}
+}
+
+// GetBranches lists all branches
+func (h *CalendarHandler) GetBranches(c *gin.Context) {
+    branches, err := h.calendarService.GetBranches()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch branches"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "branches": branches,
+        "total":    len(branches),
+    })
+}
+
+// CreateBranch adds a branch
+func (h *CalendarHandler) CreateBranch(c *gin.Context) {
+    var req models.BranchRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    branch, err := h.calendarService.CreateBranch(&req)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Branch created successfully",
+        "branch":  branch,
+    })
+}
+
+// UpdateBranch changes a branch
+func (h *CalendarHandler) UpdateBranch(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+        return
+    }
+
+    var req models.BranchRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    branch, err := h.calendarService.UpdateBranch(uint(id), &req)
+    if err != nil {
+        if err.Error() == "branch not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Branch updated successfully",
+        "branch":  branch,
+    })
+}
+
+// DeleteBranch removes a branch
+func (h *CalendarHandler) DeleteBranch(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+        return
+    }
+
+    if err := h.calendarService.DeleteBranch(uint(id)); err != nil {
+        if err.Error() == "branch not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete branch"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Branch deleted successfully"})
+}
+
+// GetHolidays lists holidays; ?branch_id= limits the list to one branch's own holidays
+func (h *CalendarHandler) GetHolidays(c *gin.Context) {
+    var branchID *uint
+    if branchStr := c.Query("branch_id"); branchStr != "" {
+        id, err := strconv.ParseUint(branchStr, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+            return
+        }
+        value := uint(id)
+        branchID = &value
+    }
+
+    holidays, err := h.calendarService.GetHolidays(branchID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holidays"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "holidays": holidays,
+        "total":    len(holidays),
+    })
+}
+
+// CreateHoliday adds a holiday or work suspension day
+func (h *CalendarHandler) CreateHoliday(c *gin.Context) {
+    var req models.HolidayRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    username, _ := c.Get("username")
+    holiday, err := h.calendarService.CreateHoliday(&req, fmt.Sprint(username))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Holiday created successfully",
+        "holiday": holiday,
+    })
+}
+
+// UpdateHoliday changes a holiday
+func (h *CalendarHandler) UpdateHoliday(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+        return
+    }
+
+    var req models.HolidayRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    holiday, err := h.calendarService.UpdateHoliday(uint(id), &req)
+    if err != nil {
+        if err.Error() == "holiday not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Holiday updated successfully",
+        "holiday": holiday,
+    })
+}
+
+// DeleteHoliday removes a holiday
+func (h *CalendarHandler) DeleteHoliday(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+        return
+    }
+
+    if err := h.calendarService.DeleteHoliday(uint(id)); err != nil {
+        if err.Error() == "holiday not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete holiday"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted successfully"})
+}
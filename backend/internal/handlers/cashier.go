@@ -0,0 +1,236 @@
+package handlers
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type CashierHandler struct {
+    cashierService *services.CashierService
+}
+
+func NewCashierHandler(cashierService *services.CashierService) *CashierHandler {
+    return &CashierHandler{cashierService: cashierService}
+}
+
+// OpenSession starts the signed-in cashier's drawer session
+func (h *CashierHandler) OpenSession(c *gin.Context) {
+    var req models.CashierSessionOpenRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    userID, _ := currentUser(c)
+    session, err := h.cashierService.OpenSession(userID, c.GetString("username"), &req)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to open session",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Session opened successfully",
+        "session": session,
+    })
+}
+
+// GetCurrentSession returns the signed-in cashier's open session with running totals
+func (h *CashierHandler) GetCurrentSession(c *gin.Context) {
+    userID, _ := currentUser(c)
+    session, err := h.cashierService.GetCurrentSession(userID)
+    if err != nil {
+        if err.Error() == "no open session" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "No open session"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session"})
+        return
+    }
+
+    c.JSON(http.StatusOK, session)
+}
+
+// GetSession returns one session; cashiers may only see their own
+func (h *CashierHandler) GetSession(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+        return
+    }
+
+    userID, isAdmin := currentUser(c)
+    session, err := h.cashierService.GetSession(uint(id), userID, isAdmin)
+    if err != nil {
+        if err.Error() == "session not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session"})
+        return
+    }
+
+    c.JSON(http.StatusOK, session)
+}
+
+// GetSessions lists sessions. Admins see every cashier and may filter by
+// user_id, branch_id, status and flagged; cashiers see only their own.
+func (h *CashierHandler) GetSessions(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    filter := models.CashierSessionFilter{
+        Status:  c.Query("status"),
+        Flagged: c.Query("flagged") == "true",
+    }
+    if branchStr := c.Query("branch_id"); branchStr != "" {
+        branchID, err := strconv.ParseUint(branchStr, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+            return
+        }
+        id := uint(branchID)
+        filter.BranchID = &id
+    }
+
+    userID, isAdmin := currentUser(c)
+    if userStr := c.Query("user_id"); userStr != "" {
+        filterUser, err := strconv.ParseUint(userStr, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+            return
+        }
+        id := uint(filterUser)
+        filter.UserID = &id
+    }
+
+    sessions, total, err := h.cashierService.GetSessions(filter, userID, isAdmin, page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "sessions": sessions,
+        "total":    total,
+        "page":     page,
+        "limit":    limit,
+    })
+}
+
+// GetFlaggedSessions lists closed sessions with a cash variance awaiting the branch manager's review
+func (h *CashierHandler) GetFlaggedSessions(c *gin.Context) {
+    filter := models.CashierSessionFilter{
+        Status:  models.CashierSessionClosed,
+        Flagged: true,
+    }
+    if branchStr := c.Query("branch_id"); branchStr != "" {
+        branchID, err := strconv.ParseUint(branchStr, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+            return
+        }
+        id := uint(branchID)
+        filter.BranchID = &id
+    }
+
+    userID, isAdmin := currentUser(c)
+    sessions, total, err := h.cashierService.GetSessions(filter, userID, isAdmin, 1, 100)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "sessions": sessions,
+        "total":    total,
+    })
+}
+
+// CloseSession records the end-of-day count for a session
+func (h *CashierHandler) CloseSession(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+        return
+    }
+
+    var req models.CashierSessionCloseRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    userID, isAdmin := currentUser(c)
+    session, err := h.cashierService.CloseSession(uint(id), userID, isAdmin, &req)
+    if err != nil {
+        switch err.Error() {
+        case "session not found", "session belongs to another cashier":
+            c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+        default:
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error":   "Failed to close session",
+                "details": err.Error(),
+            })
+        }
+        return
+    }
+
+    message := "Session closed successfully"
+    if session.Flagged {
+        message = fmt.Sprintf("Session closed with a variance of %.2f; flagged for review", session.Variance)
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "message": message,
+        "session": session,
+    })
+}
+
+// ReviewSession records the branch manager's review of a closed session (admin only)
+func (h *CashierHandler) ReviewSession(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+        return
+    }
+
+    var req models.CashierSessionReviewRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    session, err := h.cashierService.ReviewSession(uint(id), c.GetString("username"), &req)
+    if err != nil {
+        if err.Error() == "session not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to review session",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Session reviewed successfully",
+        "session": session,
+    })
+}
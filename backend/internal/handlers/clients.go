@@ -1,6 +1,7 @@
 package handlers
 
 import (
+    "errors"
     "fmt"
     "net/http"
     "strconv"
@@ -12,13 +13,15 @@ import (
 type ClientHandler struct {
     clientService *services.ClientService
     loanService   *services.LoanService  // Add loanService for payment management
+    statementService *services.StatementService
 }
 
 // Update constructor to include loanService
-func NewClientHandler(clientService *services.ClientService, loanService *services.LoanService) *ClientHandler {
+func NewClientHandler(clientService *services.ClientService, loanService *services.LoanService, statementService *services.StatementService) *ClientHandler {
     return &ClientHandler{
-        clientService: clientService,
-        loanService:   loanService,
+        clientService:    clientService,
+        loanService:      loanService,
+        statementService: statementService,
     }
 }
 
@@ -69,8 +72,22 @@ func (h *ClientHandler) CreateClient(c *gin.Context) {
         return
     }
 
+    if req.Loan != nil {
+        if !authorizeComplianceOverride(c, req.Loan.ComplianceOverride) {
+            return
+        }
+        req.Loan.ReleasedByID = signedInUserID(c)
+    }
+
     createdClientData, err := h.clientService.CreateClientWithRelatedData(&req)
     if err != nil {
+        if errors.Is(err, services.ErrComplianceCapExceeded) {
+            c.JSON(http.StatusUnprocessableEntity, gin.H{
+                "error":   "Loan exceeds compliance caps",
+                "details": err.Error(),
+            })
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to create client", 
             "details": err.Error(),
@@ -149,6 +166,7 @@ func (h *ClientHandler) GetClientByID(c *gin.Context) {
         return
     }
 
+    setVersionETag(c, client.Version)
     c.JSON(http.StatusOK, client)
 }
 
@@ -170,6 +188,58 @@ func (h *ClientHandler) GetClientWithDetails(c *gin.Context) {
     c.JSON(http.StatusOK, clientData)
 }
 
+// GetClientStatement returns a client's statement of account as JSON, CSV or PDF
+func (h *ClientHandler) GetClientStatement(c *gin.Context) {
+    idStr := c.Param("id")
+    id, err := strconv.ParseUint(idStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+        return
+    }
+
+    format := c.DefaultQuery("format", "json")
+    if format != "json" && format != "csv" && format != "pdf" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be json, csv or pdf"})
+        return
+    }
+
+    statement, err := h.statementService.GetClientStatement(uint(id), c.Query("from"), c.Query("to"))
+    if err != nil {
+        if err.Error() == "client not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to generate statement",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    filename := fmt.Sprintf("statement-%s-%s", statement.ControlNumber, statement.To)
+    switch format {
+    case "csv":
+        c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+        c.Header("Content-Type", "text/csv")
+        if err := h.statementService.StatementTable(statement).WriteCSV(c.Writer); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write statement"})
+        }
+    case "pdf":
+        pdf, err := h.statementService.StatementTable(statement).PDF()
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "Failed to generate statement",
+                "details": err.Error(),
+            })
+            return
+        }
+        c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, filename))
+        c.Data(http.StatusOK, "application/pdf", pdf)
+    default:
+        c.JSON(http.StatusOK, statement)
+    }
+}
+
 // UpdateClient updates client information
 func (h *ClientHandler) UpdateClient(c *gin.Context) {
     idStr := c.Param("id")
@@ -179,19 +249,44 @@ func (h *ClientHandler) UpdateClient(c *gin.Context) {
         return
     }
 
-    var client models.Client
-    if err := c.ShouldBindJSON(&client); err != nil {
+    // The request is applied over the client as stored, so fields it leaves out keep their values
+    client, err := h.clientService.GetClientByID(uint(id))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+        return
+    }
+    client.Version = 0
+    if err := c.ShouldBindJSON(client); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
         return
     }
 
+    // If-Match takes precedence over a version in the body
+    version, err := ifMatchVersion(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if version != 0 {
+        client.Version = version
+    }
+
     client.ID = uint(id)
-    updatedClient, err := h.clientService.UpdateClient(&client)
+    updatedClient, err := h.clientService.UpdateClient(client)
     if err != nil {
+        if errors.Is(err, services.ErrVersionRequired) {
+            respondVersionRequired(c, "Client")
+            return
+        }
+        if errors.Is(err, services.ErrVersionConflict) {
+            respondVersionConflict(c, "Client")
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update client"})
         return
     }
 
+    setVersionETag(c, updatedClient.Version)
     c.JSON(http.StatusOK, gin.H{
         "message": "Client updated successfully",
         "client":  updatedClient,
@@ -292,6 +387,9 @@ func (h *ClientHandler) BulkCreateClients(c *gin.Context) {
     var errors []string
 
     for i, req := range requests {
+        if req.Loan != nil {
+            req.Loan.ReleasedByID = signedInUserID(c)
+        }
         clientData, err := h.clientService.CreateClientWithRelatedData(&req)
         if err != nil {
             errors = append(errors, fmt.Sprintf("Client %d: %s", i+1, err.Error()))
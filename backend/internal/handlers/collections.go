@@ -0,0 +1,106 @@
+package handlers
+
+import (
+    "errors"
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+type CollectionHandler struct {
+    collectionService *services.CollectionService
+}
+
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+    return &CollectionHandler{collectionService: collectionService}
+}
+
+// GetCollectionSheet returns a collector's daily collection sheet as JSON, CSV or PDF.
+// Without a collector parameter the sheet is for the logged-in user.
+func (h *CollectionHandler) GetCollectionSheet(c *gin.Context) {
+    format := c.DefaultQuery("format", "json")
+    if format != "json" && format != "csv" && format != "pdf" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be json, csv or pdf"})
+        return
+    }
+
+    collector := c.Query("collector")
+    if collector == "" {
+        collector = c.GetString("username")
+    }
+
+    sheet, err := h.collectionService.GetCollectionSheet(collector, c.Query("date"))
+    if err != nil {
+        if err.Error() == "collector not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Collector not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to generate collection sheet",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    filename := fmt.Sprintf("collection-sheet-%s-%s", sheet.CollectorName, sheet.Date)
+    switch format {
+    case "csv":
+        c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+        c.Header("Content-Type", "text/csv")
+        if err := h.collectionService.SheetTable(sheet).WriteCSV(c.Writer); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write collection sheet"})
+        }
+    case "pdf":
+        pdf, err := h.collectionService.SheetTable(sheet).PDF()
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "Failed to generate collection sheet",
+                "details": err.Error(),
+            })
+            return
+        }
+        c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, filename))
+        c.Data(http.StatusOK, "application/pdf", pdf)
+    default:
+        c.JSON(http.StatusOK, sheet)
+    }
+}
+
+// PostCollectionBatch posts a collection run's payments in one transaction
+func (h *CollectionHandler) PostCollectionBatch(c *gin.Context) {
+    var req models.CollectionBatchRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    req.ReceivedBy = signedInUserID(c)
+
+    result, err := h.collectionService.PostCollectionBatch(&req)
+    if err != nil {
+        if errors.Is(err, services.ErrRemittanceMismatch) || errors.Is(err, services.ErrCollectionBatchFailed) {
+            c.JSON(http.StatusUnprocessableEntity, gin.H{
+                "error":   "Collection batch not posted",
+                "details": err.Error(),
+                "result":  result,
+            })
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to post collection batch",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Collection batch posted successfully",
+        "result":  result,
+    })
+}
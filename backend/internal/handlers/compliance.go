@@ -0,0 +1,141 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "github.com/gin-gonic/gin"
+)
+
+type ComplianceHandler struct {
+    complianceService *services.ComplianceService
+}
+
+func NewComplianceHandler(complianceService *services.ComplianceService) *ComplianceHandler {
+    return &ComplianceHandler{complianceService: complianceService}
+}
+
+// GetSettings returns the pricing caps in force
+func (h *ComplianceHandler) GetSettings(c *gin.Context) {
+    settings, err := h.complianceService.GetSettings()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch compliance settings"})
+        return
+    }
+
+    c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings changes the pricing caps (admin only)
+func (h *ComplianceHandler) UpdateSettings(c *gin.Context) {
+    var req models.ComplianceSettingsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    username, _ := c.Get("username")
+    settings, err := h.complianceService.UpdateSettings(&req, fmt.Sprint(username))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Compliance settings updated successfully",
+        "settings": settings,
+    })
+}
+
+// GetFlaggedLoans lists loans booked above the caps awaiting admin review
+func (h *ComplianceHandler) GetFlaggedLoans(c *gin.Context) {
+    loans, err := h.complianceService.GetFlaggedLoans()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch flagged loans"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "loans": loans,
+        "total": len(loans),
+    })
+}
+
+// GetOverrides returns the audit trail of compliance overrides
+func (h *ComplianceHandler) GetOverrides(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    overrides, total, err := h.complianceService.GetOverrides(page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overrides"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "overrides": overrides,
+        "total":     total,
+        "page":      page,
+        "limit":     limit,
+    })
+}
+
+// OverrideLoan accepts a flagged loan's pricing (admin only)
+func (h *ComplianceHandler) OverrideLoan(c *gin.Context) {
+    loanID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+        return
+    }
+
+    var req models.ComplianceOverrideRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if !authorizeComplianceOverride(c, &req) {
+        return
+    }
+
+    override, err := h.complianceService.OverrideFlaggedLoan(uint(loanID), &req)
+    if err != nil {
+        if err.Error() == "loan not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":  "Compliance override recorded",
+        "override": override,
+    })
+}
+
+// authorizeComplianceOverride stamps an override with the signed-in admin, or rejects the request.
+// It returns false once a response has been written.
+func authorizeComplianceOverride(c *gin.Context, override *models.ComplianceOverrideRequest) bool {
+    if override == nil {
+        return true
+    }
+
+    isAdmin, _ := c.Get("is_admin")
+    if admin, ok := isAdmin.(bool); !ok || !admin {
+        c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin can override compliance caps"})
+        return false
+    }
+    if override.Reason == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required to override compliance caps"})
+        return false
+    }
+
+    username, _ := c.Get("username")
+    override.ApprovedBy = fmt.Sprint(username)
+    if userID, ok := c.Get("user_id"); ok {
+        override.ApprovedByID, _ = userID.(uint)
+    }
+    return true
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "github.com/gin-gonic/gin"
+)
+
+type DocumentHandler struct {
+    documentService *services.DocumentService
+}
+
+func NewDocumentHandler(documentService *services.DocumentService) *DocumentHandler {
+    return &DocumentHandler{documentService: documentService}
+}
+
+// GetLoanDocument renders a loan's disclosure statement or promissory note as PDF
+func (h *DocumentHandler) GetLoanDocument(c *gin.Context) {
+    loanIDStr := c.Param("id")
+    loanID, err := strconv.ParseUint(loanIDStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+        return
+    }
+
+    docType := c.Param("type")
+    if docType != models.DocumentTypeDisclosure && docType != models.DocumentTypePromissoryNote {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Document type must be 'disclosure' or 'promissory-note'"})
+        return
+    }
+
+    pdf, err := h.documentService.GenerateLoanDocument(uint(loanID), docType)
+    if err != nil {
+        if err.Error() == "loan not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "Failed to generate document",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="loan-%d-%s.pdf"`, loanID, docType))
+    c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetTemplates lists the current document templates
+func (h *DocumentHandler) GetTemplates(c *gin.Context) {
+    templates, err := h.documentService.ListTemplates()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve templates"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetTemplate returns the current template for a document type
+func (h *DocumentHandler) GetTemplate(c *gin.Context) {
+    template, err := h.documentService.GetTemplate(c.Param("type"))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate saves new wording for a document type (admin only)
+func (h *DocumentHandler) UpdateTemplate(c *gin.Context) {
+    var req models.DocumentTemplateUpdateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    username, _ := c.Get("username")
+    template, err := h.documentService.UpdateTemplate(c.Param("type"), &req, fmt.Sprint(username))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Template updated successfully",
+        "template": template,
+    })
+}
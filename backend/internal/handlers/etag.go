@@ -0,0 +1,52 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// setVersionETag sends a record's version as its ETag, so clients can make updates conditional with If-Match
+func setVersionETag(c *gin.Context, version int) {
+    c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion reads the version a client expects from If-Match.
+// It returns 0 when the header is absent or "*"; the version must then come in the request body.
+func ifMatchVersion(c *gin.Context) (int, error) {
+    header := strings.TrimSpace(c.GetHeader("If-Match"))
+    if header == "" || header == "*" {
+        return 0, nil
+    }
+
+    tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+    version, err := strconv.Atoi(tag)
+    if err != nil || version < 1 {
+        return 0, fmt.Errorf("invalid If-Match header %q", header)
+    }
+    return version, nil
+}
+
+// respondVersionRequired rejects an update that did not say which version it was made against
+func respondVersionRequired(c *gin.Context, resource string) {
+    c.JSON(http.StatusPreconditionRequired, gin.H{
+        "error":   resource + " updates must be conditional",
+        "details": "Send the version from its ETag in If-Match, or the version in the body",
+    })
+}
+
+// respondVersionConflict reports an update that lost a race with another write:
+// 412 when the client sent If-Match, 409 otherwise
+func respondVersionConflict(c *gin.Context, resource string) {
+    status := http.StatusConflict
+    if c.GetHeader("If-Match") != "" {
+        status = http.StatusPreconditionFailed
+    }
+    c.JSON(status, gin.H{
+        "error":   resource + " was modified by another request",
+        "details": "Reload it and try again",
+    })
+}
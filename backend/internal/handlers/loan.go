@@ -1,6 +1,8 @@
 package handlers
 
 import (
+    "errors"
+    "fmt"
     "net/http"
     "strconv"
     "time"
@@ -33,9 +35,22 @@ func (h *LoanHandler) CreateLoan(c *gin.Context) {
         return
     }
 
+    if !authorizeComplianceOverride(c, req.Loan.ComplianceOverride) {
+        return
+    }
+
+    req.Loan.ReleasedByID = signedInUserID(c)
+
     // Create the loan with ClientID
     createdLoan, err := h.loanService.CreateLoan(&req.Loan, req.ClientID)
     if err != nil {
+        if errors.Is(err, services.ErrComplianceCapExceeded) {
+            c.JSON(http.StatusUnprocessableEntity, gin.H{
+                "error":   "Loan exceeds compliance caps",
+                "details": err.Error(),
+            })
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create loan: " + err.Error()})
         return
     }
@@ -58,6 +73,44 @@ func (h *LoanHandler) CreateLoan(c *gin.Context) {
         "loan":    createdLoan,
     })
 }
+// SimulateLoan returns a loan quote with its full schedule without saving anything
+func (h *LoanHandler) SimulateLoan(c *gin.Context) {
+    var req models.LoanSimulationRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    simulation, err := h.loanService.SimulateLoan(&req)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, simulation)
+}
+
+// GetLoanSchedule returns the installment schedule of a loan
+func (h *LoanHandler) GetLoanSchedule(c *gin.Context) {
+    loanIDStr := c.Param("id")
+    loanID, err := strconv.ParseUint(loanIDStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+        return
+    }
+
+    schedule, err := h.loanService.GetLoanSchedule(uint(loanID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "loan_id":  loanID,
+        "schedule": schedule,
+    })
+}
+
 // GetAllLoans retrieves all loans with pagination
 func (h *LoanHandler) GetAllLoans(c *gin.Context) {
     page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -111,6 +164,7 @@ func (h *LoanHandler) GetLoan(c *gin.Context) {
         return
     }
 
+    setVersionETag(c, loan.Version)
     c.JSON(http.StatusOK, loan)
 }
 
@@ -132,12 +186,31 @@ func (h *LoanHandler) UpdateLoan(c *gin.Context) {
     // Set the ID from URL parameter
     updateReq.ID = uint(loanID)
 
+    // If-Match takes precedence over a version in the body
+    version, err := ifMatchVersion(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if version != 0 {
+        updateReq.Version = version
+    }
+
     updatedLoan, err := h.loanService.UpdateLoan(&updateReq)
     if err != nil {
+        if errors.Is(err, services.ErrVersionRequired) {
+            respondVersionRequired(c, "Loan")
+            return
+        }
+        if errors.Is(err, services.ErrVersionConflict) {
+            respondVersionConflict(c, "Loan")
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update loan"})
         return
     }
 
+    setVersionETag(c, updatedLoan.Version)
     c.JSON(http.StatusOK, updatedLoan)
 }
 
@@ -158,6 +231,55 @@ func (h *LoanHandler) DeleteLoan(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"message": "Loan deleted successfully"})
 }
 
+// AddCharge records a penalty or fee against a loan
+func (h *LoanHandler) AddCharge(c *gin.Context) {
+    loanIDStr := c.Param("id")
+    loanID, err := strconv.ParseUint(loanIDStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+        return
+    }
+
+    var req models.LoanChargeCreateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    username, _ := c.Get("username")
+    charge, err := h.loanService.AddCharge(uint(loanID), &req, fmt.Sprint(username))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Charge added successfully",
+        "charge":  charge,
+    })
+}
+
+// GetCharges lists the charges recorded against a loan
+func (h *LoanHandler) GetCharges(c *gin.Context) {
+    loanIDStr := c.Param("id")
+    loanID, err := strconv.ParseUint(loanIDStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+        return
+    }
+
+    charges, err := h.loanService.GetCharges(uint(loanID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch charges"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "charges": charges,
+        "total":   len(charges),
+    })
+}
+
 // GetLoanStats returns loan statistics
 func (h *LoanHandler) GetLoanStats(c *gin.Context) {
     stats, err := h.loanService.GetLoanStats()
@@ -173,3 +295,27 @@ func (h *LoanHandler) GetLoanStats(c *gin.Context) {
 func generateLoanControlNumber(clientID uint) string {
     return "L" + strconv.FormatUint(uint64(clientID), 10) + "-" + strconv.FormatInt(time.Now().Unix(), 10)
 }
+
+// RefreshOverdue re-runs overdue detection, as of today or ?as_of=YYYY-MM-DD
+func (h *LoanHandler) RefreshOverdue(c *gin.Context) {
+    asOf := time.Now()
+    if asOfStr := c.Query("as_of"); asOfStr != "" {
+        parsed, err := time.Parse("2006-01-02", asOfStr)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid as_of date, expected YYYY-MM-DD"})
+            return
+        }
+        asOf = parsed
+    }
+
+    result, err := h.loanService.RefreshOverdueStatuses(asOf)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "Failed to refresh overdue loans",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+    "errors"
     "net/http"
     "strconv"
     "micro-lending-platform/backend/internal/models"
@@ -42,8 +43,14 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
         return
     }
 
+    req.ReceivedBy = signedInUserID(c)
+
     createdPayment, err := h.paymentService.CreatePayment(&req)
     if err != nil {
+        if errors.Is(err, services.ErrVersionConflict) {
+            respondVersionConflict(c, "Loan")
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{
             "error": "Failed to create payment", 
             "details": err.Error(),
@@ -93,6 +100,7 @@ func (h *PaymentHandler) GetPaymentByID(c *gin.Context) {
         return
     }
 
+    setVersionETag(c, payment.Version)
     c.JSON(http.StatusOK, payment)
 }
 
@@ -105,19 +113,44 @@ func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
         return
     }
 
-    var payment models.Payment
-    if err := c.ShouldBindJSON(&payment); err != nil {
+    // The request is applied over the payment as stored, so fields it leaves out keep their values
+    payment, err := h.paymentService.GetPaymentByID(uint(id))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+        return
+    }
+    payment.Version = 0
+    if err := c.ShouldBindJSON(payment); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
         return
     }
 
+    // If-Match takes precedence over a version in the body
+    version, err := ifMatchVersion(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if version != 0 {
+        payment.Version = version
+    }
+
     payment.ID = uint(id)
-    updatedPayment, err := h.paymentService.UpdatePayment(&payment)
+    updatedPayment, err := h.paymentService.UpdatePayment(payment)
     if err != nil {
+        if errors.Is(err, services.ErrVersionRequired) {
+            respondVersionRequired(c, "Payment")
+            return
+        }
+        if errors.Is(err, services.ErrVersionConflict) {
+            respondVersionConflict(c, "Payment")
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment"})
         return
     }
 
+    setVersionETag(c, updatedPayment.Version)
     c.JSON(http.StatusOK, gin.H{
         "message": "Payment updated successfully",
         "payment": updatedPayment,
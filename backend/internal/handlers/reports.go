@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
+	"micro-lending-platform/backend/internal/export"
 	"micro-lending-platform/backend/internal/models"
+	"micro-lending-platform/backend/internal/period"
 	"micro-lending-platform/backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -19,41 +22,62 @@ func NewReportHandler(reportService *services.ReportService) *ReportHandler {
 
 // GetWeeklyReport returns weekly report data
 // @Summary Get Weekly Report
-// @Description Returns weekly statistics including payments, releases, and client metrics
+// @Description Returns weekly statistics including payments, releases, and client metrics. Defaults to the current business week; from and to pick any range of days.
 // @Tags reports
 // @Security BearerAuth
 // @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), defaults to the start of this week"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to the end of this week"
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param week_start query string false "First day of the week, e.g. monday"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
 // @Success 200 {object} models.ReportResponse
+// @Failure 400 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/v1/reports/weekly [get]
 func (h *ReportHandler) GetWeeklyReport(c *gin.Context) {
-	data, err := h.reportService.GetWeeklyReport()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to generate report",
-			"details": err.Error(),
-		})
+	opts, ok := h.reportOptions(c)
+	if !ok {
 		return
 	}
-
-	c.JSON(http.StatusOK, models.ReportResponse{
-		Data:      *data,
-		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
-		Status:    "success",
-	})
+	h.periodReport(c, "Weekly Report", period.Week(time.Now(), opts), opts)
 }
 
 // GetMonthlyReport returns monthly report data
 // @Summary Get Monthly Report
-// @Description Returns monthly statistics including payments, releases, and client metrics
+// @Description Returns monthly statistics including payments, releases, and client metrics. Defaults to the current business month; from and to pick any range of days.
 // @Tags reports
 // @Security BearerAuth
 // @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), defaults to the start of this month"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to the end of this month"
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
 // @Success 200 {object} models.ReportResponse
+// @Failure 400 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/v1/reports/monthly [get]
 func (h *ReportHandler) GetMonthlyReport(c *gin.Context) {
-	data, err := h.reportService.GetMonthlyReport()
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return
+	}
+	h.periodReport(c, "Monthly Report", period.Month(time.Now(), opts), opts)
+}
+
+// periodReport responds with the totals for ?from= and ?to=, or the default range when they are absent
+func (h *ReportHandler) periodReport(c *gin.Context, title string, fallback period.Range, opts period.Options) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	r, err := period.Parse(c.Query("from"), c.Query("to"), fallback, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.reportService.GetPeriodReport(r)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to generate report",
@@ -61,9 +85,16 @@ func (h *ReportHandler) GetMonthlyReport(c *gin.Context) {
 		})
 		return
 	}
+	if format != "json" {
+		writeReport(c, h.reportService.PeriodReportTable(title, data, r), format, fmt.Sprintf("report-%s-%s", r.From(), r.To()))
+		return
+	}
 
 	c.JSON(http.StatusOK, models.ReportResponse{
 		Data:      *data,
+		From:      r.From(),
+		To:        r.To(),
+		Timezone:  opts.Location.String(),
 		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
 		Status:    "success",
 	})
@@ -77,12 +108,21 @@ func (h *ReportHandler) GetMonthlyReport(c *gin.Context) {
 // @Produce json
 // @Param period query string false "Period type: weekly or monthly" default(weekly)
 // @Param periods query int false "Number of periods to retrieve" default(4)
+// @Param from query string false "First day (YYYY-MM-DD); with to, returns every period overlapping the range instead of a count"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today"
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param week_start query string false "First day of the week, e.g. monday"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
 // @Success 200 {object} models.HistoricalReportResponse
 // @Failure 400 {object} gin.H
 // @Failure 500 {object} gin.H
 // @Router /api/v1/reports/history [get]
 func (h *ReportHandler) GetHistoricalReport(c *gin.Context) {
 	// Get query parameters
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
 	periodType := c.DefaultQuery("period", "weekly")
 	periodsStr := c.DefaultQuery("periods", "4")
 	
@@ -94,17 +134,47 @@ func (h *ReportHandler) GetHistoricalReport(c *gin.Context) {
 		return
 	}
 	
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return
+	}
+
 	// Parse and validate periods count
-	periods, err := strconv.Atoi(periodsStr)
-	if err != nil || periods < 1 || periods > 52 {
+	count, err := strconv.Atoi(periodsStr)
+	if err != nil || count < 1 || count > 52 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid periods count. Must be between 1 and 52",
 		})
 		return
 	}
+
+	// Periods run back from the one holding ?to=; with ?from= too, they cover the range instead
+	today := period.Today(opts)
+	r, err := period.Parse(c.Query("from"), c.Query("to"), today, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var periods []period.Range
+	switch {
+	case c.Query("from") != "" && periodType == "weekly":
+		periods = period.WeeksCovering(r, opts)
+	case c.Query("from") != "":
+		periods = period.MonthsCovering(r, opts)
+	case periodType == "weekly":
+		periods = period.Weeks(r.End(), count, opts)
+	default:
+		periods = period.Months(r.End(), count, opts)
+	}
+	if len(periods) > 52 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Range covers too many periods. At most 52 are allowed",
+		})
+		return
+	}
 	
 	// Generate historical report
-	data, err := h.reportService.GetHistoricalReport(periodType, periods)
+	data, err := h.reportService.GetHistoricalReport(periodType, periods, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to generate historical report",
@@ -112,6 +182,473 @@ func (h *ReportHandler) GetHistoricalReport(c *gin.Context) {
 		})
 		return
 	}
+	if format != "json" {
+		writeReport(c, h.reportService.HistoryTable(data, opts), format, periodType+"-history")
+		return
+	}
 	
 	c.JSON(http.StatusOK, data)
 }
+
+// GetParReport returns portfolio at risk and arrears aging
+// @Summary Get Portfolio at Risk Report
+// @Description Buckets outstanding balances by days past due (1-7, 8-30, 31-60, 61-90, 90+) with PAR1/PAR7/PAR30, by branch, loan officer and product
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param as_of query string false "Report date (YYYY-MM-DD), defaults to today; to is accepted as an alias"
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
+// @Success 200 {object} models.ParReport
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/par [get]
+func (h *ReportHandler) GetParReport(c *gin.Context) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	asOf, ok := h.reportAsOf(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetParReport(asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate PAR report",
+			"details": err.Error(),
+		})
+		return
+	}
+	if format != "json" {
+		writeReport(c, h.reportService.ParReportTable(report, asOf), format, "par-"+asOf.To())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetParLoans lists the loans behind a PAR figure
+// @Summary Get Portfolio at Risk Loans
+// @Description Drill-down from the PAR report to individual loans, most delinquent first
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param as_of query string false "Report date (YYYY-MM-DD), defaults to today; to is accepted as an alias"
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param bucket query string false "current, 1-7, 8-30, 31-60, 61-90 or 90+"
+// @Param branch_id query int false "Branch ID, 0 for unassigned"
+// @Param officer_id query int false "Loan officer user ID, 0 for unassigned"
+// @Param product query string false "Product name from the PAR report"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
+// @Success 200 {object} models.ParLoanList
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/par/loans [get]
+func (h *ReportHandler) GetParLoans(c *gin.Context) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	asOf, ok := h.reportAsOf(c)
+	if !ok {
+		return
+	}
+
+	var filter models.ParLoanFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	list, err := h.reportService.GetParLoans(asOf, &filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to get PAR loans",
+			"details": err.Error(),
+		})
+		return
+	}
+	if format != "json" {
+		table, err := h.reportService.ParLoansTable(list, &filter, asOf)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to export PAR loans",
+				"details": err.Error(),
+			})
+			return
+		}
+		writeReport(c, table, format, "par-loans-"+asOf.To())
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetCollectionEfficiency returns amounts due against amounts collected
+// @Summary Get Collection Efficiency Report
+// @Description Splits collections into current, past-due and advance against amounts due in the period, per collector and branch, with a weekly trend
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), defaults to the start of this week"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to the end of this week"
+// @Param weeks query int false "Weeks of trend history" default(8)
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param week_start query string false "First day of the week, e.g. monday"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
+// @Success 200 {object} models.CollectionEfficiencyReport
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/collection-efficiency [get]
+func (h *ReportHandler) GetCollectionEfficiency(c *gin.Context) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return
+	}
+	r, err := period.Parse(c.Query("from"), c.Query("to"), period.Week(time.Now(), opts), opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	weeks, err := strconv.Atoi(c.DefaultQuery("weeks", "8"))
+	if err != nil || weeks < 1 || weeks > 52 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid weeks count. Must be between 1 and 52",
+		})
+		return
+	}
+
+	report, err := h.reportService.GetCollectionEfficiency(r, weeks, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate collection efficiency report",
+			"details": err.Error(),
+		})
+		return
+	}
+	if format != "json" {
+		writeReport(c, h.reportService.CollectionEfficiencyTable(report, r), format, fmt.Sprintf("collection-efficiency-%s-%s", r.From(), r.To()))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetOfficerPerformance compares loan officers over a period
+// @Summary Get Loan Officer Performance
+// @Description Per officer: active borrowers, outstanding portfolio, disbursements, PAR, collection efficiency, new vs. repeat clients and dropout rate, with a trend over earlier periods
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), defaults to the start of this month"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to the end of this month"
+// @Param period query string false "Trend period type: weekly or monthly" default(monthly)
+// @Param periods query int false "Number of trend periods" default(6)
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param week_start query string false "First day of the week, e.g. monday"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
+// @Success 200 {object} models.OfficerPerformanceReport
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/officers [get]
+func (h *ReportHandler) GetOfficerPerformance(c *gin.Context) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return
+	}
+	r, err := period.Parse(c.Query("from"), c.Query("to"), period.Month(time.Now(), opts), opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	periodType := c.DefaultQuery("period", "monthly")
+	if periodType != "weekly" && periodType != "monthly" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid period type. Must be 'weekly' or 'monthly'",
+		})
+		return
+	}
+	periods, err := strconv.Atoi(c.DefaultQuery("periods", "6"))
+	if err != nil || periods < 1 || periods > 24 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid periods count. Must be between 1 and 24",
+		})
+		return
+	}
+
+	report, err := h.reportService.GetOfficerPerformance(r, periodType, periods, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate officer performance report",
+			"details": err.Error(),
+		})
+		return
+	}
+	if format != "json" {
+		writeReport(c, h.reportService.OfficerPerformanceTable(report, r), format, fmt.Sprintf("officer-performance-%s-%s", r.From(), r.To()))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetCohortReport returns a vintage analysis of loans by release month and product
+// @Summary Get Cohort Report
+// @Description Groups loans by release month and product and follows each cohort month on book: cumulative repayment, PAR30 and 90+ day default rates, with loans now marked Default as written off
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Day in the first release month (YYYY-MM-DD), defaults to eleven months ago"
+// @Param to query string false "Day in the last release month (YYYY-MM-DD), defaults to today"
+// @Param months query int false "Months on book to follow" default(12)
+// @Param product query string false "Product name, e.g. Weekly 3-month"
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
+// @Success 200 {object} models.CohortReport
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/cohorts [get]
+func (h *ReportHandler) GetCohortReport(c *gin.Context) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return
+	}
+	today := period.Today(opts)
+	fallback := period.Between(period.Month(time.Now(), opts).FirstDay().AddDate(0, -11, 0), today.LastDay(), opts)
+	r, err := period.Parse(c.Query("from"), c.Query("to"), fallback, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	months, err := strconv.Atoi(c.DefaultQuery("months", "12"))
+	if err != nil || months < 1 || months > 36 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid months count. Must be between 1 and 36",
+		})
+		return
+	}
+
+	report, err := h.reportService.GetCohortReport(r, months, c.Query("product"), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate cohort report",
+			"details": err.Error(),
+		})
+		return
+	}
+	if format != "json" {
+		writeReport(c, h.reportService.CohortTable(report, r), format, fmt.Sprintf("cohorts-%s-%s", report.From, report.To))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetCashFlowForecast projects collections against planned loan releases for the coming weeks
+// @Summary Get Cash Flow Forecast
+// @Description Projects daily and weekly inflows from unpaid installments falling due, scaled by each branch's current collection efficiency over the lookback weeks, against loans booked for release on a later day
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param weeks query int false "Weeks to forecast, starting with the current week" default(4)
+// @Param lookback_weeks query int false "Weeks before today the branch efficiencies are measured over" default(12)
+// @Param opening_balance query number false "Cash on hand today, for the running cash position" default(0)
+// @Param tz query string false "IANA timezone the days are cut in, defaults to the business timezone"
+// @Param week_start query string false "Day weeks start on, e.g. monday, defaults to the configured week start"
+// @Param format query string false "json, csv, xlsx or pdf" default(json)
+// @Success 200 {object} models.CashFlowForecast
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/cash-flow [get]
+func (h *ReportHandler) GetCashFlowForecast(c *gin.Context) {
+	format, ok := reportFormat(c)
+	if !ok {
+		return
+	}
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return
+	}
+
+	weeks, err := strconv.Atoi(c.DefaultQuery("weeks", "4"))
+	if err != nil || weeks < 1 || weeks > 26 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid weeks count. Must be between 1 and 26",
+		})
+		return
+	}
+	lookback, err := strconv.Atoi(c.DefaultQuery("lookback_weeks", "12"))
+	if err != nil || lookback < 1 || lookback > 52 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid lookback_weeks count. Must be between 1 and 52",
+		})
+		return
+	}
+	opening, err := strconv.ParseFloat(c.DefaultQuery("opening_balance", "0"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid opening_balance"})
+		return
+	}
+
+	report, err := h.reportService.GetCashFlowForecast(weeks, lookback, opening, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate cash flow forecast",
+			"details": err.Error(),
+		})
+		return
+	}
+	if format != "json" {
+		writeReport(c, h.reportService.CashFlowTable(report, opts), format, fmt.Sprintf("cash-flow-%s-%s", report.From, report.To))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetSnapshots lists recent daily portfolio snapshots
+// @Summary List Portfolio Snapshots
+// @Description Lists the days with a portfolio snapshot and their totals, newest first
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Number of days" default(30)
+// @Success 200 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/snapshots [get]
+func (h *ReportHandler) GetSnapshots(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "30"))
+
+	snapshots, err := h.reportService.GetSnapshots(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots": snapshots})
+}
+
+// TakeSnapshot takes or retakes the portfolio snapshot for a day
+// @Summary Take Portfolio Snapshot
+// @Description Snapshots every open loan as of the end of ?date= (default yesterday), replacing any snapshot for that day. Used to backfill missed nights.
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param date query string false "Snapshot day (YYYY-MM-DD), defaults to yesterday in the business timezone"
+// @Success 201 {object} models.SnapshotSummary
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/reports/snapshots [post]
+func (h *ReportHandler) TakeSnapshot(c *gin.Context) {
+	date := period.Today(h.reportService.PeriodOptions()).FirstDay().AddDate(0, 0, -1)
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, use YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+	if date.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot snapshot a future day"})
+		return
+	}
+
+	summary, err := h.reportService.TakeSnapshot(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to take snapshot",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, summary)
+}
+
+// reportFormat reads ?format=, responding with 400 and reporting false for anything but json, csv, xlsx or pdf
+func reportFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", "json")
+	switch format {
+	case "json", export.FormatCSV, export.FormatXLSX, export.FormatPDF:
+		return format, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be json, csv, xlsx or pdf"})
+	return "", false
+}
+
+// writeReport streams a report table to the client as a file download. Once the first bytes are
+// out the status can no longer change, so a failure part-way is recorded on the context instead.
+// A PDF is built whole before it is sent, so one with more than export.MaxPDFRows rows is refused.
+func writeReport(c *gin.Context, table *export.Table, format, filename string) {
+	if format == export.FormatPDF && table.RowCount() > export.MaxPDFRows {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   fmt.Sprintf("report has %d rows, more than the %d a PDF can hold", table.RowCount(), export.MaxPDFRows),
+			"details": "Export it with format=csv or format=xlsx, or narrow the date range or filters",
+		})
+		return
+	}
+
+	disposition := "attachment"
+	if format == export.FormatPDF {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s.%s"`, disposition, filename, format))
+	c.Header("Content-Type", export.ContentType(format))
+	c.Status(http.StatusOK)
+	if err := table.Write(c.Writer, format); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// reportOptions reads ?tz= and ?week_start= over the configured business defaults,
+// responding with 400 and reporting false when either is not recognised
+func (h *ReportHandler) reportOptions(c *gin.Context) (period.Options, bool) {
+	opts, err := h.reportService.PeriodOptions().WithOverrides(c.Query("tz"), c.Query("week_start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return opts, false
+	}
+	return opts, true
+}
+
+// reportAsOf reads the ?as_of= report day (or ?to=), defaulting to today in the business timezone
+func (h *ReportHandler) reportAsOf(c *gin.Context) (period.Range, bool) {
+	opts, ok := h.reportOptions(c)
+	if !ok {
+		return period.Range{}, false
+	}
+	asOfStr := c.Query("as_of")
+	if asOfStr == "" {
+		asOfStr = c.Query("to")
+	}
+	if asOfStr == "" {
+		return period.Today(opts), true
+	}
+	asOf, err := time.Parse(period.DateLayout, asOfStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of date, use YYYY-MM-DD"})
+		return period.Range{}, false
+	}
+	return period.Between(asOf, asOf, opts), true
+}
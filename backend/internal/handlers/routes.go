@@ -14,13 +14,35 @@ func SetupRoutes(
 	loanService *services.LoanService,
 	paymentService *services.PaymentService,
 	reportService *services.ReportService,
+	documentService *services.DocumentService,
+	statementService *services.StatementService,
+	calendarService *services.CalendarService,
+	complianceService *services.ComplianceService,
+	collectionService *services.CollectionService,
+	syncService *services.SyncService,
+	cashierService *services.CashierService,
+	walletService *services.WalletService,
+	bankImportService *services.BankImportService,
+	ledgerService *services.LedgerService,
+	provisioningService *services.ProvisioningService,
+	accrualService *services.AccrualService,
+	exportService *services.JournalExportService,
 ) {
 	// Initialize handlers - update clientHandler to include loanService
 	authHandler := NewAuthHandler(authService)
-	clientHandler := NewClientHandler(clientService, loanService) // Updated
+	clientHandler := NewClientHandler(clientService, loanService, statementService)
 	loanHandler := NewLoanHandler(loanService)
 	paymentHandler := NewPaymentHandler(paymentService)
 	reportHandler := NewReportHandler(reportService)
+	documentHandler := NewDocumentHandler(documentService)
+	calendarHandler := NewCalendarHandler(calendarService)
+	complianceHandler := NewComplianceHandler(complianceService)
+	collectionHandler := NewCollectionHandler(collectionService)
+	syncHandler := NewSyncHandler(syncService)
+	cashierHandler := NewCashierHandler(cashierService)
+	walletHandler := NewWalletHandler(walletService)
+	bankImportHandler := NewBankImportHandler(bankImportService)
+	accountingHandler := NewAccountingHandler(ledgerService, provisioningService, accrualService, exportService)
 
 	// API v1 group
 	v1 := router.Group("/api/v1")
@@ -30,6 +52,15 @@ func SetupRoutes(
 		setupLoanRoutes(v1, loanHandler)
 		setupPaymentRoutes(v1, paymentHandler)
 		setupReportRoutes(v1, reportHandler)
+		setupDocumentRoutes(v1, documentHandler)
+		setupCalendarRoutes(v1, calendarHandler)
+		setupComplianceRoutes(v1, complianceHandler)
+		setupCollectionRoutes(v1, collectionHandler)
+		setupSyncRoutes(v1, syncHandler)
+		setupCashierRoutes(v1, cashierHandler)
+		setupWalletRoutes(v1, walletHandler)
+		setupBankImportRoutes(v1, bankImportHandler)
+		setupAccountingRoutes(v1, accountingHandler)
 	}
 
 	// System routes
@@ -67,6 +98,7 @@ func setupClientRoutes(rg *gin.RouterGroup, h *ClientHandler) {
 		clients.POST("/simple", h.CreateSimpleClient)
 		clients.GET("/:id", h.GetClientByID)
 		clients.GET("/:id/details", h.GetClientWithDetails)
+		clients.GET("/:id/statement", h.GetClientStatement)
 		clients.PUT("/:id", h.UpdateClient)
 		clients.DELETE("/:id", h.DeleteClient)
 		clients.PATCH("/:id/restore", h.RestoreClient)
@@ -96,14 +128,19 @@ func setupLoanRoutes(rg *gin.RouterGroup, h *LoanHandler) {
 
 	{
 		loans.POST("", h.CreateLoan)                    // Create new loan
+		loans.POST("/simulate", h.SimulateLoan)         // Quote a loan without saving it
 		loans.GET("/client/:clientId", h.GetLoansByClientID) // Get all loans for client
 		loans.GET("/:id", h.GetLoan)                    // Get single loan
+		loans.GET("/:id/schedule", h.GetLoanSchedule)   // Installment schedule
+		loans.GET("/:id/charges", h.GetCharges)         // Penalties and fees
+		loans.POST("/:id/charges", auth.AdminMiddleware(), h.AddCharge) // Record a penalty or fee
 		loans.PUT("/:id", h.UpdateLoan)                 // Update loan
 		loans.DELETE("/:id", h.DeleteLoan)              // Delete loan
 		
 		// Additional loan routes that might be needed
 		loans.GET("", h.GetAllLoans)                    // Get all loans with pagination
 		loans.GET("/stats", h.GetLoanStats)             // Get loan statistics
+		loans.POST("/overdue/refresh", auth.AdminMiddleware(), h.RefreshOverdue) // Re-run overdue detection
 	}
 }
 
@@ -133,8 +170,176 @@ func setupReportRoutes(rg *gin.RouterGroup, h *ReportHandler) {
 		reports.GET("/weekly", h.GetWeeklyReport)
 		reports.GET("/monthly", h.GetMonthlyReport)
 		reports.GET("/history", h.GetHistoricalReport)
+		reports.GET("/par", h.GetParReport)
+		reports.GET("/par/loans", h.GetParLoans)
+		reports.GET("/collection-efficiency", h.GetCollectionEfficiency)
+		reports.GET("/officers", h.GetOfficerPerformance)
+		reports.GET("/cohorts", h.GetCohortReport)
+		reports.GET("/cash-flow", h.GetCashFlowForecast)
+		reports.GET("/snapshots", h.GetSnapshots)
+		reports.POST("/snapshots", auth.AdminMiddleware(), h.TakeSnapshot)
 	}
 }
+// setupDocumentRoutes configures loan document generation and template management
+func setupDocumentRoutes(rg *gin.RouterGroup, h *DocumentHandler) {
+	loans := rg.Group("/loans")
+	loans.Use(auth.AuthMiddleware())
+	{
+		loans.GET("/:id/documents/:type", h.GetLoanDocument)
+	}
+
+	templates := rg.Group("/document-templates")
+	templates.Use(auth.AuthMiddleware())
+	{
+		templates.GET("", h.GetTemplates)
+		templates.GET("/:type", h.GetTemplate)
+		templates.PUT("/:type", auth.AdminMiddleware(), h.UpdateTemplate)
+	}
+}
+
+// setupCalendarRoutes configures branches and the holiday calendar
+func setupCalendarRoutes(rg *gin.RouterGroup, h *CalendarHandler) {
+	branches := rg.Group("/branches")
+	branches.Use(auth.AuthMiddleware())
+	{
+		branches.GET("", h.GetBranches)
+		branches.POST("", auth.AdminMiddleware(), h.CreateBranch)
+		branches.PUT("/:id", auth.AdminMiddleware(), h.UpdateBranch)
+		branches.DELETE("/:id", auth.AdminMiddleware(), h.DeleteBranch)
+	}
+
+	holidays := rg.Group("/holidays")
+	holidays.Use(auth.AuthMiddleware())
+	{
+		holidays.GET("", h.GetHolidays)
+		holidays.POST("", auth.AdminMiddleware(), h.CreateHoliday)
+		holidays.PUT("/:id", auth.AdminMiddleware(), h.UpdateHoliday)
+		holidays.DELETE("/:id", auth.AdminMiddleware(), h.DeleteHoliday)
+	}
+}
+
+// setupComplianceRoutes configures pricing caps, the flagged-loan queue and overrides
+func setupComplianceRoutes(rg *gin.RouterGroup, h *ComplianceHandler) {
+	compliance := rg.Group("/compliance")
+	compliance.Use(auth.AuthMiddleware())
+	{
+		compliance.GET("/settings", h.GetSettings)
+		compliance.PUT("/settings", auth.AdminMiddleware(), h.UpdateSettings)
+		compliance.GET("/flagged", h.GetFlaggedLoans)
+		compliance.GET("/overrides", auth.AdminMiddleware(), h.GetOverrides)
+	}
+
+	loans := rg.Group("/loans")
+	loans.Use(auth.AuthMiddleware())
+	{
+		loans.POST("/:id/compliance-override", auth.AdminMiddleware(), h.OverrideLoan)
+	}
+}
+
+// setupCollectionRoutes configures field collection endpoints
+func setupCollectionRoutes(rg *gin.RouterGroup, h *CollectionHandler) {
+	collections := rg.Group("/collections")
+	collections.Use(auth.AuthMiddleware())
+	{
+		collections.GET("/sheet", h.GetCollectionSheet)
+		collections.POST("/batch", h.PostCollectionBatch)
+	}
+}
+
+// setupSyncRoutes configures offline sync endpoints for collectors' devices
+func setupSyncRoutes(rg *gin.RouterGroup, h *SyncHandler) {
+	sync := rg.Group("/sync")
+	sync.Use(auth.AuthMiddleware())
+	{
+		sync.GET("/snapshot", h.GetSnapshot)
+		sync.POST("/payments", h.PushPayments)
+	}
+}
+
+// setupCashierRoutes configures cashier drawer sessions and end-of-day reconciliation
+func setupCashierRoutes(rg *gin.RouterGroup, h *CashierHandler) {
+	sessions := rg.Group("/cashier/sessions")
+	sessions.Use(auth.AuthMiddleware())
+	{
+		sessions.GET("", h.GetSessions)
+		sessions.POST("", h.OpenSession)
+		sessions.GET("/current", h.GetCurrentSession)
+		sessions.GET("/flagged", auth.AdminMiddleware(), h.GetFlaggedSessions)
+		sessions.GET("/:id", h.GetSession)
+		sessions.POST("/:id/close", h.CloseSession)
+		sessions.POST("/:id/review", auth.AdminMiddleware(), h.ReviewSession)
+	}
+}
+
+// setupWalletRoutes configures e-wallet webhooks and the review queue for unmatched notifications
+func setupWalletRoutes(rg *gin.RouterGroup, h *WalletHandler) {
+	// Providers authenticate with a signature rather than a JWT
+	rg.POST("/webhooks/wallet/:provider", h.ReceiveWebhook)
+
+	notifications := rg.Group("/wallet/notifications")
+	notifications.Use(auth.AuthMiddleware(), auth.AdminMiddleware())
+	{
+		notifications.GET("", h.GetReviewQueue)
+		notifications.POST("/:id/resolve", h.ResolveNotification)
+		notifications.POST("/:id/dismiss", h.DismissNotification)
+	}
+}
+
+// setupBankImportRoutes configures bank statement imports and the review queue for unconfirmed credits
+func setupBankImportRoutes(rg *gin.RouterGroup, h *BankImportHandler) {
+	imports := rg.Group("/bank-imports")
+	imports.Use(auth.AuthMiddleware(), auth.AdminMiddleware())
+	{
+		imports.POST("", h.ImportStatement)
+		imports.GET("", h.GetImports)
+		imports.GET("/transactions", h.GetReviewQueue)
+		imports.POST("/transactions/:id/confirm", h.ConfirmTransaction)
+		imports.POST("/transactions/:id/ignore", h.IgnoreTransaction)
+		imports.GET("/:id", h.GetImport)
+	}
+}
+
+// setupAccountingRoutes configures the general ledger: chart of accounts, journal, financial statements, period closes
+// loan loss provisioning, interest accrual and exports
+func setupAccountingRoutes(rg *gin.RouterGroup, h *AccountingHandler) {
+	accounting := rg.Group("/accounting")
+	accounting.Use(auth.AuthMiddleware(), auth.AdminMiddleware())
+	{
+		accounting.GET("/accounts", h.GetAccounts)
+		accounting.POST("/accounts", h.CreateAccount)
+		accounting.GET("/journal-entries", h.GetEntries)
+		accounting.POST("/journal-entries", h.CreateEntry)
+		accounting.GET("/journal-entries/:id", h.GetEntry)
+		accounting.POST("/journal-entries/:id/reverse", h.ReverseEntry)
+		accounting.POST("/sync", h.SyncLedger) // Post loan events the ledger has not seen yet
+		accounting.GET("/trial-balance", h.GetTrialBalance)       // ?as_of=YYYY-MM-DD, default today
+		accounting.GET("/income-statement", h.GetIncomeStatement) // ?from=&to=, default this month
+		accounting.GET("/balance-sheet", h.GetBalanceSheet)       // ?as_of=YYYY-MM-DD, default today
+		accounting.GET("/periods", h.GetPeriods)
+		accounting.POST("/periods/close", h.ClosePeriod) // {"month": "YYYY-MM"}
+		accounting.POST("/periods/:id/reopen", h.ReopenPeriod)
+		accounting.GET("/provisioning/settings", h.GetProvisioningSettings)
+		accounting.PUT("/provisioning/settings", h.UpdateProvisioningSettings)
+		accounting.GET("/provisioning/runs", h.GetProvisionRuns)
+		accounting.POST("/provisioning/runs", h.RunProvisioning) // {"month": "YYYY-MM"}
+		accounting.GET("/provisioning/movement", h.GetProvisionMovement) // ?from=&to=, default the last six months
+
+		// Interest accrual
+		accounting.GET("/accruals/settings", h.GetAccrualSettings)
+		accounting.PUT("/accruals/settings", h.UpdateAccrualSettings)
+		accounting.GET("/accruals/runs", h.GetAccrualRuns)
+		accounting.POST("/accruals/runs", h.RunAccruals) // {"month": "YYYY-MM"}
+
+		// Exports to external accounting software
+		accounting.GET("/exports/mappings", h.GetExportMappings)
+		accounting.PUT("/exports/mappings", h.UpdateExportMappings)
+		accounting.GET("/exports", h.GetExports)
+		accounting.POST("/exports", h.CreateExport) // {"from", "to", "format": "csv" | "iif" | "xero"}
+		accounting.GET("/exports/:id/file", h.DownloadExport)
+		accounting.POST("/exports/:id/void", h.VoidExport)
+	}
+}
+
 // setupSystemRoutes configures system-level endpoints
 func setupSystemRoutes(router *gin.Engine) {
 	router.GET("/health", func(c *gin.Context) {
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+type SyncHandler struct {
+    syncService *services.SyncService
+}
+
+func NewSyncHandler(syncService *services.SyncService) *SyncHandler {
+    return &SyncHandler{syncService: syncService}
+}
+
+// GetSnapshot returns the signed-in collector's offline snapshot.
+// Admins may pass collector_id to download another collector's snapshot.
+func (h *SyncHandler) GetSnapshot(c *gin.Context) {
+    collectorID, isAdmin := currentUser(c)
+    if idStr := c.Query("collector_id"); idStr != "" {
+        if !isAdmin {
+            c.JSON(http.StatusForbidden, gin.H{"error": "Only an admin can download another collector's snapshot"})
+            return
+        }
+        id, err := strconv.ParseUint(idStr, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collector ID"})
+            return
+        }
+        collectorID = uint(id)
+    }
+
+    snapshot, err := h.syncService.GetSnapshot(collectorID)
+    if err != nil {
+        if err.Error() == "collector not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Collector not found"})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "Failed to build snapshot",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, snapshot)
+}
+
+// PushPayments applies payments a device recorded offline and reports conflicts
+func (h *SyncHandler) PushPayments(c *gin.Context) {
+    var req models.SyncPushRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    collectorID, isAdmin := currentUser(c)
+    result, err := h.syncService.PushPayments(collectorID, isAdmin, &req)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "Failed to sync payments",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
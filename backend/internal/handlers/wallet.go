@@ -0,0 +1,158 @@
+package handlers
+
+import (
+    "errors"
+    "io"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/services"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+)
+
+// maxWalletWebhookBody caps how much of a webhook body is read
+const maxWalletWebhookBody = 64 << 10
+
+type WalletHandler struct {
+    walletService *services.WalletService
+}
+
+func NewWalletHandler(walletService *services.WalletService) *WalletHandler {
+    return &WalletHandler{walletService: walletService}
+}
+
+// ReceiveWebhook accepts a signed payment notification from an e-wallet provider.
+// Providers sign "<X-Webhook-Timestamp>.<body>" with HMAC-SHA256 and send the hex digest
+// in X-Webhook-Signature. Unmatched notifications are still acknowledged so the provider
+// stops retrying; they wait in the review queue instead.
+func (h *WalletHandler) ReceiveWebhook(c *gin.Context) {
+    provider := c.Param("provider")
+    body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWalletWebhookBody))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+        return
+    }
+
+    err = h.walletService.VerifySignature(provider, c.GetHeader("X-Webhook-Timestamp"), c.GetHeader("X-Webhook-Signature"), body)
+    if err != nil {
+        if errors.Is(err, services.ErrUnknownWalletProvider) {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider"})
+            return
+        }
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+        return
+    }
+
+    notification, err := h.walletService.ReceiveNotification(provider, body)
+    if err != nil {
+        // Anything but a bad payload is answered with a server error so the provider retries
+        status := http.StatusInternalServerError
+        if errors.Is(err, services.ErrInvalidWalletPayload) {
+            status = http.StatusBadRequest
+        }
+        c.JSON(status, gin.H{
+            "error":   "Failed to process notification",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "id":         notification.ID,
+        "event_id":   notification.EventID,
+        "status":     notification.Status,
+        "payment_id": notification.PaymentID,
+    })
+}
+
+// GetReviewQueue lists wallet notifications waiting for review, or those with ?status=
+func (h *WalletHandler) GetReviewQueue(c *gin.Context) {
+    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+    notifications, total, err := h.walletService.GetReviewQueue(c.Query("status"), page, limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "notifications": notifications,
+        "total":         total,
+        "page":          page,
+        "limit":         limit,
+    })
+}
+
+// ResolveNotification posts a queued notification to a loan chosen by the reviewer
+func (h *WalletHandler) ResolveNotification(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+        return
+    }
+
+    var req models.WalletResolveRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    notification, err := h.walletService.ResolveNotification(uint(id), &req, c.GetString("username"))
+    if err != nil {
+        if err.Error() == "notification not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to resolve notification",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":      "Notification posted successfully",
+        "notification": notification,
+    })
+}
+
+// DismissNotification closes a queued notification without posting it
+func (h *WalletHandler) DismissNotification(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+        return
+    }
+
+    var req models.WalletDismissRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    notification, err := h.walletService.DismissNotification(uint(id), &req, c.GetString("username"))
+    if err != nil {
+        if err.Error() == "notification not found" {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+            return
+        }
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "Failed to dismiss notification",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":      "Notification dismissed",
+        "notification": notification,
+    })
+}
@@ -0,0 +1,103 @@
+package jobs
+
+import (
+    "log"
+    "sync"
+    "time"
+)
+
+// Scheduler runs background jobs once a day at a fixed time in its timezone
+type Scheduler struct {
+    jobs     []dailyJob
+    location *time.Location
+    stop     chan struct{}
+    wg       sync.WaitGroup
+}
+
+type dailyJob struct {
+    name    string
+    hour    int
+    minute  int
+    atStart bool // Also run as soon as the scheduler starts
+    run     func(now time.Time) error
+}
+
+// NewScheduler creates a scheduler whose jobs run at their times in the given timezone,
+// the business timezone rather than wherever the server happens to be
+func NewScheduler(location *time.Location) *Scheduler {
+    if location == nil {
+        location = time.UTC
+    }
+    return &Scheduler{location: location, stop: make(chan struct{})}
+}
+
+// Daily registers a job to run every day at hour:minute in the scheduler's timezone
+func (s *Scheduler) Daily(name string, hour, minute int, run func(now time.Time) error) {
+    s.jobs = append(s.jobs, dailyJob{name: name, hour: hour, minute: minute, run: run})
+}
+
+// DailyWithCatchUp registers a daily job that also runs once when the scheduler starts, for jobs
+// that make up for runs missed while the server was down
+func (s *Scheduler) DailyWithCatchUp(name string, hour, minute int, run func(now time.Time) error) {
+    s.jobs = append(s.jobs, dailyJob{name: name, hour: hour, minute: minute, atStart: true, run: run})
+}
+
+// Start launches every registered job in its own goroutine
+func (s *Scheduler) Start() {
+    for _, job := range s.jobs {
+        s.wg.Add(1)
+        go s.loop(job)
+    }
+}
+
+// Stop signals all jobs to exit and waits for any run in progress to finish
+func (s *Scheduler) Stop() {
+    close(s.stop)
+    s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job dailyJob) {
+    defer s.wg.Done()
+
+    if job.atStart {
+        s.runOnce(job, time.Now().In(s.location))
+    }
+    for {
+        next := nextRun(time.Now().In(s.location), job.hour, job.minute)
+        log.Printf("Job %s scheduled for %s", job.name, next.Format("2006-01-02 15:04"))
+
+        timer := time.NewTimer(time.Until(next))
+        select {
+        case <-s.stop:
+            timer.Stop()
+            return
+        case now := <-timer.C:
+            s.runOnce(job, now.In(s.location))
+        }
+    }
+}
+
+// runOnce executes a job, keeping the scheduler alive if it panics
+func (s *Scheduler) runOnce(job dailyJob, now time.Time) {
+    defer func() {
+        if r := recover(); r != nil {
+            log.Printf("Job %s panicked: %v", job.name, r)
+        }
+    }()
+
+    started := time.Now()
+    if err := job.run(now); err != nil {
+        log.Printf("Job %s failed: %v", job.name, err)
+        return
+    }
+    log.Printf("Job %s completed in %s", job.name, time.Since(started).Round(time.Millisecond))
+}
+
+// nextRun returns the next occurrence of hour:minute in now's timezone strictly after now
+func nextRun(now time.Time, hour, minute int) time.Time {
+    next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+    if !next.After(now) {
+        next = next.AddDate(0, 0, 1)
+    }
+    return next
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+    "time"
+)
+
+// Interest accrual methods
+const (
+    AccrualMethodStraightLine      = "straight_line"      // Each installment's interest evenly over the days of its period
+    AccrualMethodEffectiveInterest = "effective_interest" // The constant rate on the carrying amount that earns the term's interest
+)
+
+// InterestAccrualSettings decide how interest is recognized: by the method set for the loan's product,
+// the default method otherwise, and on a cash basis once a loan is more than NonPerformingDays past due
+type InterestAccrualSettings struct {
+    BaseModel
+    DefaultMethod     string            `gorm:"size:20;not null" json:"default_method"`
+    NonPerformingDays int               `gorm:"default:90" json:"non_performing_days"`
+    UpdatedBy         string            `gorm:"size:50" json:"updated_by"`
+    Products          map[string]string `gorm:"-" json:"products"` // Product name to method, e.g. "Weekly 3-month"
+}
+
+func (InterestAccrualSettings) TableName() string {
+    return "interest_accrual_settings"
+}
+
+// AccrualProductMethod is the accrual method set for one loan product
+type AccrualProductMethod struct {
+    BaseModel
+    Product string `gorm:"size:50;uniqueIndex;not null" json:"product"`
+    Method  string `gorm:"size:20;not null" json:"method"`
+}
+
+func (AccrualProductMethod) TableName() string {
+    return "accrual_product_methods"
+}
+
+type InterestAccrualSettingsRequest struct {
+    DefaultMethod     string            `json:"default_method" binding:"required,oneof=straight_line effective_interest"`
+    NonPerformingDays int               `json:"non_performing_days" binding:"gte=1"`
+    Products          map[string]string `json:"products"`
+}
+
+// InterestAccrualRun is one pass bringing every loan's interest income to what it had earned by a month-end
+type InterestAccrualRun struct {
+    BaseModel
+    Month         string    `gorm:"size:7;not null;index" json:"month"` // YYYY-MM
+    PeriodEnd     time.Time `gorm:"not null" json:"period_end"`
+    Loans         int       `json:"loans"`          // Loans whose interest was checked
+    NonPerforming int       `json:"non_performing"` // Of those, loans on a cash basis
+    Accrued       float64   `gorm:"type:decimal(12,2);default:0" json:"accrued"`
+    Reversed      float64   `gorm:"type:decimal(12,2);default:0" json:"reversed"`
+    Entries       int       `json:"entries"`
+    RunBy         string    `gorm:"size:50" json:"run_by"`
+}
+
+func (InterestAccrualRun) TableName() string {
+    return "interest_accrual_runs"
+}
+
+// InterestAccrualRunRequest accrues interest through the end of a month that has ended, given as YYYY-MM
+type InterestAccrualRunRequest struct {
+    Month string `json:"month" binding:"required"`
+}
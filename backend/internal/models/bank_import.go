@@ -0,0 +1,94 @@
+package models
+
+import (
+    "time"
+)
+
+// Bank transaction states after matching
+const (
+    BankTxnPosted    = "posted"    // Auto-matched and posted
+    BankTxnReview    = "review"    // Plausible matches found, waiting for a reviewer to pick one
+    BankTxnUnmatched = "unmatched" // No plausible loan
+    BankTxnDuplicate = "duplicate" // Same line already imported from an earlier statement
+    BankTxnConfirmed = "confirmed" // Posted by a reviewer
+    BankTxnIgnored   = "ignored"   // Reviewed and not a loan payment
+)
+
+// BankImport is one uploaded bank statement
+type BankImport struct {
+    BaseModel
+    FileName       string            `gorm:"size:255" json:"file_name"`
+    ImportedBy     string            `gorm:"size:50" json:"imported_by"`
+    ImportedByID   *uint             `json:"imported_by_id"`
+    Mapping        string            `gorm:"type:text" json:"mapping"` // Column mapping used, as JSON
+    RowCount       int               `json:"row_count"`
+    CreditCount    int               `json:"credit_count"`
+    PostedCount    int               `json:"posted_count"`
+    ReviewCount    int               `json:"review_count"`
+    UnmatchedCount int               `json:"unmatched_count"`
+    DuplicateCount int               `json:"duplicate_count"`
+    TotalCredits   float64           `gorm:"type:decimal(12,2)" json:"total_credits"`
+    Transactions   []BankTransaction `gorm:"foreignKey:ImportID" json:"transactions,omitempty"`
+}
+
+func (BankImport) TableName() string {
+    return "bank_imports"
+}
+
+// BankTransaction is one credit line from a bank statement and how it was matched
+type BankTransaction struct {
+    BaseModel
+    ImportID    uint       `gorm:"not null;index" json:"import_id"`
+    RowNumber   int        `json:"row_number"`
+    Date        time.Time  `gorm:"index" json:"date"`
+    Description string     `gorm:"size:255" json:"description"`
+    Reference   string     `gorm:"size:100;index" json:"reference"`
+    Amount      float64    `gorm:"type:decimal(12,2)" json:"amount"`
+    Status      string     `gorm:"size:20;index" json:"status"`
+    Reason      string     `gorm:"size:255" json:"reason,omitempty"`
+    Candidates  string     `gorm:"type:text" json:"candidates,omitempty"` // Ranked BankMatchCandidate list as JSON
+    LoanID      *uint      `gorm:"index" json:"loan_id"`
+    PaymentID   *uint      `json:"payment_id"`
+    ReviewedBy  string     `gorm:"size:50" json:"reviewed_by,omitempty"`
+    ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+    ReviewNotes string     `gorm:"type:text" json:"review_notes,omitempty"`
+}
+
+func (BankTransaction) TableName() string {
+    return "bank_transactions"
+}
+
+// BankColumnMapping says which statement columns hold which fields. Columns are given
+// by header name, or by 1-based position when the file has no header row.
+type BankColumnMapping struct {
+    Date        string `json:"date" binding:"required"`
+    Description string `json:"description"`
+    Reference   string `json:"reference"`
+    Amount      string `json:"amount"` // Signed amount; positive is a credit
+    Credit      string `json:"credit"` // Or separate credit and debit columns
+    Debit       string `json:"debit"`
+    DateFormat  string `json:"date_format"` // Go layout; common formats are tried when empty
+    NoHeader    bool   `json:"no_header"`
+    Delimiter   string `json:"delimiter"` // Defaults to a comma
+    SkipRows    int    `json:"skip_rows"` // Bank letterhead lines above the header
+}
+
+// BankMatchCandidate is a loan a bank credit may belong to, with the evidence for it
+type BankMatchCandidate struct {
+    LoanID            uint     `json:"loan_id"`
+    LoanControlNumber string   `json:"loan_control_number"`
+    ClientName        string   `json:"client_name"`
+    Score             int      `json:"score"`
+    Reasons           []string `json:"reasons"`
+}
+
+// BankTransactionConfirmRequest posts a transaction to a loan; an empty loan ID takes the top candidate
+type BankTransactionConfirmRequest struct {
+    LoanID uint   `json:"loan_id"`
+    Notes  string `json:"notes"`
+}
+
+// BankTransactionIgnoreRequest closes a transaction without posting it
+type BankTransactionIgnoreRequest struct {
+    Notes string `json:"notes" binding:"required"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// Branch is an office that clients, loans and collectors belong to
+type Branch struct {
+    BaseModel
+    Code    string `gorm:"uniqueIndex;not null;size:20" json:"code"`
+    Name    string `gorm:"not null;size:100" json:"name"`
+    Address string `gorm:"type:text" json:"address"`
+}
+
+func (Branch) TableName() string {
+    return "branches"
+}
+
+type BranchRequest struct {
+    Code    string `json:"code" binding:"required"`
+    Name    string `json:"name" binding:"required"`
+    Address string `json:"address"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+    "time"
+)
+
+// Cashier session states
+const (
+    CashierSessionOpen     = "Open"
+    CashierSessionClosed   = "Closed"
+    CashierSessionReviewed = "Reviewed" // Variance looked at by the branch manager
+)
+
+// CashierSession is one cashier's drawer from opening count to end-of-day count.
+// Totals cover payments the cashier received and loans they released while the session was open.
+type CashierSession struct {
+    BaseModel
+    UserID                uint       `gorm:"not null;index" json:"user_id"`
+    Username              string     `gorm:"size:50" json:"username"`
+    BranchID              *uint      `gorm:"index" json:"branch_id"`
+    Status                string     `gorm:"size:20;default:'Open';index" json:"status"`
+    OpenedAt              time.Time  `gorm:"not null" json:"opened_at"`
+    ClosedAt              *time.Time `json:"closed_at"`
+    OpeningBalance        float64    `gorm:"type:decimal(12,2);default:0" json:"opening_balance"`
+    PaymentsReceived      float64    `gorm:"type:decimal(12,2);default:0" json:"payments_received"`  // Cash only
+    NonCashReceived       float64    `gorm:"type:decimal(12,2);default:0" json:"non_cash_received"` // E-wallet, bank, check
+    PaymentCount          int        `gorm:"default:0" json:"payment_count"`
+    DisbursementsReleased float64    `gorm:"type:decimal(12,2);default:0" json:"disbursements_released"`
+    DisbursementCount     int        `gorm:"default:0" json:"disbursement_count"`
+    ExpectedClosing       float64    `gorm:"type:decimal(12,2);default:0" json:"expected_closing"`
+    ActualCount           *float64   `gorm:"type:decimal(12,2)" json:"actual_count"`
+    Variance              float64    `gorm:"type:decimal(12,2);default:0" json:"variance"` // Actual count less expected; negative is a shortage
+    Flagged               bool       `gorm:"default:false;index" json:"flagged"`
+    Notes                 string     `gorm:"type:text" json:"notes"`
+    ReviewedBy            string     `gorm:"size:50" json:"reviewed_by,omitempty"`
+    ReviewedAt            *time.Time `json:"reviewed_at,omitempty"`
+    ReviewNotes           string     `gorm:"type:text" json:"review_notes,omitempty"`
+}
+
+func (CashierSession) TableName() string {
+    return "cashier_sessions"
+}
+
+type CashierSessionOpenRequest struct {
+    OpeningBalance float64 `json:"opening_balance" binding:"gte=0"`
+    BranchID       *uint   `json:"branch_id"`
+    Notes          string  `json:"notes"`
+}
+
+type CashierSessionCloseRequest struct {
+    ActualCount *float64 `json:"actual_count" binding:"required,gte=0"`
+    Notes       string   `json:"notes"`
+}
+
+type CashierSessionReviewRequest struct {
+    Notes string `json:"notes" binding:"required"`
+}
+
+// CashierSessionFilter narrows the session list
+type CashierSessionFilter struct {
+    UserID   *uint
+    BranchID *uint
+    Status   string
+    Flagged  bool
+}
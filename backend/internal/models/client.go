@@ -20,6 +20,9 @@ type Client struct {
     FacebookAccount   string    `gorm:"size:100" json:"facebook_account"`
     Age               int       `json:"age"`
     ContactNumber     string    `gorm:"size:20" json:"contact_number"`
+    BranchID          *uint     `gorm:"index" json:"branch_id"`
+    Area              string    `gorm:"size:100;index" json:"area"` // Barangay or route the collector covers
+    Version           int       `gorm:"not null;default:1" json:"version"` // Bumped on every write, for optimistic locking
     
     // Relationships - use slices instead of pointers
     IncomeInfo    []IncomeInfo    `gorm:"foreignKey:ClientID" json:"income_info,omitempty"`
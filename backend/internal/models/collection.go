@@ -0,0 +1,85 @@
+package models
+
+// CollectionSheetLine is one borrower a collector visits on the sheet date
+type CollectionSheetLine struct {
+    LoanID             uint    `json:"loan_id"`
+    LoanControlNumber  string  `json:"loan_control_number"`
+    ClientID           uint    `json:"client_id"`
+    ClientName         string  `json:"client_name"`
+    Address            string  `json:"address"`
+    ContactNumber      string  `json:"contact_number"`
+    InstallmentNumber  int     `json:"installment_number"`
+    Ammortization      float64 `json:"ammortization"`
+    CurrentWeekBalance float64 `json:"current_week_balance"` // Amortization less partial payments on the current installment
+    Arrears            float64 `json:"arrears"`               // Unpaid installments due before the sheet date
+    DaysPastDue        int     `json:"days_past_due"`
+    DueToday           float64 `json:"due_today"`
+    TotalDue           float64 `json:"total_due"`
+    OutstandingBalance float64 `json:"outstanding_balance"`
+}
+
+// CollectionSheetArea groups sheet lines by the client's area
+type CollectionSheetArea struct {
+    Area     string                `json:"area"`
+    Lines    []CollectionSheetLine `json:"lines"`
+    TotalDue float64               `json:"total_due"`
+}
+
+// CollectionSheet is a collector's list of borrowers and amounts to collect on one day
+type CollectionSheet struct {
+    CollectorID   uint                  `json:"collector_id"`
+    CollectorName string                `json:"collector_name"`
+    Date          string                `json:"date"`
+    Areas         []CollectionSheetArea `json:"areas"`
+    TotalAccounts int                   `json:"total_accounts"`
+    TotalArrears  float64               `json:"total_arrears"`
+    TotalDueToday float64               `json:"total_due_today"`
+    TotalDue      float64               `json:"total_due"`
+}
+
+// Outcomes of one line in a posted collection batch
+const (
+    CollectionLinePosted     = "posted"
+    CollectionLineSkipped    = "skipped"
+    CollectionLineFailed     = "failed"
+    CollectionLineRolledBack = "rolled_back" // Would have posted, but another line failed
+)
+
+// CollectionBatchLine is the amount collected from one borrower on the sheet
+type CollectionBatchLine struct {
+    LoanID          uint    `json:"loan_id" binding:"required"`
+    AmountCollected float64 `json:"amount_collected"`
+}
+
+// CollectionBatchRequest posts a collection run's payments together
+type CollectionBatchRequest struct {
+    CollectorID    uint                  `json:"collector_id,omitempty"` // When set, every loan must be assigned to this collector
+    CollectionDate string                `json:"collection_date" binding:"required"`
+    CashRemitted   float64               `json:"cash_remitted"`
+    PaymentMethod  string                `json:"payment_method"`
+    Lines          []CollectionBatchLine `json:"lines" binding:"required,min=1,dive"`
+    ReceivedBy     *uint                 `json:"-"` // Cashier posting the remittance
+}
+
+// CollectionBatchLineResult reports what happened to one line of a batch
+type CollectionBatchLineResult struct {
+    LoanID          uint    `json:"loan_id"`
+    AmountCollected float64 `json:"amount_collected"`
+    Status          string  `json:"status,omitempty"` // Empty when the batch was rejected before posting
+    PaymentID       uint    `json:"payment_id,omitempty"`
+    WeekNumber      int     `json:"week_number,omitempty"`
+    IsPartial       bool    `json:"is_partial,omitempty"`
+    Error           string  `json:"error,omitempty"`
+}
+
+// CollectionBatchResult summarizes a batch; when Posted is false nothing was saved
+type CollectionBatchResult struct {
+    CollectionDate string                      `json:"collection_date"`
+    CashRemitted   float64                     `json:"cash_remitted"`
+    TotalCollected float64                     `json:"total_collected"`
+    Variance       float64                     `json:"variance"` // Cash remitted less total collected
+    Posted         bool                        `json:"posted"`
+    PostedCount    int                         `json:"posted_count"`
+    FailedCount    int                         `json:"failed_count"`
+    Lines          []CollectionBatchLineResult `json:"lines"`
+}
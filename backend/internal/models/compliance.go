@@ -0,0 +1,72 @@
+package models
+
+// What happens to a loan priced above the caps
+const (
+    ComplianceActionReject = "reject" // Refuse to book it unless an admin overrides
+    ComplianceActionFlag   = "flag"   // Book it and queue it for admin review
+)
+
+// Loan compliance states
+const (
+    ComplianceStatusCompliant  = "Compliant"
+    ComplianceStatusFlagged    = "Flagged"
+    ComplianceStatusOverridden = "Overridden"
+)
+
+// ComplianceSettings are the regulatory pricing caps applied to new loans.
+// Rates are monthly decimals (0.06 = 6%); a zero cap is not enforced.
+type ComplianceSettings struct {
+    BaseModel
+    MaxNominalMonthlyRate   float64 `gorm:"type:decimal(10,4);default:0" json:"max_nominal_monthly_rate"`
+    MaxEffectiveMonthlyRate float64 `gorm:"type:decimal(10,4);default:0" json:"max_effective_monthly_rate"` // Total cost of credit, including all deductions and fees
+    MaxFeeRate              float64 `gorm:"type:decimal(10,4);default:0" json:"max_fee_rate"`               // Fees as a share of principal
+    Action                  string  `gorm:"size:20;default:'reject'" json:"action"`
+    UpdatedBy               string  `gorm:"size:50" json:"updated_by"`
+}
+
+func (ComplianceSettings) TableName() string {
+    return "compliance_settings"
+}
+
+type ComplianceSettingsRequest struct {
+    MaxNominalMonthlyRate   float64 `json:"max_nominal_monthly_rate" binding:"gte=0"`
+    MaxEffectiveMonthlyRate float64 `json:"max_effective_monthly_rate" binding:"gte=0"`
+    MaxFeeRate              float64 `json:"max_fee_rate" binding:"gte=0"`
+    Action                  string  `json:"action" binding:"required"`
+}
+
+// ComplianceCheck is the result of testing a loan's pricing against the caps
+type ComplianceCheck struct {
+    NominalMonthlyRate   float64  `json:"nominal_monthly_rate"`
+    EffectiveMonthlyRate float64  `json:"effective_monthly_rate"`
+    FeeRate              float64  `json:"fee_rate"`
+    Compliant            bool     `json:"compliant"`
+    Violations           []string `json:"violations"`
+}
+
+// ComplianceOverride is the audit record of an admin accepting a loan priced above the caps
+type ComplianceOverride struct {
+    BaseModel
+    LoanID               uint    `gorm:"not null;index" json:"loan_id"`
+    Reason               string  `gorm:"type:text;not null" json:"reason"`
+    ApprovedBy           string  `gorm:"size:50;not null" json:"approved_by"`
+    ApprovedByID         uint    `json:"approved_by_id"`
+    NominalMonthlyRate   float64 `gorm:"type:decimal(10,4)" json:"nominal_monthly_rate"`
+    EffectiveMonthlyRate float64 `gorm:"type:decimal(10,4)" json:"effective_monthly_rate"`
+    FeeRate              float64 `gorm:"type:decimal(10,4)" json:"fee_rate"`
+    Violations           string  `gorm:"type:text" json:"violations"`
+
+    Loan *Loan `gorm:"foreignKey:LoanID" json:"loan,omitempty"`
+}
+
+func (ComplianceOverride) TableName() string {
+    return "compliance_overrides"
+}
+
+// ComplianceOverrideRequest accepts an above-cap loan. The approver is taken from the
+// authenticated admin, never from the request body.
+type ComplianceOverrideRequest struct {
+    Reason       string `json:"reason" binding:"required"`
+    ApprovedBy   string `json:"-"`
+    ApprovedByID uint   `json:"-"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Loan document types
+const (
+    DocumentTypeDisclosure     = "disclosure"
+    DocumentTypePromissoryNote = "promissory-note"
+)
+
+// DocumentTemplate holds the admin-editable wording of a generated loan document.
+// Body is a Go text/template rendered against the loan, client, co-makers and schedule.
+type DocumentTemplate struct {
+    BaseModel
+    Type      string `gorm:"uniqueIndex;not null;size:50" json:"type"`
+    Title     string `gorm:"not null;size:150" json:"title"`
+    Body      string `gorm:"type:text;not null" json:"body"`
+    UpdatedBy string `gorm:"size:50" json:"updated_by"`
+}
+
+func (DocumentTemplate) TableName() string {
+    return "document_templates"
+}
+
+// DocumentTemplateUpdateRequest is the payload admins send to change a template
+type DocumentTemplateUpdateRequest struct {
+    Title string `json:"title" binding:"required"`
+    Body  string `json:"body" binding:"required"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+    "time"
+)
+
+// Holiday kinds
+const (
+    HolidayTypeRegular    = "Regular"
+    HolidayTypeSpecial    = "Special"
+    HolidayTypeSuspension = "Suspension" // Work suspended, e.g. typhoon declarations
+)
+
+// Holiday is a non-working day on which no collections are due.
+// A nil BranchID applies to every branch; recurring holidays repeat on the same month and day each year.
+type Holiday struct {
+    BaseModel
+    BranchID  *uint     `gorm:"index" json:"branch_id"`
+    Name      string    `gorm:"not null;size:100" json:"name"`
+    Date      time.Time `gorm:"not null;index" json:"date"`
+    Recurring bool      `gorm:"default:false" json:"recurring"`
+    Type      string    `gorm:"size:20;default:'Regular'" json:"type"`
+    CreatedBy string    `gorm:"size:50" json:"created_by"`
+
+    Branch *Branch `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+}
+
+func (Holiday) TableName() string {
+    return "holidays"
+}
+
+type HolidayRequest struct {
+    BranchID  *uint  `json:"branch_id"`
+    Name      string `json:"name" binding:"required"`
+    Date      string `json:"date" binding:"required"`
+    Recurring bool   `json:"recurring"`
+    Type      string `json:"type"`
+}
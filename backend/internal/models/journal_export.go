@@ -0,0 +1,70 @@
+package models
+
+import (
+    "time"
+)
+
+// Journal export formats
+const (
+    JournalExportCSV  = "csv"  // Generic journal, one row per line
+    JournalExportIIF  = "iif"  // QuickBooks Desktop general journal transactions
+    JournalExportXero = "xero" // Xero manual journal import
+)
+
+// LedgerAccountMapping is the code and name an account goes by in the external accounting software.
+// Accounts without a mapping are exported under their own code and name.
+type LedgerAccountMapping struct {
+    BaseModel
+    AccountCode  string `gorm:"size:20;uniqueIndex;not null" json:"account_code"`
+    ExternalCode string `gorm:"size:50" json:"external_code"`
+    ExternalName string `gorm:"size:100" json:"external_name"`
+}
+
+func (LedgerAccountMapping) TableName() string {
+    return "ledger_account_mappings"
+}
+
+// LedgerAccountMappingLine maps one account; leaving both external fields blank removes the mapping
+type LedgerAccountMappingLine struct {
+    AccountCode  string `json:"account_code" binding:"required"`
+    ExternalCode string `json:"external_code"`
+    ExternalName string `json:"external_name"`
+}
+
+// LedgerAccountMappingRequest replaces the whole account mapping
+type LedgerAccountMappingRequest struct {
+    Mappings []LedgerAccountMappingLine `json:"mappings" binding:"dive"`
+}
+
+// JournalExportBatch is one export of journal entries and the file produced. Every entry exported is
+// marked with its batch, so later exports of an overlapping range only pick up entries posted since.
+// Voiding a batch, e.g. when the import was rejected, frees its entries to be exported again.
+type JournalExportBatch struct {
+    BaseModel
+    Format      string     `gorm:"size:10;not null" json:"format"`
+    PeriodStart time.Time  `gorm:"not null" json:"period_start"`
+    PeriodEnd   time.Time  `gorm:"not null" json:"period_end"`
+    Entries     int        `json:"entries"`
+    TotalDebit  float64    `gorm:"type:decimal(14,2);default:0" json:"total_debit"`
+    Content     string     `gorm:"type:text" json:"-"`
+    ExportedBy  string     `gorm:"size:50" json:"exported_by"`
+    VoidedAt    *time.Time `json:"voided_at,omitempty"`
+    VoidedBy    string     `gorm:"size:50" json:"voided_by,omitempty"`
+    VoidReason  string     `gorm:"size:255" json:"void_reason,omitempty"`
+}
+
+func (JournalExportBatch) TableName() string {
+    return "journal_export_batches"
+}
+
+// JournalExportRequest exports the entries dated in a range that no earlier batch exported
+type JournalExportRequest struct {
+    From   string `json:"from" binding:"required"`
+    To     string `json:"to" binding:"required"`
+    Format string `json:"format" binding:"required,oneof=csv iif xero"`
+}
+
+// JournalExportVoidRequest voids a batch so its entries can be exported again
+type JournalExportVoidRequest struct {
+    Reason string `json:"reason" binding:"required"`
+}
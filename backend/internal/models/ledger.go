@@ -0,0 +1,265 @@
+package models
+
+import (
+    "time"
+)
+
+// Ledger account types
+const (
+    AccountTypeAsset     = "Asset"
+    AccountTypeLiability = "Liability"
+    AccountTypeEquity    = "Equity"
+    AccountTypeIncome    = "Income"
+    AccountTypeExpense   = "Expense"
+)
+
+// Accounts the platform posts to automatically
+const (
+    AccountCashOnHand         = "1000"
+    AccountCashInBank         = "1010" // Bank, e-wallet and check receipts
+    AccountLoansReceivable    = "1100" // Gross of interest and financed charges, like the loan's outstanding balance
+    AccountUnearnedInterest   = "1105" // Contra to loans receivable until the interest is earned
+    AccountChargesReceivable  = "1110" // Penalties and fees added after release
+    AccountLoanLossAllowance  = "1190" // Contra to loans receivable, set by month-end provisioning
+    AccountClientOverpayments = "2000"
+    AccountCapital            = "3000"
+    AccountRetainedEarnings   = "3100"
+    AccountInterestIncome     = "4000"
+    AccountServiceFeeIncome   = "4100"
+    AccountPenaltyIncome      = "4200"
+    AccountRecoveries         = "4300" // Collections on written-off loans
+    AccountLoanLosses         = "5000"
+    AccountProvisionExpense   = "5100"
+    AccountBalanceAdjustments = "5900" // Outstanding balances edited by hand
+)
+
+// Journal entry sources
+const (
+    JournalSourceRelease    = "Release"
+    JournalSourceCharge     = "Charge"
+    JournalSourcePayment    = "Payment"
+    JournalSourceWriteOff   = "WriteOff"
+    JournalSourceAdjustment = "Adjustment"
+    JournalSourceReversal   = "Reversal"
+    JournalSourceManual     = "Manual"
+    JournalSourceProvision  = "Provision"
+    JournalSourceAccrual    = "Accrual"
+)
+
+// LedgerAccount is one account in the chart of accounts. Contra accounts carry the opposite
+// balance to their type, e.g. unearned interest is a credit-balance asset.
+type LedgerAccount struct {
+    BaseModel
+    Code        string `gorm:"size:20;uniqueIndex;not null" json:"code"`
+    Name        string `gorm:"size:100;not null" json:"name"`
+    Type        string `gorm:"size:20;not null" json:"type"`
+    Contra      bool   `gorm:"default:false" json:"contra"`
+    System      bool   `gorm:"default:false" json:"system"` // Posted to automatically; cannot be changed
+    Description string `gorm:"size:255" json:"description"`
+}
+
+func (LedgerAccount) TableName() string {
+    return "ledger_accounts"
+}
+
+// DebitNormal reports whether the account's balance is normally a debit
+func (a LedgerAccount) DebitNormal() bool {
+    debit := a.Type == AccountTypeAsset || a.Type == AccountTypeExpense
+    return debit != a.Contra
+}
+
+// JournalEntry is one balanced posting. Automatic entries carry the loan and payment they came from
+// and a source key that keeps them from being posted twice; entries are never edited or deleted,
+// only reversed by an opposite entry.
+type JournalEntry struct {
+    BaseModel
+    EntryDate     time.Time     `gorm:"not null;index" json:"entry_date"`
+    Source        string        `gorm:"size:20;not null;index" json:"source"`
+    SourceKey     *string       `gorm:"size:100;uniqueIndex" json:"source_key,omitempty"`
+    Reference     string        `gorm:"size:50" json:"reference"`
+    Description   string        `gorm:"size:255" json:"description"`
+    LoanID        *uint         `gorm:"index" json:"loan_id,omitempty"`
+    PaymentID     *uint         `gorm:"index" json:"payment_id,omitempty"`
+    ReversesID    *uint         `gorm:"index" json:"reverses_id,omitempty"`
+    ReversedByID  *uint         `json:"reversed_by_id,omitempty"`
+    PostedBy      string        `gorm:"size:50" json:"posted_by"`
+    ExportBatchID *uint         `gorm:"index" json:"export_batch_id,omitempty"` // Set once exported to external accounting software
+    Lines         []JournalLine `gorm:"foreignKey:EntryID" json:"lines,omitempty"`
+}
+
+func (JournalEntry) TableName() string {
+    return "journal_entries"
+}
+
+// JournalLine is one debit or credit of an entry. Lines on loan accounts carry the loan
+// so the ledger can be tied out to each loan's balance.
+type JournalLine struct {
+    ID          uint    `gorm:"primaryKey" json:"id"`
+    EntryID     uint    `gorm:"not null;index" json:"entry_id"`
+    AccountCode string  `gorm:"size:20;not null;index" json:"account_code"`
+    LoanID      *uint   `gorm:"index" json:"loan_id,omitempty"`
+    Debit       float64 `gorm:"type:decimal(12,2);default:0" json:"debit"`
+    Credit      float64 `gorm:"type:decimal(12,2);default:0" json:"credit"`
+    Memo        string  `gorm:"size:255" json:"memo,omitempty"`
+}
+
+func (JournalLine) TableName() string {
+    return "journal_lines"
+}
+
+// LedgerPeriodClose locks the books through the end of a period: nothing can be posted on or before
+// that day until the close is reopened. Loan events dated in a closed period post on the next open day.
+type LedgerPeriodClose struct {
+    BaseModel
+    PeriodStart  time.Time  `gorm:"not null" json:"period_start"`
+    PeriodEnd    time.Time  `gorm:"not null;index" json:"period_end"`
+    NetIncome    float64    `gorm:"type:decimal(12,2);default:0" json:"net_income"` // For the period, when it was closed
+    ClosedBy     string     `gorm:"size:50" json:"closed_by"`
+    Notes        string     `gorm:"size:255" json:"notes"`
+    ReopenedAt   *time.Time `json:"reopened_at,omitempty"`
+    ReopenedBy   string     `gorm:"size:50" json:"reopened_by,omitempty"`
+    ReopenReason string     `gorm:"size:255" json:"reopen_reason,omitempty"`
+}
+
+func (LedgerPeriodClose) TableName() string {
+    return "ledger_period_closes"
+}
+
+// LedgerAccountRequest adds an account to the chart
+type LedgerAccountRequest struct {
+    Code        string `json:"code" binding:"required"`
+    Name        string `json:"name" binding:"required"`
+    Type        string `json:"type" binding:"required"`
+    Contra      bool   `json:"contra"`
+    Description string `json:"description"`
+}
+
+// JournalLineRequest is one line of a manual entry; exactly one of debit and credit is set
+type JournalLineRequest struct {
+    AccountCode string  `json:"account_code" binding:"required"`
+    Debit       float64 `json:"debit"`
+    Credit      float64 `json:"credit"`
+    Memo        string  `json:"memo"`
+}
+
+// JournalEntryRequest posts a manual entry, e.g. capital paid in or an operating expense
+type JournalEntryRequest struct {
+    EntryDate   string               `json:"entry_date" binding:"required"`
+    Reference   string               `json:"reference"`
+    Description string               `json:"description" binding:"required"`
+    Lines       []JournalLineRequest `json:"lines" binding:"required,min=2,dive"`
+}
+
+// JournalReversalRequest reverses an entry on a given day, today when blank
+type JournalReversalRequest struct {
+    EntryDate string `json:"entry_date"`
+    Reason    string `json:"reason" binding:"required"`
+}
+
+// LedgerPeriodCloseRequest closes the books for a month that has ended, given as YYYY-MM
+type LedgerPeriodCloseRequest struct {
+    Month string `json:"month" binding:"required"`
+    Notes string `json:"notes"`
+}
+
+// LedgerPeriodReopenRequest reopens the most recently closed month
+type LedgerPeriodReopenRequest struct {
+    Reason string `json:"reason" binding:"required"`
+}
+
+// JournalEntryFilter narrows the journal listing
+type JournalEntryFilter struct {
+    From   *time.Time
+    To     *time.Time // Exclusive
+    Source string
+    LoanID *uint
+}
+
+// AccountBalance is the total debits and credits posted to an account
+type AccountBalance struct {
+    AccountCode string
+    Debit       float64
+    Credit      float64
+}
+
+// TrialBalanceLine is one account's balance, shown on its debit or credit side
+type TrialBalanceLine struct {
+    Code   string  `json:"code"`
+    Name   string  `json:"name"`
+    Type   string  `json:"type"`
+    Debit  float64 `json:"debit"`
+    Credit float64 `json:"credit"`
+}
+
+// LoanTieOut is a loan whose receivable in the ledger differs from its outstanding balance
+type LoanTieOut struct {
+    LoanID        uint    `json:"loan_id"`
+    ControlNumber string  `json:"control_number"`
+    Ledger        float64 `json:"ledger"`
+    Subledger     float64 `json:"subledger"`
+    Difference    float64 `json:"difference"`
+}
+
+// SubledgerTieOut compares loans and charges receivable in the ledger with the outstanding balances
+// of every loan that is neither deleted nor written off
+type SubledgerTieOut struct {
+    LedgerReceivable     float64      `json:"ledger_receivable"`
+    SubledgerOutstanding float64      `json:"subledger_outstanding"`
+    Difference           float64      `json:"difference"`
+    TiedOut              bool         `json:"tied_out"`
+    Loans                int          `json:"loans"`
+    Mismatches           []LoanTieOut `json:"mismatches"`
+}
+
+// TrialBalance lists every account's balance at the end of a day
+type TrialBalance struct {
+    AsOf        string             `json:"as_of"`
+    Lines       []TrialBalanceLine `json:"lines"`
+    TotalDebit  float64            `json:"total_debit"`
+    TotalCredit float64            `json:"total_credit"`
+    Balanced    bool               `json:"balanced"`
+    Subledger   *SubledgerTieOut   `json:"subledger,omitempty"` // Only for the current position
+    GeneratedAt string             `json:"generated_at"`
+}
+
+// FinancialStatementLine is one account's amount on a statement, positive on its normal side
+type FinancialStatementLine struct {
+    Code   string  `json:"code"`
+    Name   string  `json:"name"`
+    Amount float64 `json:"amount"`
+}
+
+// FinancialStatementSection is a titled group of accounts on a statement with its total
+type FinancialStatementSection struct {
+    Title string                   `json:"title"`
+    Lines []FinancialStatementLine `json:"lines"`
+    Total float64                  `json:"total"`
+}
+
+// IncomeStatement is income and expenses posted over a range of days
+type IncomeStatement struct {
+    From        string                    `json:"from"`
+    To          string                    `json:"to"`
+    Income      FinancialStatementSection `json:"income"`
+    Expenses    FinancialStatementSection `json:"expenses"`
+    NetIncome   float64                   `json:"net_income"`
+    GeneratedAt string                    `json:"generated_at"`
+}
+
+// BalanceSheet is the financial position at the end of a day. Income and expenses are not closed
+// into retained earnings by entries, so everything earned to date shows as its own equity line.
+type BalanceSheet struct {
+    AsOf                      string                    `json:"as_of"`
+    Assets                    FinancialStatementSection `json:"assets"`
+    Liabilities               FinancialStatementSection `json:"liabilities"`
+    Equity                    FinancialStatementSection `json:"equity"`
+    TotalLiabilitiesAndEquity float64                   `json:"total_liabilities_and_equity"`
+    Balanced                  bool                      `json:"balanced"`
+    GeneratedAt               string                    `json:"generated_at"`
+}
+
+// LedgerSyncResult summarizes one pass posting loan events the ledger has not seen yet
+type LedgerSyncResult struct {
+    LoansChecked  int `json:"loans_checked"`
+    EntriesPosted int `json:"entries_posted"`
+}
@@ -14,6 +14,20 @@ const (
     LoanStatusDefault LoanStatus = "Default"
 )
 
+// Repayment modes
+const (
+    LoanModeDaily       = "Daily"
+    LoanModeWeekly      = "Weekly"
+    LoanModeSemiMonthly = "Semi-Monthly"
+    LoanModeMonthly     = "Monthly"
+)
+
+// Interest computation methods
+const (
+    InterestMethodFlat        = "Flat"        // Add-on interest on the original principal
+    InterestMethodDiminishing = "Diminishing" // Interest on the declining balance
+)
+
 type Loan struct {
     BaseModel
     ClientID              uint      `gorm:"not null;index" json:"client_id"`
@@ -41,6 +55,16 @@ type Loan struct {
     NameCI                string    `gorm:"size:100" json:"name_ci"`
     NotedBy               string    `gorm:"size:100" json:"noted_by"`
     ApplicationDate       time.Time `json:"application_date"`
+    Principal             float64   `gorm:"type:decimal(10,2);default:0" json:"principal"`
+    InterestRate          float64   `gorm:"type:decimal(10,4);default:0" json:"interest_rate"`
+    InterestMethod        string    `gorm:"size:20;default:'Flat'" json:"interest_method"`
+    BranchID              *uint     `gorm:"index" json:"branch_id"`
+    EffectiveMonthlyRate  float64   `gorm:"type:decimal(10,4);default:0" json:"effective_monthly_rate"`
+    ComplianceStatus      string    `gorm:"size:20;default:'Compliant'" json:"compliance_status"`
+    CollectorID           *uint     `gorm:"index" json:"collector_id"` // User who collects in the field
+    OfficerID             *uint     `gorm:"index" json:"officer_id"` // Loan officer who recommended and manages the account
+    ReleasedByID          *uint     `gorm:"index" json:"released_by_id"` // User who released the proceeds
+    Version               int       `gorm:"not null;default:1" json:"version"` // Bumped on every write, for optimistic locking
     
     // Relationships
     Client     Client      `gorm:"foreignKey:ClientID" json:"client,omitempty"`
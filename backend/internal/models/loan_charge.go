@@ -0,0 +1,34 @@
+package models
+
+import (
+    "time"
+)
+
+type ChargeType string
+
+const (
+    ChargeTypePenalty ChargeType = "Penalty"
+    ChargeTypeFee     ChargeType = "Fee"
+)
+
+// LoanCharge is an amount added to a loan's balance after release, such as a late-payment penalty
+type LoanCharge struct {
+    BaseModel
+    LoanID      uint       `gorm:"not null;index" json:"loan_id"`
+    ChargeType  ChargeType `gorm:"size:20;not null" json:"charge_type"`
+    Amount      float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+    ChargeDate  time.Time  `gorm:"not null" json:"charge_date"`
+    Description string     `gorm:"size:255" json:"description"`
+    CreatedBy   string     `gorm:"size:50" json:"created_by"`
+}
+
+func (LoanCharge) TableName() string {
+    return "loan_charges"
+}
+
+type LoanChargeCreateRequest struct {
+    ChargeType  string  `json:"charge_type" binding:"required"`
+    Amount      float64 `json:"amount" binding:"required"`
+    ChargeDate  string  `json:"charge_date,omitempty"`
+    Description string  `json:"description"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+    "time"
+)
+
+// LoanDeduction is a single charge taken at release (service fee, insurance, etc.)
+type LoanDeduction struct {
+    Name   string  `json:"name"`
+    Amount float64 `json:"amount"`
+}
+
+// LoanSimulationRequest carries the inputs a loan officer quotes a borrower with
+type LoanSimulationRequest struct {
+    Principal      float64  `json:"principal" binding:"required"`
+    InterestRate   *float64 `json:"interest_rate,omitempty"`   // Monthly rate as a decimal (0.035 = 3.5%)
+    InterestMethod string   `json:"interest_method,omitempty"` // Flat or Diminishing
+    Mode           string   `json:"mode,omitempty"`            // Daily, Weekly, Semi-Monthly or Monthly
+    Terms          int      `json:"terms" binding:"required"`  // Loan term in months
+    DeductionType  string   `json:"deduction_type,omitempty"`  // With (deducted from release) or Without (added to amount payable)
+    // Deductions defaults to the standard service fee when omitted; send [] for none
+    Deductions    []LoanDeduction `json:"deductions"`
+    DateOfRelease string          `json:"date_of_release,omitempty"`
+    DueDate       string          `json:"due_date,omitempty"` // Collection weekday for weekly loans
+    BranchID      *uint           `json:"branch_id,omitempty"` // Due dates skip this branch's holidays
+}
+
+// Installment is one row of a loan's repayment schedule
+type Installment struct {
+    Number           int       `json:"number"`
+    DueDate          time.Time `json:"due_date"`
+    AmountDue        float64   `json:"amount_due"`
+    Principal        float64   `json:"principal"`
+    Interest         float64   `json:"interest"`
+    Charges          float64   `json:"charges"`
+    RemainingBalance float64   `json:"remaining_balance"`
+}
+
+// LoanSimulation is the computed quote for a LoanSimulationRequest
+type LoanSimulation struct {
+    Principal             float64         `json:"principal"`
+    InterestRate          float64         `json:"interest_rate"`
+    InterestMethod        string          `json:"interest_method"`
+    Mode                  string          `json:"mode"`
+    Terms                 int             `json:"terms"`
+    NumberOfInstallments  int             `json:"number_of_installments"`
+    DeductionType         string          `json:"deduction_type"`
+    Deductions            []LoanDeduction `json:"deductions"`
+    TotalDeductions       float64         `json:"total_deductions"`
+    TotalInterest         float64         `json:"total_interest"`
+    TotalAmount           float64         `json:"total_amount"`
+    Ammortization         float64         `json:"ammortization"`
+    NetRelease            float64         `json:"net_release"`
+    EffectiveInterestRate float64         `json:"effective_interest_rate"` // Effective monthly rate including deductions
+    EffectiveAnnualRate   float64         `json:"effective_annual_rate"`
+    Schedule              []Installment   `json:"schedule"`
+    Compliance            *ComplianceCheck `json:"compliance,omitempty"`
+}
@@ -26,6 +26,9 @@ type Payment struct {
     PaymentMethod   string        `json:"payment_method" gorm:"type:varchar(50)"`
     IsPartial       bool          `json:"is_partial" gorm:"default:false"`
     CompletesWeek   bool          `json:"completes_week" gorm:"default:false"`
+    SyncID          *string       `json:"sync_id,omitempty" gorm:"size:64;uniqueIndex"` // UUID from an offline device
+    ReceivedBy      *uint         `json:"received_by,omitempty" gorm:"index"`           // User who took the money
+    Version         int           `json:"version" gorm:"not null;default:1"`             // Bumped on every write, for optimistic locking
     CreatedAt       time.Time     `json:"created_at"`
     UpdatedAt       time.Time     `json:"updated_at"`
     DeletedAt       gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -45,6 +48,8 @@ type PaymentCreateRequest struct {
     PaymentMethod   string  `json:"payment_method" binding:"required"`
     IsPartial       bool    `json:"is_partial,omitempty"`
     CompletesWeek   bool    `json:"completes_week,omitempty"`
+    SyncID          *string `json:"-"`
+    ReceivedBy      *uint   `json:"-"` // Set from the signed-in user
 }
 type LoanUpdateRequest struct {
     ID                 uint     `json:"id"`
@@ -52,6 +57,9 @@ type LoanUpdateRequest struct {
     Status             string   `json:"status,omitempty"`
     PaymentPeriodWeeks *int     `json:"payment_period_weeks,omitempty"`
     DueDate            string   `json:"due_date,omitempty"`
+    CollectorID        *uint    `json:"collector_id,omitempty"` // 0 unassigns the collector
+    OfficerID          *uint    `json:"officer_id,omitempty"`   // 0 unassigns the loan officer
+    Version            int      `json:"version,omitempty"`      // Version the change was based on; 0 skips the check
 }
 
 
@@ -0,0 +1,108 @@
+package models
+
+import (
+    "time"
+)
+
+// ProvisioningSettings are the share of each loan's net balance held as an allowance for losses,
+// by days past due at month-end. Rates are decimals (0.25 = 25%).
+type ProvisioningSettings struct {
+    BaseModel
+    RateCurrent float64 `gorm:"type:decimal(10,4);default:0" json:"rate_current"`
+    Rate1To7    float64 `gorm:"column:rate_1_7;type:decimal(10,4);default:0" json:"rate_1_7"`
+    Rate8To30   float64 `gorm:"column:rate_8_30;type:decimal(10,4);default:0" json:"rate_8_30"`
+    Rate31To60  float64 `gorm:"column:rate_31_60;type:decimal(10,4);default:0" json:"rate_31_60"`
+    Rate61To90  float64 `gorm:"column:rate_61_90;type:decimal(10,4);default:0" json:"rate_61_90"`
+    RateOver90  float64 `gorm:"column:rate_over_90;type:decimal(10,4);default:0" json:"rate_over_90"`
+    UpdatedBy   string  `gorm:"size:50" json:"updated_by"`
+}
+
+func (ProvisioningSettings) TableName() string {
+    return "provisioning_settings"
+}
+
+// RateFor returns the provisioning rate for a PAR aging bucket
+func (p ProvisioningSettings) RateFor(bucket string) float64 {
+    switch bucket {
+    case ParBucket1To7:
+        return p.Rate1To7
+    case ParBucket8To30:
+        return p.Rate8To30
+    case ParBucket31To60:
+        return p.Rate31To60
+    case ParBucket61To90:
+        return p.Rate61To90
+    case ParBucketOver90:
+        return p.RateOver90
+    }
+    return p.RateCurrent
+}
+
+type ProvisioningSettingsRequest struct {
+    RateCurrent float64 `json:"rate_current" binding:"gte=0,lte=1"`
+    Rate1To7    float64 `json:"rate_1_7" binding:"gte=0,lte=1"`
+    Rate8To30   float64 `json:"rate_8_30" binding:"gte=0,lte=1"`
+    Rate31To60  float64 `json:"rate_31_60" binding:"gte=0,lte=1"`
+    Rate61To90  float64 `json:"rate_61_90" binding:"gte=0,lte=1"`
+    RateOver90  float64 `json:"rate_over_90" binding:"gte=0,lte=1"`
+}
+
+// ProvisionRun is one month-end calculation of the allowance for loan losses and the
+// entry that brought the ledger's allowance to it
+type ProvisionRun struct {
+    BaseModel
+    Month             string               `gorm:"size:7;not null;index" json:"month"` // YYYY-MM
+    PeriodEnd         time.Time            `gorm:"not null" json:"period_end"`
+    Loans             int                  `json:"loans"`
+    NetBalance        float64              `gorm:"type:decimal(12,2);default:0" json:"net_balance"` // Receivable less unearned interest
+    OpeningAllowance  float64              `gorm:"type:decimal(12,2);default:0" json:"opening_allowance"`
+    RequiredAllowance float64              `gorm:"type:decimal(12,2);default:0" json:"required_allowance"`
+    Adjustment        float64              `gorm:"type:decimal(12,2);default:0" json:"adjustment"` // Positive is charged to expense, negative released
+    EntryID           *uint                `json:"entry_id,omitempty"`
+    RunBy             string               `gorm:"size:50" json:"run_by"`
+    Buckets           []ProvisionRunBucket `gorm:"foreignKey:RunID" json:"buckets,omitempty"`
+}
+
+func (ProvisionRun) TableName() string {
+    return "provision_runs"
+}
+
+// ProvisionRunBucket is the allowance required for the loans in one aging bucket
+type ProvisionRunBucket struct {
+    ID         uint    `gorm:"primaryKey" json:"id"`
+    RunID      uint    `gorm:"not null;index" json:"run_id"`
+    Bucket     string  `gorm:"size:20;not null" json:"bucket"`
+    Rate       float64 `gorm:"type:decimal(10,4);default:0" json:"rate"`
+    Loans      int     `json:"loans"`
+    NetBalance float64 `gorm:"type:decimal(12,2);default:0" json:"net_balance"`
+    Required   float64 `gorm:"type:decimal(12,2);default:0" json:"required"`
+}
+
+func (ProvisionRunBucket) TableName() string {
+    return "provision_run_buckets"
+}
+
+// ProvisionRunRequest computes the allowance at the end of a month that has ended, given as YYYY-MM
+type ProvisionRunRequest struct {
+    Month string `json:"month" binding:"required"`
+}
+
+// ProvisionMovementLine is how the allowance moved over one month
+type ProvisionMovementLine struct {
+    Month    string  `json:"month"`
+    Opening  float64 `json:"opening"`
+    Charged  float64 `json:"charged"`  // Added to the allowance and expensed
+    Released float64 `json:"released"` // Taken back out of the allowance
+    Closing  float64 `json:"closing"`
+    Required float64 `json:"required"` // From the month's latest run; zero when it was not run
+    Run      bool    `json:"run"`
+}
+
+// ProvisionMovement is the allowance for loan losses month by month
+type ProvisionMovement struct {
+    From        string                  `json:"from"`
+    To          string                  `json:"to"`
+    Months      []ProvisionMovementLine `json:"months"`
+    Total       ProvisionMovementLine   `json:"total"`
+    GeneratedAt string                  `json:"generated_at"`
+}
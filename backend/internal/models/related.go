@@ -81,6 +81,9 @@ type ClientWithRelatedData struct {
     Siblings   []FamilyMember `json:"siblings,omitempty"`
     Spouse     *FamilyMember  `json:"spouse,omitempty"`
     Dependents []FamilyMember `json:"dependents,omitempty"`
+
+    // Saved with the loan when an admin accepted pricing above the compliance caps
+    ComplianceOverride *ComplianceOverride `json:"compliance_override,omitempty"`
 }
 
 // Family represents the family information structure
@@ -118,21 +121,23 @@ type ClientCreate struct {
     FacebookAccount   string    `json:"facebook_account"`
     Age               int       `json:"age" binding:"required"`
     ContactNumber     string    `json:"contact_number" binding:"required"`
+    BranchID          *uint     `json:"branch_id,omitempty"`
+    Area              string    `json:"area"`
 }
 
 // LoanCreate represents loan data for creation
 type LoanCreate struct {
     ControlNumber         string    `json:"control_number"`
     DateOfRelease         string    `json:"date_of_release"`
-    TotalAmount           float64   `json:"total_amount" binding:"required"`
-    Ammortization         float64   `json:"ammortization" binding:"required"`
+    TotalAmount           float64   `json:"total_amount" binding:"required_without=Principal"`
+    Ammortization         float64   `json:"ammortization" binding:"required_without=Principal"`
     Terms                 int       `json:"terms" binding:"required"`
     Mode                  string    `json:"mode"`
-    OutstandingBalance    float64   `json:"outstanding_balance" binding:"required"`
+    OutstandingBalance    float64   `json:"outstanding_balance" binding:"required_without=Principal"`
     Status                string    `json:"status"`
     DueDate               string    `json:"due_date"`
     Deductions            string    `json:"deductions"`
-    AmountRelease         float64   `json:"amount_release" binding:"required"`
+    AmountRelease         float64   `json:"amount_release" binding:"required_without=Principal"`
     PaymentPeriodWeeks    int       `json:"payment_period_weeks"`
     MethodOfPayment       string    `json:"method_of_payment"`
     CreditHistory         string    `json:"credit_history"`
@@ -145,6 +150,22 @@ type LoanCreate struct {
     NameCI                string    `json:"name_ci"` // ADDED THIS MISSING FIELD
     NotedBy               string    `json:"noted_by"`
     ApplicationDate       string    `json:"application_date"`
+
+    // When principal is set, amounts are computed by the loan calculator
+    Principal             float64   `json:"principal,omitempty"`
+    InterestRate          *float64  `json:"interest_rate,omitempty"`
+    InterestMethod        string    `json:"interest_method,omitempty"`
+    // Itemized release charges as quoted; the standard service fee when omitted, none for []
+    DeductionItems        []LoanDeduction `json:"deduction_items"`
+
+    // Defaults to the client's branch
+    BranchID              *uint     `json:"branch_id,omitempty"`
+    CollectorID           *uint     `json:"collector_id,omitempty"`
+    OfficerID             *uint     `json:"officer_id,omitempty"`
+    ReleasedByID          *uint     `json:"-"` // Set from the signed-in user
+
+    // Admin acceptance of pricing above the compliance caps
+    ComplianceOverride    *ComplianceOverrideRequest `json:"compliance_override,omitempty"`
 }
 
 // CoMakerCreate represents co-maker data for creation
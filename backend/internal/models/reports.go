@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type WeeklyReportData struct {
 	WeeklyPaymentTotal   float64 `json:"weekly_payment_total"`
 	WeeklyReleaseTotal   float64 `json:"weekly_release_total"`
@@ -12,6 +14,9 @@ type WeeklyReportData struct {
 
 type ReportResponse struct {
 	Data      WeeklyReportData `json:"data"`
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	Timezone  string           `json:"timezone"`
 	Timestamp string           `json:"timestamp"`
 	Status    string           `json:"status"`
 }
@@ -26,6 +31,7 @@ type HistoricalRecord struct {
 	ActiveClients  int64   `json:"active_clients"`
 	OverdueClients int64   `json:"overdue_clients"`
 	NetFlow        float64 `json:"net_flow"`
+	Source         string  `json:"source"` // "snapshot" when client counts come from the period's frozen snapshot
 }
 
 // HistoricalReportResponse represents the response for historical reports
@@ -39,9 +45,266 @@ type HistoricalReportResponse struct {
 // HistoryMetadata provides summary information about the historical data
 type HistoryMetadata struct {
 	PeriodType    string  `json:"period_type"`
+	Timezone      string  `json:"timezone"`
+	WeekStart     string  `json:"week_start"`
 	PeriodsCount  int     `json:"periods_count"`
 	AveragePayment float64 `json:"average_payment"`
 	AverageRelease float64 `json:"average_release"`
 	TotalPayments  float64 `json:"total_payments"`
 	TotalReleases  float64 `json:"total_releases"`
 }
+
+// PAR aging buckets, by days past due
+const (
+	ParBucketCurrent = "current"
+	ParBucket1To7    = "1-7"
+	ParBucket8To30   = "8-30"
+	ParBucket31To60  = "31-60"
+	ParBucket61To90  = "61-90"
+	ParBucketOver90  = "90+"
+)
+
+// ParBucketTotal is the part of a portfolio in one arrears bucket
+type ParBucketTotal struct {
+	Bucket      string  `json:"bucket"`
+	Loans       int     `json:"loans"`
+	Outstanding float64 `json:"outstanding"`
+	Percent     float64 `json:"percent"` // Of the group's outstanding balance
+}
+
+// ParSummary is portfolio at risk for the whole portfolio or one branch, officer or product.
+// Key is the value to pass back as a drill-down filter.
+type ParSummary struct {
+	Key         string           `json:"key"`
+	Name        string           `json:"name"`
+	Loans       int              `json:"loans"`
+	Outstanding float64          `json:"outstanding"`
+	AtRisk      float64          `json:"at_risk"` // Outstanding on loans at least one day past due
+	Par1        float64          `json:"par1"`    // Percent of outstanding more than 0 days past due
+	Par7        float64          `json:"par7"`    // More than 7 days
+	Par30       float64          `json:"par30"`   // More than 30 days
+	Buckets     []ParBucketTotal `json:"buckets"`
+}
+
+// ParReport is portfolio at risk as of one day, broken down by branch, loan officer and product
+type ParReport struct {
+	AsOf        string       `json:"as_of"`
+	Timezone    string       `json:"timezone"`
+	Portfolio   ParSummary   `json:"portfolio"`
+	ByBranch    []ParSummary `json:"by_branch"`
+	ByOfficer   []ParSummary `json:"by_officer"`
+	ByProduct   []ParSummary `json:"by_product"`
+	GeneratedAt string       `json:"generated_at"`
+}
+
+// ParLoan is one loan's arrears position in a PAR drill-down
+type ParLoan struct {
+	LoanID        uint       `json:"loan_id"`
+	ControlNumber string     `json:"control_number"`
+	ClientID      uint       `json:"client_id"`
+	ClientName    string     `json:"client_name"`
+	BranchID      uint       `json:"branch_id"`
+	BranchName    string     `json:"branch_name"`
+	OfficerID     uint       `json:"officer_id"`
+	OfficerName   string     `json:"officer_name"`
+	Product       string     `json:"product"`
+	Outstanding   float64    `json:"outstanding"`
+	AmountPastDue float64    `json:"amount_past_due"`
+	DaysPastDue   int        `json:"days_past_due"`
+	Bucket        string     `json:"bucket"`
+	OldestDueDate *time.Time `json:"oldest_due_date,omitempty"`
+}
+
+// ParLoanFilter narrows a PAR drill-down; a zero ID means unassigned, nil means any
+type ParLoanFilter struct {
+	Bucket    string `form:"bucket"`
+	BranchID  *uint  `form:"branch_id"`
+	OfficerID *uint  `form:"officer_id"`
+	Product   string `form:"product"`
+}
+
+// ParLoanList is the drill-down from a PAR figure to the loans behind it
+type ParLoanList struct {
+	AsOf        string    `json:"as_of"`
+	Timezone    string    `json:"timezone"`
+	Loans       []ParLoan `json:"loans"`
+	Count       int       `json:"count"`
+	Outstanding float64   `json:"outstanding"`
+	PastDue     float64   `json:"past_due"`
+}
+
+// CollectionEfficiency compares what fell due in a period with what was collected.
+// Collections are applied to installments oldest first: the part paying installments due in
+// the period is current, the part paying earlier installments is past-due recovery, and the
+// part paying later installments is advance.
+type CollectionEfficiency struct {
+	Key               string  `json:"key"`
+	Name              string  `json:"name"`
+	Loans             int     `json:"loans"`
+	DueInPeriod       float64 `json:"due_in_period"`
+	PastDueAtStart    float64 `json:"past_due_at_start"`
+	CurrentCollected  float64 `json:"current_collected"`
+	PastDueCollected  float64 `json:"past_due_collected"`
+	AdvanceCollected  float64 `json:"advance_collected"`
+	TotalCollected    float64 `json:"total_collected"`
+	CurrentEfficiency float64 `json:"current_efficiency"` // Percent of amounts due in the period collected
+	OverallEfficiency float64 `json:"overall_efficiency"` // Percent of amounts due including earlier arrears collected
+}
+
+// CollectionEfficiencyPeriod is one week of the collection efficiency trend
+type CollectionEfficiencyPeriod struct {
+	Period            string  `json:"period"`
+	StartDate         string  `json:"start_date"`
+	EndDate           string  `json:"end_date"`
+	DueInPeriod       float64 `json:"due_in_period"`
+	PastDueAtStart    float64 `json:"past_due_at_start"`
+	CurrentCollected  float64 `json:"current_collected"`
+	PastDueCollected  float64 `json:"past_due_collected"`
+	AdvanceCollected  float64 `json:"advance_collected"`
+	CurrentEfficiency float64 `json:"current_efficiency"`
+	OverallEfficiency float64 `json:"overall_efficiency"`
+}
+
+// CollectionEfficiencyReport is collection efficiency for a period, per collector and branch, with a weekly trend
+type CollectionEfficiencyReport struct {
+	From        string                       `json:"from"`
+	To          string                       `json:"to"`
+	Timezone    string                       `json:"timezone"`
+	Portfolio   CollectionEfficiency         `json:"portfolio"`
+	ByCollector []CollectionEfficiency       `json:"by_collector"`
+	ByBranch    []CollectionEfficiency       `json:"by_branch"`
+	Trend       []CollectionEfficiencyPeriod `json:"trend"`
+	GeneratedAt string                       `json:"generated_at"`
+}
+
+// OfficerPerformance is one loan officer's portfolio at the end of a period and their results during it.
+// Borrowers are new when the loan released in the period was their first, and a dropout is a client
+// whose loan was paid off in the period without a newer loan released by its end.
+type OfficerPerformance struct {
+	OfficerID         uint                `json:"officer_id"`
+	Name              string              `json:"name"`
+	ActiveBorrowers   int                 `json:"active_borrowers"`
+	Outstanding       float64             `json:"outstanding"`
+	LoansReleased     int                 `json:"loans_released"`
+	Disbursed         float64             `json:"disbursed"`
+	NewClients        int                 `json:"new_clients"`
+	RepeatClients     int                 `json:"repeat_clients"`
+	Par1              float64             `json:"par1"`
+	Par30             float64             `json:"par30"`
+	CurrentEfficiency float64             `json:"current_efficiency"`
+	OverallEfficiency float64             `json:"overall_efficiency"`
+	LoansCompleted    int                 `json:"loans_completed"`
+	Dropouts          int                 `json:"dropouts"`
+	DropoutRate       float64             `json:"dropout_rate"` // Percent of completed loans not renewed
+	Trend             []OfficerTrendPoint `json:"trend,omitempty"`
+}
+
+// OfficerTrendPoint is an officer's headline figures for one earlier period
+type OfficerTrendPoint struct {
+	Period            string  `json:"period"`
+	StartDate         string  `json:"start_date"`
+	EndDate           string  `json:"end_date"`
+	ActiveBorrowers   int     `json:"active_borrowers"`
+	Outstanding       float64 `json:"outstanding"`
+	Disbursed         float64 `json:"disbursed"`
+	Par30             float64 `json:"par30"`
+	CurrentEfficiency float64 `json:"current_efficiency"`
+	DropoutRate       float64 `json:"dropout_rate"`
+}
+
+// OfficerPerformanceReport compares loan officers over a period, with a trend of earlier periods
+type OfficerPerformanceReport struct {
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	Timezone    string               `json:"timezone"`
+	PeriodType  string               `json:"period_type"` // Trend periods: weekly or monthly
+	Portfolio   OfficerPerformance   `json:"portfolio"`
+	Officers    []OfficerPerformance `json:"officers"`
+	GeneratedAt string               `json:"generated_at"`
+}
+
+// CohortMonth is where a cohort stood at the end of one month on book. Month 0 is the release month.
+// Rates are percentages of the cohort's total amount due.
+type CohortMonth struct {
+	MonthOnBook  int     `json:"month_on_book"`
+	AsOf         string  `json:"as_of"`
+	Repaid       float64 `json:"repaid"` // Cumulative
+	RepaidRate   float64 `json:"repaid_rate"`
+	Outstanding  float64 `json:"outstanding"`
+	Par30        float64 `json:"par30"`
+	DefaultLoans int     `json:"default_loans"` // More than 90 days past due
+	DefaultRate  float64 `json:"default_rate"`
+}
+
+// Cohort is the loans of one product released in one month, followed month by month
+type Cohort struct {
+	Cohort          string        `json:"cohort"` // Release month, YYYY-MM
+	Product         string        `json:"product"`
+	Loans           int           `json:"loans"`
+	Disbursed       float64       `json:"disbursed"`
+	TotalDue        float64       `json:"total_due"`
+	WrittenOffLoans int           `json:"written_off_loans"` // Loans now marked Default
+	WrittenOffRate  float64       `json:"written_off_rate"`
+	Months          []CohortMonth `json:"months"`
+}
+
+// CohortReport is a vintage analysis of loans released between two months
+type CohortReport struct {
+	From        string   `json:"from"` // First release month, YYYY-MM
+	To          string   `json:"to"`
+	Timezone    string   `json:"timezone"`
+	MaxMonths   int      `json:"max_months"`
+	Cohorts     []Cohort `json:"cohorts"`
+	GeneratedAt string   `json:"generated_at"`
+}
+
+// CashFlowLine is projected cash in and out over one day or week. Scheduled is what borrowers owe on
+// installments falling due, Expected is that scaled by each branch's historical collection efficiency,
+// and Releases are loans booked to be released on those days.
+type CashFlowLine struct {
+	Period    string  `json:"period"`
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date"`
+	Scheduled float64 `json:"scheduled"`
+	Expected  float64 `json:"expected"`
+	Releases  float64 `json:"releases"`
+	NetFlow   float64 `json:"net_flow"`      // Expected less releases
+	Position  float64 `json:"cash_position"` // Opening cash plus net flow to the end of the line
+}
+
+// CashFlowBranch is one branch's share of the forecast and the efficiency applied to its schedule
+type CashFlowBranch struct {
+	BranchID   uint    `json:"branch_id"`
+	BranchName string  `json:"branch_name"`
+	Efficiency float64 `json:"efficiency"` // Current collection efficiency over the lookback weeks
+	Scheduled  float64 `json:"scheduled"`
+	Expected   float64 `json:"expected"`
+	Releases   float64 `json:"releases"`
+}
+
+// CashFlowRelease is a loan booked with a release date still ahead
+type CashFlowRelease struct {
+	LoanID        uint    `json:"loan_id"`
+	ControlNumber string  `json:"control_number"`
+	ClientName    string  `json:"client_name"`
+	BranchName    string  `json:"branch_name"`
+	ReleaseDate   string  `json:"release_date"`
+	AmountRelease float64 `json:"amount_release"`
+}
+
+// CashFlowForecast projects collections against planned releases for the coming weeks
+type CashFlowForecast struct {
+	From           string            `json:"from"`
+	To             string            `json:"to"`
+	Timezone       string            `json:"timezone"`
+	LookbackFrom   string            `json:"lookback_from"` // Weeks the branch efficiencies were measured over
+	LookbackTo     string            `json:"lookback_to"`
+	OpeningBalance float64           `json:"opening_balance"`
+	Total          CashFlowLine      `json:"total"`
+	Daily          []CashFlowLine    `json:"daily"`
+	Weekly         []CashFlowLine    `json:"weekly"`
+	ByBranch       []CashFlowBranch  `json:"by_branch"`
+	Pipeline       []CashFlowRelease `json:"pipeline"`
+	ShortfallDate  string            `json:"shortfall_date,omitempty"` // First day the cash position goes negative
+	GeneratedAt    string            `json:"generated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+    "time"
+)
+
+// PortfolioSnapshot is one loan's position at the end of a day, written by the nightly snapshot job
+// so reports on past periods do not change when loans are paid or updated later
+type PortfolioSnapshot struct {
+    ID            uint       `gorm:"primarykey" json:"id"`
+    SnapshotDate  time.Time  `gorm:"not null;uniqueIndex:idx_portfolio_snapshots_date_loan" json:"snapshot_date"`
+    LoanID        uint       `gorm:"not null;uniqueIndex:idx_portfolio_snapshots_date_loan" json:"loan_id"`
+    ClientID      uint       `gorm:"not null;index" json:"client_id"`
+    BranchID      *uint      `gorm:"index" json:"branch_id"`
+    CollectorID   *uint      `gorm:"index" json:"collector_id"`
+    Status        LoanStatus `gorm:"size:20" json:"status"` // As it stood that day, from the schedule
+    Outstanding   float64    `gorm:"type:decimal(12,2)" json:"outstanding"`
+    AmountPastDue float64    `gorm:"type:decimal(12,2)" json:"amount_past_due"`
+    DaysPastDue   int        `json:"days_past_due"`
+    CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (PortfolioSnapshot) TableName() string {
+    return "portfolio_snapshots"
+}
+
+// SnapshotSummary describes the snapshot taken for one day
+type SnapshotSummary struct {
+    SnapshotDate  string  `json:"snapshot_date"`
+    Loans         int64   `json:"loans"`
+    Outstanding   float64 `json:"outstanding"`
+    AmountPastDue float64 `json:"amount_past_due"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+    "time"
+)
+
+// Statement entry types, in the order they are listed on the same day
+const (
+    StatementEntryRelease     = "Release"
+    StatementEntryInstallment = "Installment Due"
+    StatementEntryCharge      = "Charge"
+    StatementEntryPayment     = "Payment"
+    StatementEntryReversal    = "Reversal"
+)
+
+// StatementEntry is one line of a client's statement of account.
+// Debits increase what the client owes, credits reduce it; installment rows are informational.
+type StatementEntry struct {
+    Date              time.Time `json:"date"`
+    LoanID            uint      `json:"loan_id"`
+    LoanControlNumber string    `json:"loan_control_number"`
+    EntryType         string    `json:"entry_type"`
+    Description       string    `json:"description"`
+    AmountDue         float64   `json:"amount_due"`
+    Debit             float64   `json:"debit"`
+    Credit            float64   `json:"credit"`
+    Balance           float64   `json:"balance"`
+}
+
+// ClientStatement is a chronological ledger across all of a client's loans
+type ClientStatement struct {
+    ClientID       uint             `json:"client_id"`
+    ClientName     string           `json:"client_name"`
+    ControlNumber  string           `json:"control_number"`
+    From           string           `json:"from"`
+    To             string           `json:"to"`
+    OpeningBalance float64          `json:"opening_balance"`
+    TotalDebits    float64          `json:"total_debits"`
+    TotalCredits   float64          `json:"total_credits"`
+    ClosingBalance float64          `json:"closing_balance"`
+    Entries        []StatementEntry `json:"entries"`
+}
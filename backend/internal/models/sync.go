@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Outcomes of one offline payment pushed by a device
+const (
+    SyncStatusApplied   = "applied"
+    SyncStatusDuplicate = "duplicate" // Already applied by an earlier push
+    SyncStatusConflict  = "conflict"
+)
+
+// Reasons an offline payment could not be applied
+const (
+    SyncConflictLoanNotFound = "loan_not_found"
+    SyncConflictLoanChanged  = "loan_changed" // Restructured or re-priced since the device's snapshot
+    SyncConflictLoanClosed   = "loan_closed"
+    SyncConflictNotAssigned  = "not_assigned"
+    SyncConflictOverpayment  = "exceeds_balance"
+    SyncConflictIDReused     = "id_reused" // Same UUID already used for a different payment
+)
+
+// SyncLoan is one assigned loan with what a device needs to collect on it offline
+type SyncLoan struct {
+    Loan               Loan          `json:"loan"`
+    ScheduleVersion    string        `json:"schedule_version"` // Echoed back on push to detect restructured loans
+    Schedule           []Installment `json:"schedule"`
+    AmountPaid         float64       `json:"amount_paid"`
+    CurrentWeekBalance float64       `json:"current_week_balance"`
+    AmountPastDue      float64       `json:"amount_past_due"`
+    DaysPastDue        int           `json:"days_past_due"`
+}
+
+// SyncSnapshot is everything a collector's device downloads before going offline
+type SyncSnapshot struct {
+    CollectorID   uint       `json:"collector_id"`
+    CollectorName string     `json:"collector_name"`
+    GeneratedAt   time.Time  `json:"generated_at"`
+    Clients       []Client   `json:"clients"`
+    Loans         []SyncLoan `json:"loans"`
+}
+
+// SyncPayment is a payment recorded on a device while offline
+type SyncPayment struct {
+    ID              string  `json:"id" binding:"required,max=64"` // Device-generated UUID
+    LoanID          uint    `json:"loan_id" binding:"required"`
+    ScheduleVersion string  `json:"schedule_version"`
+    AmountPaid      float64 `json:"amount_paid" binding:"required,gt=0"`
+    PaymentDate     string  `json:"payment_date"`
+    PaymentMethod   string  `json:"payment_method"`
+}
+
+// SyncPushRequest uploads a device's offline payments
+type SyncPushRequest struct {
+    DeviceID string        `json:"device_id"`
+    Payments []SyncPayment `json:"payments" binding:"required,dive"`
+}
+
+// SyncPaymentResult reports what happened to one pushed payment
+type SyncPaymentResult struct {
+    ID         string `json:"id"`
+    LoanID     uint   `json:"loan_id"`
+    Status     string `json:"status"`
+    PaymentID  uint   `json:"payment_id,omitempty"`
+    WeekNumber int    `json:"week_number,omitempty"`
+    Conflict   string `json:"conflict,omitempty"`
+    Reason     string `json:"reason,omitempty"`
+}
+
+// SyncPushResult lists every pushed payment's outcome, with conflicts repeated for review
+type SyncPushResult struct {
+    SyncedAt   time.Time           `json:"synced_at"`
+    Applied    int                 `json:"applied"`
+    Duplicates int                 `json:"duplicates"`
+    Results    []SyncPaymentResult `json:"results"`
+    Conflicts  []SyncPaymentResult `json:"conflicts"`
+}
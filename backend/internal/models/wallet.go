@@ -0,0 +1,63 @@
+package models
+
+import (
+    "time"
+)
+
+// Wallet notification states. Unmatched, duplicate and failed notifications wait in the review queue.
+const (
+    WalletNotificationReceived  = "received" // Stored, not yet processed
+    WalletNotificationPosted    = "posted"
+    WalletNotificationUnmatched = "unmatched"
+    WalletNotificationDuplicate = "duplicate"
+    WalletNotificationFailed    = "failed"    // Posting hit an error; tried again when the provider redelivers it
+    WalletNotificationResolved  = "resolved"  // Posted by a reviewer to the loan they chose
+    WalletNotificationDismissed = "dismissed" // Reviewed and not posted, e.g. refunded by the provider
+)
+
+// WalletNotification is one payment notification received from an e-wallet provider
+type WalletNotification struct {
+    BaseModel
+    Provider     string     `gorm:"size:30;not null;uniqueIndex:idx_wallet_notifications_event" json:"provider"`
+    EventID      string     `gorm:"size:100;not null;uniqueIndex:idx_wallet_notifications_event" json:"event_id"`
+    Reference    string     `gorm:"size:100;index" json:"reference"` // Loan control number the payer entered
+    Amount       float64    `gorm:"type:decimal(12,2)" json:"amount"`
+    Currency     string     `gorm:"size:3" json:"currency"`
+    PaidAt       time.Time  `json:"paid_at"`
+    PayerName    string     `gorm:"size:150" json:"payer_name"`
+    PayerAccount string     `gorm:"size:100" json:"payer_account"`
+    Status       string     `gorm:"size:20;index" json:"status"`
+    Reason       string     `gorm:"size:255" json:"reason,omitempty"` // Why it is waiting for review
+    LoanID       *uint      `gorm:"index" json:"loan_id"`
+    PaymentID    *uint      `json:"payment_id"`
+    RawPayload   string     `gorm:"type:text" json:"raw_payload"`
+    ReviewedBy   string     `gorm:"size:50" json:"reviewed_by,omitempty"`
+    ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+    ReviewNotes  string     `gorm:"type:text" json:"review_notes,omitempty"`
+}
+
+func (WalletNotification) TableName() string {
+    return "wallet_notifications"
+}
+
+// WalletNotificationPayload is the provider-agnostic body every wallet webhook sends
+type WalletNotificationPayload struct {
+    EventID      string  `json:"event_id" binding:"required"`
+    Reference    string  `json:"reference"`
+    Amount       float64 `json:"amount" binding:"required,gt=0"`
+    Currency     string  `json:"currency"`
+    PaidAt       string  `json:"paid_at"` // RFC 3339
+    PayerName    string  `json:"payer_name"`
+    PayerAccount string  `json:"payer_account"`
+}
+
+// WalletResolveRequest posts a queued notification to the loan a reviewer picked
+type WalletResolveRequest struct {
+    LoanID uint   `json:"loan_id" binding:"required"`
+    Notes  string `json:"notes"`
+}
+
+// WalletDismissRequest closes a queued notification without posting it
+type WalletDismissRequest struct {
+    Notes string `json:"notes" binding:"required"`
+}
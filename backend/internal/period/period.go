@@ -0,0 +1,215 @@
+// Package period cuts reporting periods into whole days of the business timezone, so that a
+// collection taken at 7am Monday in the office lands in Monday's week rather than Sunday's in UTC.
+package period
+
+import (
+    "fmt"
+    "strings"
+    "time"
+    _ "time/tzdata" // Business timezones must resolve on hosts without a zoneinfo database
+)
+
+// DateLayout is how report dates are read from and written to query strings and responses
+const DateLayout = "2006-01-02"
+
+// Options controls where periods are cut
+type Options struct {
+    Location  *time.Location
+    WeekStart time.Weekday
+}
+
+// DefaultOptions cuts periods in UTC with weeks starting on Sunday
+func DefaultOptions() Options {
+    return Options{Location: time.UTC, WeekStart: time.Sunday}
+}
+
+// WithOverrides returns a copy of the options with a timezone name and week-start day applied;
+// blank values keep what the options already had
+func (o Options) WithOverrides(timezone, weekStart string) (Options, error) {
+    if o.Location == nil {
+        o.Location = time.UTC
+    }
+    if timezone = strings.TrimSpace(timezone); timezone != "" {
+        loc, err := time.LoadLocation(timezone)
+        if err != nil {
+            return o, fmt.Errorf("unknown timezone %q", timezone)
+        }
+        o.Location = loc
+    }
+    if weekStart = strings.TrimSpace(weekStart); weekStart != "" {
+        day, err := ParseWeekday(weekStart)
+        if err != nil {
+            return o, err
+        }
+        o.WeekStart = day
+    }
+    return o, nil
+}
+
+// ParseWeekday reads a day name, full ("monday") or abbreviated ("Mon")
+func ParseWeekday(s string) (time.Weekday, error) {
+    name := strings.ToLower(strings.TrimSpace(s))
+    if len(name) >= 3 {
+        for day := time.Sunday; day <= time.Saturday; day++ {
+            full := strings.ToLower(day.String())
+            if name == full || name == full[:3] {
+                return day, nil
+            }
+        }
+    }
+    return time.Sunday, fmt.Errorf("unknown week start day %q", s)
+}
+
+// Range is a run of whole days in one timezone
+type Range struct {
+    first time.Time // Midnight starting the first day, in the range's timezone
+    last  time.Time // Midnight starting the last day
+}
+
+// Start is the first instant of the range
+func (r Range) Start() time.Time { return r.first }
+
+// End is the last instant of the range, for inclusive BETWEEN queries
+func (r Range) End() time.Time { return r.Next().Add(-time.Nanosecond) }
+
+// Next is the first instant after the range
+func (r Range) Next() time.Time { return midnight(r.last.AddDate(0, 0, 1), r.last.Location()) }
+
+// FirstDay is the calendar date of the first day, at UTC midnight like loan schedule dates
+func (r Range) FirstDay() time.Time { return calendarDate(r.first) }
+
+// LastDay is the calendar date of the last day, at UTC midnight like loan schedule dates
+func (r Range) LastDay() time.Time { return calendarDate(r.last) }
+
+// Location is the timezone the range's days are cut in
+func (r Range) Location() *time.Location { return r.first.Location() }
+
+// From formats the first day as YYYY-MM-DD
+func (r Range) From() string { return r.first.Format(DateLayout) }
+
+// To formats the last day as YYYY-MM-DD
+func (r Range) To() string { return r.last.Format(DateLayout) }
+
+// Between makes the range from the first through the last calendar day, in the options' timezone
+func Between(first, last time.Time, opts Options) Range {
+    loc := opts.location()
+    return Range{
+        first: time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc),
+        last:  time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, loc),
+    }
+}
+
+// Day is the single business day containing an instant
+func Day(t time.Time, opts Options) Range {
+    day := midnight(t, opts.location())
+    return Range{first: day, last: day}
+}
+
+// Today is the current business day
+func Today(opts Options) Range {
+    return Day(time.Now(), opts)
+}
+
+// Week is the week containing an instant, starting on the options' week-start day
+func Week(t time.Time, opts Options) Range {
+    day := midnight(t, opts.location())
+    back := (int(day.Weekday()) - int(opts.WeekStart) + 7) % 7
+    first := day.AddDate(0, 0, -back)
+    return Range{first: first, last: first.AddDate(0, 0, 6)}
+}
+
+// Month is the calendar month containing an instant
+func Month(t time.Time, opts Options) Range {
+    local := t.In(opts.location())
+    first := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, local.Location())
+    return Range{first: first, last: first.AddDate(0, 1, -1)}
+}
+
+// Weeks returns n weeks ending with the week containing an instant, newest first
+func Weeks(t time.Time, n int, opts Options) []Range {
+    ranges := make([]Range, 0, n)
+    week := Week(t, opts)
+    for i := 0; i < n; i++ {
+        ranges = append(ranges, week)
+        week = Week(week.first.AddDate(0, 0, -1), opts)
+    }
+    return ranges
+}
+
+// Months returns n calendar months ending with the month containing an instant, newest first
+func Months(t time.Time, n int, opts Options) []Range {
+    ranges := make([]Range, 0, n)
+    month := Month(t, opts)
+    for i := 0; i < n; i++ {
+        ranges = append(ranges, month)
+        month = Month(month.first.AddDate(0, 0, -1), opts)
+    }
+    return ranges
+}
+
+// WeeksCovering returns every week that overlaps a range, newest first
+func WeeksCovering(r Range, opts Options) []Range {
+    var ranges []Range
+    for week := Week(r.last, opts); !week.last.Before(r.first); week = Week(week.first.AddDate(0, 0, -1), opts) {
+        ranges = append(ranges, week)
+    }
+    return ranges
+}
+
+// MonthsCovering returns every calendar month that overlaps a range, newest first
+func MonthsCovering(r Range, opts Options) []Range {
+    var ranges []Range
+    for month := Month(r.last, opts); !month.last.Before(r.first); month = Month(month.first.AddDate(0, 0, -1), opts) {
+        ranges = append(ranges, month)
+    }
+    return ranges
+}
+
+// Parse reads inclusive YYYY-MM-DD from and to dates. A blank date takes its side of the fallback range.
+func Parse(from, to string, fallback Range, opts Options) (Range, error) {
+    first, last := fallback.first, fallback.last
+    var err error
+    if from != "" {
+        if first, err = time.Parse(DateLayout, from); err != nil {
+            return Range{}, fmt.Errorf("invalid from date, use YYYY-MM-DD")
+        }
+    }
+    if to != "" {
+        if last, err = time.Parse(DateLayout, to); err != nil {
+            return Range{}, fmt.Errorf("invalid to date, use YYYY-MM-DD")
+        }
+    }
+    r := Between(first, last, opts)
+    if r.last.Before(r.first) {
+        return Range{}, fmt.Errorf("from must be on or before to")
+    }
+    return r, nil
+}
+
+// LocalDate is the business calendar date of a stored time, at UTC midnight. Dates entered without
+// a time are stored at UTC midnight and are already calendar dates, so they are kept as they are;
+// anything with a time of day is moved into the business timezone first.
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+    utc := t.UTC()
+    if utc.Hour() == 0 && utc.Minute() == 0 && utc.Second() == 0 && utc.Nanosecond() == 0 {
+        return calendarDate(utc)
+    }
+    return calendarDate(t.In(loc))
+}
+
+func (o Options) location() *time.Location {
+    if o.Location == nil {
+        return time.UTC
+    }
+    return o.Location
+}
+
+// midnight is the start of the day containing t, in loc
+func midnight(t time.Time, loc *time.Location) time.Time {
+    local := t.In(loc)
+    return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+func calendarDate(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
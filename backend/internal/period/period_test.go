@@ -0,0 +1,260 @@
+package period
+
+import (
+    "testing"
+    "time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+    t.Helper()
+    loc, err := time.LoadLocation(name)
+    if err != nil {
+        t.Fatalf("load %s: %v", name, err)
+    }
+    return loc
+}
+
+func TestWeek(t *testing.T) {
+    manila := mustLoad(t, "Asia/Manila")
+
+    tests := []struct {
+        name      string
+        at        time.Time
+        opts      Options
+        wantFirst string
+        wantLast  string
+    }{
+        {
+            // 07:00 Monday in Manila is still Sunday in UTC
+            name:      "UTC+8 Monday morning, Monday weeks",
+            at:        time.Date(2026, 10, 11, 23, 0, 0, 0, time.UTC),
+            opts:      Options{Location: manila, WeekStart: time.Monday},
+            wantFirst: "2026-10-12",
+            wantLast:  "2026-10-18",
+        },
+        {
+            name:      "UTC+8 Monday morning, Sunday weeks",
+            at:        time.Date(2026, 10, 11, 23, 0, 0, 0, time.UTC),
+            opts:      Options{Location: manila, WeekStart: time.Sunday},
+            wantFirst: "2026-10-11",
+            wantLast:  "2026-10-17",
+        },
+        {
+            name:      "same instant in UTC falls in the week before",
+            at:        time.Date(2026, 10, 11, 23, 0, 0, 0, time.UTC),
+            opts:      Options{Location: time.UTC, WeekStart: time.Monday},
+            wantFirst: "2026-10-05",
+            wantLast:  "2026-10-11",
+        },
+        {
+            name:      "nil location cuts in UTC",
+            at:        time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC),
+            opts:      Options{WeekStart: time.Sunday},
+            wantFirst: "2026-10-11",
+            wantLast:  "2026-10-17",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            w := Week(tt.at, tt.opts)
+            if w.From() != tt.wantFirst || w.To() != tt.wantLast {
+                t.Errorf("Week = %s..%s, want %s..%s", w.From(), w.To(), tt.wantFirst, tt.wantLast)
+            }
+        })
+    }
+}
+
+func TestWeekEveryWeekStart(t *testing.T) {
+    manila := mustLoad(t, "Asia/Manila")
+    // Wednesday 14 October 2026, 10:00 in Manila
+    at := time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC)
+    want := map[time.Weekday]string{
+        time.Sunday:    "2026-10-11",
+        time.Monday:    "2026-10-12",
+        time.Tuesday:   "2026-10-13",
+        time.Wednesday: "2026-10-14",
+        time.Thursday:  "2026-10-08",
+        time.Friday:    "2026-10-09",
+        time.Saturday:  "2026-10-10",
+    }
+    for day := time.Sunday; day <= time.Saturday; day++ {
+        t.Run(day.String(), func(t *testing.T) {
+            w := Week(at, Options{Location: manila, WeekStart: day})
+            if w.From() != want[day] {
+                t.Errorf("first day = %s, want %s", w.From(), want[day])
+            }
+            if w.Start().Weekday() != day {
+                t.Errorf("week starts on %s, want %s", w.Start().Weekday(), day)
+            }
+            if got := w.LastDay().Sub(w.FirstDay()); got != 6*24*time.Hour {
+                t.Errorf("week spans %v, want six days after the first", got)
+            }
+            if at.Before(w.Start()) || !at.Before(w.Next()) {
+                t.Errorf("week %s..%s does not contain the instant", w.From(), w.To())
+            }
+        })
+    }
+}
+
+func TestBetween(t *testing.T) {
+    manila := mustLoad(t, "Asia/Manila")
+
+    tests := []struct {
+        name      string
+        first     time.Time
+        last      time.Time
+        opts      Options
+        wantStart time.Time
+        wantNext  time.Time
+    }{
+        {
+            name:      "calendar dates are taken as they are, not converted",
+            first:     time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+            last:      time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC),
+            opts:      Options{Location: manila},
+            wantStart: time.Date(2026, 10, 1, 0, 0, 0, 0, manila),
+            wantNext:  time.Date(2026, 11, 1, 0, 0, 0, 0, manila),
+        },
+        {
+            name:      "time of day is dropped",
+            first:     time.Date(2026, 10, 5, 18, 30, 0, 0, time.UTC),
+            last:      time.Date(2026, 10, 5, 23, 59, 0, 0, time.UTC),
+            opts:      DefaultOptions(),
+            wantStart: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+            wantNext:  time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC),
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            r := Between(tt.first, tt.last, tt.opts)
+            if !r.Start().Equal(tt.wantStart) {
+                t.Errorf("Start = %v, want %v", r.Start(), tt.wantStart)
+            }
+            if !r.Next().Equal(tt.wantNext) {
+                t.Errorf("Next = %v, want %v", r.Next(), tt.wantNext)
+            }
+            if !r.End().Equal(tt.wantNext.Add(-time.Nanosecond)) {
+                t.Errorf("End = %v, want a nanosecond before Next", r.End())
+            }
+            first, last := r.FirstDay(), r.LastDay()
+            if first.Location() != time.UTC || last.Location() != time.UTC || first.Hour() != 0 || last.Hour() != 0 {
+                t.Errorf("FirstDay %v and LastDay %v should be UTC midnights", first, last)
+            }
+        })
+    }
+}
+
+func TestRangeAcrossDST(t *testing.T) {
+    newYork := mustLoad(t, "America/New_York")
+    london := mustLoad(t, "Europe/London")
+
+    tests := []struct {
+        name     string
+        r        Range
+        wantNext time.Time
+        wantSpan time.Duration
+    }{
+        {
+            name:     "New York spring forward day is 23 hours",
+            r:        Between(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), Options{Location: newYork}),
+            wantNext: time.Date(2026, 3, 9, 0, 0, 0, 0, newYork),
+            wantSpan: 23 * time.Hour,
+        },
+        {
+            name:     "New York fall back day is 25 hours",
+            r:        Between(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), Options{Location: newYork}),
+            wantNext: time.Date(2026, 11, 2, 0, 0, 0, 0, newYork),
+            wantSpan: 25 * time.Hour,
+        },
+        {
+            name:     "London week containing the clocks going forward",
+            r:        Week(time.Date(2026, 3, 25, 12, 0, 0, 0, time.UTC), Options{Location: london, WeekStart: time.Monday}),
+            wantNext: time.Date(2026, 3, 30, 0, 0, 0, 0, london),
+            wantSpan: 7*24*time.Hour - time.Hour,
+        },
+        {
+            name:     "New York month containing fall back",
+            r:        Month(time.Date(2026, 11, 15, 12, 0, 0, 0, time.UTC), Options{Location: newYork}),
+            wantNext: time.Date(2026, 12, 1, 0, 0, 0, 0, newYork),
+            wantSpan: 30*24*time.Hour + time.Hour,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if !tt.r.Next().Equal(tt.wantNext) {
+                t.Errorf("Next = %v, want %v", tt.r.Next(), tt.wantNext)
+            }
+            if got := tt.r.Next().Sub(tt.r.Start()); got != tt.wantSpan {
+                t.Errorf("range spans %v, want %v", got, tt.wantSpan)
+            }
+            if got := tt.r.Next().Sub(tt.r.End()); got != time.Nanosecond {
+                t.Errorf("End is %v before Next, want 1ns", got)
+            }
+            if h := tt.r.End().In(tt.r.Location()).Hour(); h != 23 {
+                t.Errorf("End falls at %d:00 local, want 23:59:59", h)
+            }
+        })
+    }
+}
+
+func TestParseWeekday(t *testing.T) {
+    tests := []struct {
+        in      string
+        want    time.Weekday
+        wantErr bool
+    }{
+        {in: "monday", want: time.Monday},
+        {in: "Mon", want: time.Monday},
+        {in: " SUNDAY ", want: time.Sunday},
+        {in: "sat", want: time.Saturday},
+        {in: "mo", wantErr: true},
+        {in: "funday", wantErr: true},
+        {in: "", wantErr: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.in, func(t *testing.T) {
+            got, err := ParseWeekday(tt.in)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("ParseWeekday(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+            }
+            if !tt.wantErr && got != tt.want {
+                t.Errorf("ParseWeekday(%q) = %s, want %s", tt.in, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestWithOverrides(t *testing.T) {
+    manila := mustLoad(t, "Asia/Manila")
+    base := Options{Location: manila, WeekStart: time.Monday}
+
+    tests := []struct {
+        name      string
+        base      Options
+        timezone  string
+        weekStart string
+        wantLoc   string
+        wantStart time.Weekday
+        wantErr   bool
+    }{
+        {name: "blank keeps the options", base: base, wantLoc: "Asia/Manila", wantStart: time.Monday},
+        {name: "nil location becomes UTC", base: Options{}, wantLoc: "UTC", wantStart: time.Sunday},
+        {name: "both applied", base: base, timezone: "America/New_York", weekStart: "sun", wantLoc: "America/New_York", wantStart: time.Sunday},
+        {name: "unknown timezone", base: base, timezone: "Mars/Olympus", wantErr: true},
+        {name: "unknown week start", base: base, weekStart: "someday", wantErr: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := tt.base.WithOverrides(tt.timezone, tt.weekStart)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("WithOverrides error = %v, wantErr %v", err, tt.wantErr)
+            }
+            if tt.wantErr {
+                return
+            }
+            if got.Location.String() != tt.wantLoc || got.WeekStart != tt.wantStart {
+                t.Errorf("WithOverrides = %s/%s, want %s/%s", got.Location, got.WeekStart, tt.wantLoc, tt.wantStart)
+            }
+        })
+    }
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+    "database/sql"
+    "micro-lending-platform/backend/internal/models"
+
+    "gorm.io/gorm"
+)
+
+type AccrualRepository struct {
+    db *gorm.DB
+}
+
+func NewAccrualRepository(db *gorm.DB) *AccrualRepository {
+    return &AccrualRepository{db: db}
+}
+
+// FindSettings returns the saved accrual settings, or nil if none were saved
+func (r *AccrualRepository) FindSettings() (*models.InterestAccrualSettings, error) {
+    var settings models.InterestAccrualSettings
+    result := r.db.Order("id DESC").First(&settings)
+    if result.Error != nil {
+        if result.Error == gorm.ErrRecordNotFound {
+            return nil, nil
+        }
+        return nil, result.Error
+    }
+    return &settings, nil
+}
+
+// SaveSettings creates or updates the accrual settings and replaces the product methods, in one transaction
+func (r *AccrualRepository) SaveSettings(settings *models.InterestAccrualSettings, products []models.AccrualProductMethod) (*models.InterestAccrualSettings, error) {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(settings).Error; err != nil {
+            return err
+        }
+        if err := tx.Unscoped().Where("1 = 1").Delete(&models.AccrualProductMethod{}).Error; err != nil {
+            return err
+        }
+        if len(products) > 0 {
+            return tx.Create(&products).Error
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return settings, nil
+}
+
+// FindProductMethods lists the accrual method set for each product
+func (r *AccrualRepository) FindProductMethods() ([]models.AccrualProductMethod, error) {
+    var methods []models.AccrualProductMethod
+    result := r.db.Order("product").Find(&methods)
+    return methods, result.Error
+}
+
+// CreateRun records an accrual run
+func (r *AccrualRepository) CreateRun(run *models.InterestAccrualRun) (*models.InterestAccrualRun, error) {
+    result := r.db.Create(run)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return run, nil
+}
+
+// LatestRunMonth returns the latest month a run was made for, empty when there are none
+func (r *AccrualRepository) LatestRunMonth() (string, error) {
+    var month sql.NullString
+    result := r.db.Model(&models.InterestAccrualRun{}).Select("MAX(month)").Scan(&month)
+    return month.String, result.Error
+}
+
+// FindRuns lists runs with pagination, newest first
+func (r *AccrualRepository) FindRuns(offset, limit int) ([]models.InterestAccrualRun, int64, error) {
+    var runs []models.InterestAccrualRun
+    var total int64
+    if err := r.db.Model(&models.InterestAccrualRun{}).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+    result := r.db.Order("id DESC").Offset(offset).Limit(limit).Find(&runs)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return runs, total, nil
+}
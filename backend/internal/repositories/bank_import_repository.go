@@ -0,0 +1,109 @@
+package repositories
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+type BankImportRepository struct {
+    db *gorm.DB
+}
+
+func NewBankImportRepository(db *gorm.DB) *BankImportRepository {
+    return &BankImportRepository{db: db}
+}
+
+// Create inserts an import together with its transactions
+func (r *BankImportRepository) Create(bankImport *models.BankImport) (*models.BankImport, error) {
+    result := r.db.Create(bankImport)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return bankImport, nil
+}
+
+// Update saves an import's totals
+func (r *BankImportRepository) Update(bankImport *models.BankImport) (*models.BankImport, error) {
+    result := r.db.Omit("Transactions").Save(bankImport)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return bankImport, nil
+}
+
+// FindByID retrieves an import with its transactions
+func (r *BankImportRepository) FindByID(id uint) (*models.BankImport, error) {
+    var bankImport models.BankImport
+    result := r.db.Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+        return db.Order("row_number ASC")
+    }).First(&bankImport, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &bankImport, nil
+}
+
+// FindAll retrieves imports with pagination, newest first
+func (r *BankImportRepository) FindAll(offset, limit int) ([]models.BankImport, int64, error) {
+    var total int64
+    if err := r.db.Model(&models.BankImport{}).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var imports []models.BankImport
+    result := r.db.Order("created_at DESC").Offset(offset).Limit(limit).Find(&imports)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return imports, total, nil
+}
+
+// FindTransactionByID retrieves one statement line
+func (r *BankImportRepository) FindTransactionByID(id uint) (*models.BankTransaction, error) {
+    var txn models.BankTransaction
+    result := r.db.First(&txn, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &txn, nil
+}
+
+// UpdateTransaction saves a statement line
+func (r *BankImportRepository) UpdateTransaction(txn *models.BankTransaction) (*models.BankTransaction, error) {
+    result := r.db.Save(txn)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return txn, nil
+}
+
+// FindTransactionsByStatuses retrieves statement lines in the given states, oldest first
+func (r *BankImportRepository) FindTransactionsByStatuses(statuses []string, offset, limit int) ([]models.BankTransaction, int64, error) {
+    query := r.db.Model(&models.BankTransaction{}).Where("status IN ?", statuses)
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var txns []models.BankTransaction
+    result := query.Order("date ASC, id ASC").Offset(offset).Limit(limit).Find(&txns)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return txns, total, nil
+}
+
+// ExistsTransaction reports whether an identical line was imported before
+func (r *BankImportRepository) ExistsTransaction(date time.Time, amount float64, reference, description string) (bool, error) {
+    var count int64
+    result := r.db.Model(&models.BankTransaction{}).
+        Where("date = ? AND amount = ? AND reference = ? AND description = ?", date, amount, reference, description).
+        Count(&count)
+    if result.Error != nil {
+        return false, result.Error
+    }
+    return count > 0, nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "gorm.io/gorm"
+)
+
+type BranchRepository struct {
+    db *gorm.DB
+}
+
+func NewBranchRepository(db *gorm.DB) *BranchRepository {
+    return &BranchRepository{db: db}
+}
+
+// Create inserts a new branch
+func (r *BranchRepository) Create(branch *models.Branch) (*models.Branch, error) {
+    result := r.db.Create(branch)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return branch, nil
+}
+
+// FindByID retrieves a branch by ID
+func (r *BranchRepository) FindByID(id uint) (*models.Branch, error) {
+    var branch models.Branch
+    result := r.db.First(&branch, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &branch, nil
+}
+
+// FindAll retrieves all branches ordered by code
+func (r *BranchRepository) FindAll() ([]models.Branch, error) {
+    var branches []models.Branch
+    result := r.db.Order("code ASC").Find(&branches)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return branches, nil
+}
+
+// Update saves changes to a branch
+func (r *BranchRepository) Update(branch *models.Branch) (*models.Branch, error) {
+    result := r.db.Save(branch)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return branch, nil
+}
+
+// Delete soft deletes a branch
+func (r *BranchRepository) Delete(id uint) error {
+    result := r.db.Delete(&models.Branch{}, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return fmt.Errorf("branch not found")
+    }
+    return nil
+}
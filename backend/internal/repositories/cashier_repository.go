@@ -0,0 +1,136 @@
+package repositories
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+type CashierRepository struct {
+    db *gorm.DB
+}
+
+func NewCashierRepository(db *gorm.DB) *CashierRepository {
+    return &CashierRepository{db: db}
+}
+
+// CashTotals are the sums that move a cashier's drawer during a session
+type CashTotals struct {
+    CashReceived      float64
+    NonCashReceived   float64
+    PaymentCount      int
+    Disbursed         float64
+    DisbursementCount int
+}
+
+// Create inserts a new session
+func (r *CashierRepository) Create(session *models.CashierSession) (*models.CashierSession, error) {
+    result := r.db.Create(session)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return session, nil
+}
+
+// Update saves a session
+func (r *CashierRepository) Update(session *models.CashierSession) (*models.CashierSession, error) {
+    result := r.db.Save(session)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return session, nil
+}
+
+// FindByID retrieves a session by ID
+func (r *CashierRepository) FindByID(id uint) (*models.CashierSession, error) {
+    var session models.CashierSession
+    result := r.db.First(&session, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &session, nil
+}
+
+// FindOpenByUser returns a cashier's open session, or nil if they have none
+func (r *CashierRepository) FindOpenByUser(userID uint) (*models.CashierSession, error) {
+    var session models.CashierSession
+    result := r.db.Where("user_id = ? AND status = ?", userID, models.CashierSessionOpen).Limit(1).Find(&session)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, nil
+    }
+    return &session, nil
+}
+
+// FindAll retrieves sessions matching a filter, newest first
+func (r *CashierRepository) FindAll(filter models.CashierSessionFilter, offset, limit int) ([]models.CashierSession, int64, error) {
+    query := r.db.Model(&models.CashierSession{})
+    if filter.UserID != nil {
+        query = query.Where("user_id = ?", *filter.UserID)
+    }
+    if filter.BranchID != nil {
+        query = query.Where("branch_id = ?", *filter.BranchID)
+    }
+    if filter.Status != "" {
+        query = query.Where("status = ?", filter.Status)
+    }
+    if filter.Flagged {
+        query = query.Where("flagged = ?", true)
+    }
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var sessions []models.CashierSession
+    result := query.Order("opened_at DESC").Offset(offset).Limit(limit).Find(&sessions)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return sessions, total, nil
+}
+
+// SumActivity totals what a user received and released between two instants
+func (r *CashierRepository) SumActivity(userID uint, from, to time.Time) (*CashTotals, error) {
+    var payments []struct {
+        IsCash bool
+        Total  float64
+        Count  int
+    }
+    err := r.db.Model(&models.Payment{}).
+        Select("LOWER(COALESCE(payment_method, '')) IN ('', 'cash') AS is_cash, COALESCE(SUM(amount_paid), 0) AS total, COUNT(*) AS count").
+        Where("received_by = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+        Group("is_cash").
+        Scan(&payments).Error
+    if err != nil {
+        return nil, fmt.Errorf("failed to sum payments: %w", err)
+    }
+
+    var loans struct {
+        Total float64
+        Count int
+    }
+    err = r.db.Model(&models.Loan{}).
+        Select("COALESCE(SUM(amount_release), 0) AS total, COUNT(*) AS count").
+        Where("released_by_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+        Scan(&loans).Error
+    if err != nil {
+        return nil, fmt.Errorf("failed to sum disbursements: %w", err)
+    }
+
+    totals := &CashTotals{Disbursed: loans.Total, DisbursementCount: loans.Count}
+    for _, p := range payments {
+        if p.IsCash {
+            totals.CashReceived += p.Total
+        } else {
+            totals.NonCashReceived += p.Total
+        }
+        totals.PaymentCount += p.Count
+    }
+    return totals, nil
+}
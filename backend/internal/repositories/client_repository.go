@@ -52,6 +52,15 @@ func (r *ClientRepository) CreateClientWithRelatedData(clientData *models.Client
             return nil, fmt.Errorf("failed to create loan: %w", err)
         }
 
+        // Audit record of an admin accepting above-cap pricing
+        if clientData.ComplianceOverride != nil {
+            clientData.ComplianceOverride.LoanID = clientData.Loan.ID
+            if err := tx.Create(clientData.ComplianceOverride).Error; err != nil {
+                tx.Rollback()
+                return nil, fmt.Errorf("failed to record compliance override: %w", err)
+            }
+        }
+
         // 4. Create Co-makers (if provided and linked to the loan)
         for i := range clientData.CoMakers {
             clientData.CoMakers[i].LoanID = clientData.Loan.ID
@@ -270,11 +279,10 @@ func (r *ClientRepository) Search(query string, offset, limit int) ([]models.Cli
     return clients, nil
 }
 
-// Update updates an existing client
+// Update updates an existing client, failing with ErrVersionConflict if it changed since it was read
 func (r *ClientRepository) Update(client *models.Client) (*models.Client, error) {
-    result := r.db.Save(client)
-    if result.Error != nil {
-        return nil, result.Error
+    if err := saveVersioned(r.db, client, client.ID, &client.Version); err != nil {
+        return nil, err
     }
     return client, nil
 }
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "gorm.io/gorm"
+)
+
+type ComplianceRepository struct {
+    db *gorm.DB
+}
+
+func NewComplianceRepository(db *gorm.DB) *ComplianceRepository {
+    return &ComplianceRepository{db: db}
+}
+
+// FindSettings returns the saved compliance caps, or nil if none were saved
+func (r *ComplianceRepository) FindSettings() (*models.ComplianceSettings, error) {
+    var settings models.ComplianceSettings
+    result := r.db.Order("id DESC").First(&settings)
+    if result.Error != nil {
+        if result.Error == gorm.ErrRecordNotFound {
+            return nil, nil
+        }
+        return nil, result.Error
+    }
+    return &settings, nil
+}
+
+// SaveSettings creates or updates the compliance caps
+func (r *ComplianceRepository) SaveSettings(settings *models.ComplianceSettings) (*models.ComplianceSettings, error) {
+    result := r.db.Save(settings)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return settings, nil
+}
+
+// CreateOverride records an admin override
+func (r *ComplianceRepository) CreateOverride(override *models.ComplianceOverride) (*models.ComplianceOverride, error) {
+    result := r.db.Create(override)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return override, nil
+}
+
+// FindOverrides retrieves overrides newest first with their loans
+func (r *ComplianceRepository) FindOverrides(offset, limit int) ([]models.ComplianceOverride, error) {
+    var overrides []models.ComplianceOverride
+    result := r.db.Preload("Loan").
+        Order("created_at DESC").
+        Offset(offset).
+        Limit(limit).
+        Find(&overrides)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return overrides, nil
+}
+
+// CountOverrides returns the number of recorded overrides
+func (r *ComplianceRepository) CountOverrides() (int64, error) {
+    var count int64
+    result := r.db.Model(&models.ComplianceOverride{}).Count(&count)
+    return count, result.Error
+}
+
+// FindFlaggedLoans retrieves loans booked above the caps that still await review
+func (r *ComplianceRepository) FindFlaggedLoans() ([]models.Loan, error) {
+    var loans []models.Loan
+    result := r.db.Preload("Client").
+        Where("compliance_status = ?", models.ComplianceStatusFlagged).
+        Order("created_at ASC").
+        Find(&loans)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return loans, nil
+}
+
+// UpdateLoanComplianceStatus changes only a loan's compliance status
+func (r *ComplianceRepository) UpdateLoanComplianceStatus(loanID uint, status string) error {
+    return r.db.Model(&models.Loan{}).
+        Where("id = ?", loanID).
+        Updates(map[string]interface{}{
+            "compliance_status": status,
+            "version":           gorm.Expr("version + 1"),
+        }).Error
+}
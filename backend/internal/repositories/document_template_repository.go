@@ -0,0 +1,46 @@
+package repositories
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "gorm.io/gorm"
+)
+
+type DocumentTemplateRepository struct {
+    db *gorm.DB
+}
+
+func NewDocumentTemplateRepository(db *gorm.DB) *DocumentTemplateRepository {
+    return &DocumentTemplateRepository{db: db}
+}
+
+// FindByType finds the stored template for a document type, returning nil if none was saved
+func (r *DocumentTemplateRepository) FindByType(docType string) (*models.DocumentTemplate, error) {
+    var template models.DocumentTemplate
+    result := r.db.Where("type = ?", docType).First(&template)
+    if result.Error != nil {
+        if result.Error == gorm.ErrRecordNotFound {
+            return nil, nil
+        }
+        return nil, result.Error
+    }
+    return &template, nil
+}
+
+// FindAll retrieves all stored templates
+func (r *DocumentTemplateRepository) FindAll() ([]models.DocumentTemplate, error) {
+    var templates []models.DocumentTemplate
+    result := r.db.Order("type ASC").Find(&templates)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return templates, nil
+}
+
+// Save creates or updates a template
+func (r *DocumentTemplateRepository) Save(template *models.DocumentTemplate) (*models.DocumentTemplate, error) {
+    result := r.db.Save(template)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return template, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "gorm.io/gorm"
+)
+
+type HolidayRepository struct {
+    db *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) *HolidayRepository {
+    return &HolidayRepository{db: db}
+}
+
+// Create inserts a new holiday
+func (r *HolidayRepository) Create(holiday *models.Holiday) (*models.Holiday, error) {
+    result := r.db.Create(holiday)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return holiday, nil
+}
+
+// FindByID retrieves a holiday by ID
+func (r *HolidayRepository) FindByID(id uint) (*models.Holiday, error) {
+    var holiday models.Holiday
+    result := r.db.First(&holiday, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &holiday, nil
+}
+
+// FindForBranch retrieves holidays observed by a branch, including company-wide ones.
+// A nil branchID returns only company-wide holidays.
+func (r *HolidayRepository) FindForBranch(branchID *uint) ([]models.Holiday, error) {
+    var holidays []models.Holiday
+    query := r.db.Where("branch_id IS NULL")
+    if branchID != nil {
+        query = r.db.Where("branch_id IS NULL OR branch_id = ?", *branchID)
+    }
+    result := query.Order("date ASC").Find(&holidays)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return holidays, nil
+}
+
+// FindAll retrieves every holiday, optionally limited to one branch's own entries
+func (r *HolidayRepository) FindAll(branchID *uint) ([]models.Holiday, error) {
+    var holidays []models.Holiday
+    query := r.db.Preload("Branch")
+    if branchID != nil {
+        query = query.Where("branch_id = ?", *branchID)
+    }
+    result := query.Order("date ASC").Find(&holidays)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return holidays, nil
+}
+
+// Update saves changes to a holiday
+func (r *HolidayRepository) Update(holiday *models.Holiday) (*models.Holiday, error) {
+    result := r.db.Save(holiday)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return holiday, nil
+}
+
+// Delete soft deletes a holiday
+func (r *HolidayRepository) Delete(id uint) error {
+    result := r.db.Delete(&models.Holiday{}, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return fmt.Errorf("holiday not found")
+    }
+    return nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+    "errors"
+    "micro-lending-platform/backend/internal/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// ErrAlreadyExported means another export took some of the entries while this one was being built
+var ErrAlreadyExported = errors.New("some of the entries were exported by another batch, run the export again")
+
+type JournalExportRepository struct {
+    db *gorm.DB
+}
+
+func NewJournalExportRepository(db *gorm.DB) *JournalExportRepository {
+    return &JournalExportRepository{db: db}
+}
+
+// FindMappings lists the external code and name set for each account
+func (r *JournalExportRepository) FindMappings() ([]models.LedgerAccountMapping, error) {
+    var mappings []models.LedgerAccountMapping
+    result := r.db.Order("account_code").Find(&mappings)
+    return mappings, result.Error
+}
+
+// SaveMappings replaces the account mapping, in one transaction
+func (r *JournalExportRepository) SaveMappings(mappings []models.LedgerAccountMapping) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Unscoped().Where("1 = 1").Delete(&models.LedgerAccountMapping{}).Error; err != nil {
+            return err
+        }
+        if len(mappings) > 0 {
+            return tx.Create(&mappings).Error
+        }
+        return nil
+    })
+}
+
+// FindUnexportedEntries lists the entries with their lines dated from one day up to but not including
+// another that no standing batch has exported, oldest first
+func (r *JournalExportRepository) FindUnexportedEntries(from, before time.Time) ([]models.JournalEntry, error) {
+    var entries []models.JournalEntry
+    result := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+        Where("entry_date >= ? AND entry_date < ? AND export_batch_id IS NULL", from, before).
+        Order("entry_date ASC, id ASC").
+        Find(&entries)
+    return entries, result.Error
+}
+
+// CreateBatch records a batch and marks its entries exported, in one transaction. It fails with
+// ErrAlreadyExported if any of the entries was marked by another batch in the meantime.
+func (r *JournalExportRepository) CreateBatch(batch *models.JournalExportBatch, entryIDs []uint) (*models.JournalExportBatch, error) {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(batch).Error; err != nil {
+            return err
+        }
+        result := tx.Model(&models.JournalEntry{}).
+            Where("id IN ? AND export_batch_id IS NULL", entryIDs).
+            Update("export_batch_id", batch.ID)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected != int64(len(entryIDs)) {
+            return ErrAlreadyExported
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return batch, nil
+}
+
+// FindBatches lists batches with pagination, newest first
+func (r *JournalExportRepository) FindBatches(offset, limit int) ([]models.JournalExportBatch, int64, error) {
+    var batches []models.JournalExportBatch
+    var total int64
+    if err := r.db.Model(&models.JournalExportBatch{}).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+    result := r.db.Omit("content").Order("id DESC").Offset(offset).Limit(limit).Find(&batches)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return batches, total, nil
+}
+
+// FindBatchByID retrieves a batch with its file
+func (r *JournalExportRepository) FindBatchByID(id uint) (*models.JournalExportBatch, error) {
+    var batch models.JournalExportBatch
+    result := r.db.First(&batch, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &batch, nil
+}
+
+// VoidBatch saves a voided batch and frees its entries to be exported again, in one transaction
+func (r *JournalExportRepository) VoidBatch(batch *models.JournalExportBatch) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(batch).Error; err != nil {
+            return err
+        }
+        return tx.Model(&models.JournalEntry{}).
+            Where("export_batch_id = ?", batch.ID).
+            Update("export_batch_id", nil).Error
+    })
+}
@@ -0,0 +1,253 @@
+package repositories
+
+import (
+    "errors"
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// ErrPeriodClosed means an entry is dated in a period whose books are closed
+var ErrPeriodClosed = errors.New("the books are closed for that date")
+
+type LedgerRepository struct {
+    db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+    return &LedgerRepository{db: db}
+}
+
+// Transaction runs fn with a ledger repository whose writes share one database transaction
+func (r *LedgerRepository) Transaction(fn func(ledgerRepo *LedgerRepository) error) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        return fn(&LedgerRepository{db: tx})
+    })
+}
+
+// EnsureAccounts adds any of the given accounts whose code is not in the chart yet
+func (r *LedgerRepository) EnsureAccounts(accounts []models.LedgerAccount) error {
+    for i := range accounts {
+        var count int64
+        if err := r.db.Unscoped().Model(&models.LedgerAccount{}).Where("code = ?", accounts[i].Code).Count(&count).Error; err != nil {
+            return err
+        }
+        if count > 0 {
+            continue
+        }
+        if err := r.db.Create(&accounts[i]).Error; err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// FindAccounts retrieves the chart of accounts in code order
+func (r *LedgerRepository) FindAccounts() ([]models.LedgerAccount, error) {
+    var accounts []models.LedgerAccount
+    result := r.db.Order("code ASC").Find(&accounts)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return accounts, nil
+}
+
+// FindAccountByCode finds an account by its code, or returns nil when there is none
+func (r *LedgerRepository) FindAccountByCode(code string) (*models.LedgerAccount, error) {
+    var account models.LedgerAccount
+    result := r.db.Where("code = ?", code).Limit(1).Find(&account)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, nil
+    }
+    return &account, nil
+}
+
+// CreateAccount inserts a new account
+func (r *LedgerRepository) CreateAccount(account *models.LedgerAccount) (*models.LedgerAccount, error) {
+    result := r.db.Create(account)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return account, nil
+}
+
+// CreateEntry inserts a journal entry together with its lines, refusing entries dated in a closed period
+func (r *LedgerRepository) CreateEntry(entry *models.JournalEntry) (*models.JournalEntry, error) {
+    closed, err := r.FindLatestPeriodClose()
+    if err != nil {
+        return nil, err
+    }
+    if closed != nil && !entry.EntryDate.After(closed.PeriodEnd) {
+        return nil, ErrPeriodClosed
+    }
+
+    result := r.db.Create(entry)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return entry, nil
+}
+
+
+// MarkReversed links an entry to the entry that reverses it, failing if it was already reversed
+func (r *LedgerRepository) MarkReversed(entryID, reversedByID uint) error {
+    result := r.db.Model(&models.JournalEntry{}).
+        Where("id = ? AND reversed_by_id IS NULL", entryID).
+        Update("reversed_by_id", reversedByID)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return fmt.Errorf("journal entry %d is already reversed", entryID)
+    }
+    return nil
+}
+
+// FindEntryByID retrieves an entry with its lines
+func (r *LedgerRepository) FindEntryByID(id uint) (*models.JournalEntry, error) {
+    var entry models.JournalEntry
+    result := r.db.Preload("Lines").First(&entry, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &entry, nil
+}
+
+// FindEntriesByLoanID retrieves every entry posted for a loan with its lines, in posting order
+func (r *LedgerRepository) FindEntriesByLoanID(loanID uint) ([]models.JournalEntry, error) {
+    var entries []models.JournalEntry
+    result := r.db.Preload("Lines").
+        Where("loan_id = ?", loanID).
+        Order("id ASC").
+        Find(&entries)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return entries, nil
+}
+
+// FindEntries retrieves entries matching a filter with their lines, newest first, and the total count
+func (r *LedgerRepository) FindEntries(filter *models.JournalEntryFilter, offset, limit int) ([]models.JournalEntry, int64, error) {
+    query := r.db.Model(&models.JournalEntry{})
+    if filter.From != nil {
+        query = query.Where("entry_date >= ?", *filter.From)
+    }
+    if filter.To != nil {
+        query = query.Where("entry_date < ?", *filter.To)
+    }
+    if filter.Source != "" {
+        query = query.Where("source = ?", filter.Source)
+    }
+    if filter.LoanID != nil {
+        query = query.Where("loan_id = ?", *filter.LoanID)
+    }
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var entries []models.JournalEntry
+    result := query.Preload("Lines").
+        Order("entry_date DESC, id DESC").
+        Offset(offset).
+        Limit(limit).
+        Find(&entries)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return entries, total, nil
+}
+
+// SumByAccount totals the debits and credits posted to each account in entries dated from a time
+// (the beginning when zero) up to but not including another
+func (r *LedgerRepository) SumByAccount(from, before time.Time) ([]models.AccountBalance, error) {
+    var balances []models.AccountBalance
+    query := r.db.Model(&models.JournalLine{}).
+        Select("journal_lines.account_code AS account_code, SUM(journal_lines.debit) AS debit, SUM(journal_lines.credit) AS credit").
+        Joins("JOIN journal_entries ON journal_entries.id = journal_lines.entry_id AND journal_entries.deleted_at IS NULL").
+        Where("journal_entries.entry_date < ?", before)
+    if !from.IsZero() {
+        query = query.Where("journal_entries.entry_date >= ?", from)
+    }
+    result := query.Group("journal_lines.account_code").Scan(&balances)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return balances, nil
+}
+
+// SumByLoan returns each loan's net debit balance across the given accounts, in entries dated
+// before a time (any date when zero)
+func (r *LedgerRepository) SumByLoan(accountCodes []string, before time.Time) (map[uint]float64, error) {
+    var rows []struct {
+        LoanID  uint
+        Balance float64
+    }
+    query := r.db.Model(&models.JournalLine{}).
+        Select("journal_lines.loan_id AS loan_id, SUM(journal_lines.debit - journal_lines.credit) AS balance").
+        Joins("JOIN journal_entries ON journal_entries.id = journal_lines.entry_id AND journal_entries.deleted_at IS NULL").
+        Where("journal_lines.loan_id IS NOT NULL AND journal_lines.account_code IN ?", accountCodes)
+    if !before.IsZero() {
+        query = query.Where("journal_entries.entry_date < ?", before)
+    }
+    result := query.Group("journal_lines.loan_id").Scan(&rows)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    balances := make(map[uint]float64, len(rows))
+    for _, row := range rows {
+        balances[row.LoanID] = row.Balance
+    }
+    return balances, nil
+}
+
+// FindLatestPeriodClose returns the close covering the most recent period that has not been reopened, or nil if none
+func (r *LedgerRepository) FindLatestPeriodClose() (*models.LedgerPeriodClose, error) {
+    var closes []models.LedgerPeriodClose
+    result := r.db.Where("reopened_at IS NULL").Order("period_end DESC").Limit(1).Find(&closes)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if len(closes) == 0 {
+        return nil, nil
+    }
+    return &closes[0], nil
+}
+
+// FindPeriodCloses lists every period close, reopened ones included, newest first
+func (r *LedgerRepository) FindPeriodCloses() ([]models.LedgerPeriodClose, error) {
+    var closes []models.LedgerPeriodClose
+    result := r.db.Order("period_end DESC, id DESC").Find(&closes)
+    return closes, result.Error
+}
+
+// FindPeriodCloseByID retrieves one period close
+func (r *LedgerRepository) FindPeriodCloseByID(id uint) (*models.LedgerPeriodClose, error) {
+    var periodClose models.LedgerPeriodClose
+    result := r.db.First(&periodClose, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &periodClose, nil
+}
+
+// CreatePeriodClose records a period as closed
+func (r *LedgerRepository) CreatePeriodClose(periodClose *models.LedgerPeriodClose) (*models.LedgerPeriodClose, error) {
+    result := r.db.Create(periodClose)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return periodClose, nil
+}
+
+// UpdatePeriodClose saves a reopened period close
+func (r *LedgerRepository) UpdatePeriodClose(periodClose *models.LedgerPeriodClose) error {
+    return r.db.Save(periodClose).Error
+}
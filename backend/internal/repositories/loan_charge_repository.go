@@ -0,0 +1,57 @@
+package repositories
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "gorm.io/gorm"
+)
+
+type LoanChargeRepository struct {
+    db *gorm.DB
+}
+
+func NewLoanChargeRepository(db *gorm.DB) *LoanChargeRepository {
+    return &LoanChargeRepository{db: db}
+}
+
+// Transaction runs fn with charge and loan repositories bound to one database transaction
+func (r *LoanChargeRepository) Transaction(fn func(chargeRepo *LoanChargeRepository, loanRepo *LoanRepository) error) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        return fn(&LoanChargeRepository{db: tx}, &LoanRepository{db: tx})
+    })
+}
+
+// Create inserts a new charge
+func (r *LoanChargeRepository) Create(charge *models.LoanCharge) (*models.LoanCharge, error) {
+    result := r.db.Create(charge)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return charge, nil
+}
+
+// FindByLoanID retrieves all charges for a loan in date order
+func (r *LoanChargeRepository) FindByLoanID(loanID uint) ([]models.LoanCharge, error) {
+    var charges []models.LoanCharge
+    result := r.db.Where("loan_id = ?", loanID).
+        Order("charge_date ASC").
+        Find(&charges)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return charges, nil
+}
+
+// FindByLoanIDs retrieves all charges for a set of loans in date order
+func (r *LoanChargeRepository) FindByLoanIDs(loanIDs []uint) ([]models.LoanCharge, error) {
+    var charges []models.LoanCharge
+    if len(loanIDs) == 0 {
+        return charges, nil
+    }
+    result := r.db.Where("loan_id IN ?", loanIDs).
+        Order("charge_date ASC").
+        Find(&charges)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return charges, nil
+}
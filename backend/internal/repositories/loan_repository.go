@@ -24,6 +24,21 @@ func (r *LoanRepository) Create(loan *models.Loan) (*models.Loan, error) {
     return loan, nil
 }
 
+// CreateWithOverride inserts a loan together with the audit record of its compliance override
+func (r *LoanRepository) CreateWithOverride(loan *models.Loan, override *models.ComplianceOverride) (*models.Loan, error) {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(loan).Error; err != nil {
+            return err
+        }
+        override.LoanID = loan.ID
+        return tx.Create(override).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return loan, nil
+}
+
 // FindByID finds a loan by ID
 func (r *LoanRepository) FindByID(id uint) (*models.Loan, error) {
     var loan models.Loan
@@ -34,6 +49,16 @@ func (r *LoanRepository) FindByID(id uint) (*models.Loan, error) {
     return &loan, nil
 }
 
+// FindWithClient finds a loan by ID together with its client and co-makers
+func (r *LoanRepository) FindWithClient(id uint) (*models.Loan, error) {
+    var loan models.Loan
+    result := r.db.Preload("Client").Preload("CoMakers").Preload("Payments").First(&loan, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &loan, nil
+}
+
 // FindAll retrieves all loans with pagination and status filtering
 func (r *LoanRepository) FindAll(offset, limit int, status string) ([]models.Loan, error) {
     var loans []models.Loan
@@ -72,33 +97,20 @@ func (r *LoanRepository) FindByClientID(clientID uint) ([]models.Loan, error) {
     return loans, nil
 }
 
-// Update updates an existing loan
+// Update updates an existing loan, failing with ErrVersionConflict if it changed since it was read
 func (r *LoanRepository) Update(loan *models.Loan) (*models.Loan, error) {
-    result := r.db.Save(loan)
-    if result.Error != nil {
-        return nil, result.Error
+    if err := saveVersioned(r.db, loan, loan.ID, &loan.Version); err != nil {
+        return nil, err
     }
     return loan, nil
 }
 
-// UpdateBalance updates the outstanding balance and status of a loan
-func (r *LoanRepository) UpdateBalance(loanID uint, newBalance float64, newStatus models.LoanStatus) error {
-    result := r.db.Model(&models.Loan{}).
-        Where("id = ?", loanID).
-        Updates(map[string]interface{}{
-            "outstanding_balance": newBalance,
-            "status":              newStatus,
-        })
-    
-    if result.Error != nil {
-        return result.Error
-    }
-    
-    if result.RowsAffected == 0 {
-        return fmt.Errorf("loan not found")
-    }
-    
-    return nil
+// UpdateBalance updates the outstanding balance and status of a loan still at the given version
+func (r *LoanRepository) UpdateBalance(loanID uint, version int, newBalance float64, newStatus models.LoanStatus) error {
+    return updateVersioned(r.db, &models.Loan{}, loanID, version, map[string]interface{}{
+        "outstanding_balance": newBalance,
+        "status":              newStatus,
+    })
 }
 
 // Delete soft deletes a loan
@@ -168,6 +180,101 @@ func (r *LoanRepository) FindActiveLoans() ([]models.Loan, error) {
     return loans, nil
 }
 
+// FindByStatuses retrieves all loans in any of the given statuses
+func (r *LoanRepository) FindByStatuses(statuses []models.LoanStatus) ([]models.Loan, error) {
+    var loans []models.Loan
+    result := r.db.Where("status IN ?", statuses).
+        Order("id ASC").
+        Find(&loans)
+
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return loans, nil
+}
+
+// FindNotInStatuses retrieves all loans in none of the given statuses
+func (r *LoanRepository) FindNotInStatuses(statuses []models.LoanStatus) ([]models.Loan, error) {
+    var loans []models.Loan
+    result := r.db.Where("status NOT IN ?", statuses).
+        Order("id ASC").
+        Find(&loans)
+
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return loans, nil
+}
+
+// FindByIDWithDeleted finds a loan by ID, including a soft-deleted one
+func (r *LoanRepository) FindByIDWithDeleted(id uint) (*models.Loan, error) {
+    var loan models.Loan
+    result := r.db.Unscoped().First(&loan, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &loan, nil
+}
+
+// FindIDsWithDeleted lists the IDs of every loan ever booked, including soft-deleted ones
+func (r *LoanRepository) FindIDsWithDeleted() ([]uint, error) {
+    var ids []uint
+    result := r.db.Unscoped().Model(&models.Loan{}).Order("id ASC").Pluck("id", &ids)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return ids, nil
+}
+
+// FindByStatusesWithClient retrieves loans in any of the given statuses with their clients
+func (r *LoanRepository) FindByStatusesWithClient(statuses []models.LoanStatus) ([]models.Loan, error) {
+    var loans []models.Loan
+    result := r.db.Preload("Client").
+        Where("status IN ?", statuses).
+        Order("id ASC").
+        Find(&loans)
+
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return loans, nil
+}
+
+// FindReleasedThrough retrieves loans in the given statuses released on or before a time, with their clients
+func (r *LoanRepository) FindReleasedThrough(through time.Time, statuses []models.LoanStatus) ([]models.Loan, error) {
+    var loans []models.Loan
+    result := r.db.Preload("Client").
+        Where("date_of_release <= ? AND status IN ?", through, statuses).
+        Order("id ASC").
+        Find(&loans)
+
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return loans, nil
+}
+
+// FindByCollector retrieves a collector's loans in the given statuses with their clients
+func (r *LoanRepository) FindByCollector(collectorID uint, statuses []models.LoanStatus) ([]models.Loan, error) {
+    var loans []models.Loan
+    result := r.db.Preload("Client").
+        Where("collector_id = ? AND status IN ?", collectorID, statuses).
+        Order("id ASC").
+        Find(&loans)
+
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return loans, nil
+}
+
+// UpdateStatus changes only the status of a loan still at the given version
+func (r *LoanRepository) UpdateStatus(loanID uint, version int, status models.LoanStatus) error {
+    return updateVersioned(r.db, &models.Loan{}, loanID, version, map[string]interface{}{
+        "status": status,
+    })
+}
+
 // FindOverdueLoans retrieves all overdue loans
 func (r *LoanRepository) FindOverdueLoans() ([]models.Loan, error) {
     var loans []models.Loan
@@ -215,18 +322,14 @@ func (r *LoanRepository) FindWithPartialPayments(offset, limit int) ([]models.Lo
     return loans, nil
 }
 
-// UpdateBalanceAndProgress updates loan balance and paid weeks
-func (r *LoanRepository) UpdateBalanceAndProgress(loanID uint, balance float64, paidWeeks int, status models.LoanStatus) error {
-    result := r.db.Model(&models.Loan{}).
-        Where("id = ?", loanID).
-        Updates(map[string]interface{}{
-            "outstanding_balance": balance,
-            "paid_weeks": paidWeeks,
-            "status": status,
-            "updated_at": time.Now(),
-        })
-
-    return result.Error
+// UpdateBalanceAndProgress updates loan balance and paid weeks of a loan still at the given version
+func (r *LoanRepository) UpdateBalanceAndProgress(loanID uint, version int, balance float64, paidWeeks int, status models.LoanStatus) error {
+    return updateVersioned(r.db, &models.Loan{}, loanID, version, map[string]interface{}{
+        "outstanding_balance": balance,
+        "paid_weeks": paidWeeks,
+        "status": status,
+        "updated_at": time.Now(),
+    })
 }
 
 // GetLoansForPayments retrieves loans that need payment attention
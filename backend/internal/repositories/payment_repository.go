@@ -4,6 +4,7 @@ import (
     "micro-lending-platform/backend/internal/models"
     "gorm.io/gorm"
     "fmt"
+    "time"
 )
 
 type PaymentRepository struct {
@@ -14,6 +15,13 @@ func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
     return &PaymentRepository{db: db}
 }
 
+// Transaction runs fn with payment and loan repositories bound to one database transaction
+func (r *PaymentRepository) Transaction(fn func(paymentRepo *PaymentRepository, loanRepo *LoanRepository) error) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        return fn(&PaymentRepository{db: tx}, &LoanRepository{db: tx})
+    })
+}
+
 // Create inserts a new payment into the database
 func (r *PaymentRepository) Create(payment *models.Payment) (*models.Payment, error) {
     result := r.db.Create(payment)
@@ -23,6 +31,19 @@ func (r *PaymentRepository) Create(payment *models.Payment) (*models.Payment, er
     return payment, nil
 }
 
+// FindBySyncID finds a payment by the UUID its offline device gave it, including reversed ones
+func (r *PaymentRepository) FindBySyncID(syncID string) (*models.Payment, error) {
+    var payment models.Payment
+    result := r.db.Unscoped().Where("sync_id = ?", syncID).Limit(1).Find(&payment)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, nil
+    }
+    return &payment, nil
+}
+
 // FindByID finds a payment by ID
 func (r *PaymentRepository) FindByID(id uint) (*models.Payment, error) {
     var payment models.Payment
@@ -47,6 +68,89 @@ func (r *PaymentRepository) FindByLoanID(loanID uint) ([]models.Payment, error)
 }
 
 
+// FindByLoanIDsWithDeleted retrieves payments for a set of loans, including reversed (soft-deleted) ones
+func (r *PaymentRepository) FindByLoanIDsWithDeleted(loanIDs []uint) ([]models.Payment, error) {
+    var payments []models.Payment
+    if len(loanIDs) == 0 {
+        return payments, nil
+    }
+    result := r.db.Unscoped().
+        Where("loan_id IN ?", loanIDs).
+        Order("payment_date ASC, id ASC").
+        Find(&payments)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return payments, nil
+}
+
+// FindByLoanIDsBefore retrieves payments for a set of loans dated before a time, oldest first
+func (r *PaymentRepository) FindByLoanIDsBefore(loanIDs []uint, before time.Time) ([]models.Payment, error) {
+    var payments []models.Payment
+    if len(loanIDs) == 0 {
+        return payments, nil
+    }
+    result := r.db.Where("loan_id IN ? AND payment_date < ?", loanIDs, before).
+        Order("payment_date ASC, id ASC").
+        Find(&payments)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return payments, nil
+}
+
+// SumPaidByLoanIDs returns the total amount paid on each of the given loans
+func (r *PaymentRepository) SumPaidByLoanIDs(loanIDs []uint) (map[uint]float64, error) {
+    sums := make(map[uint]float64, len(loanIDs))
+    if len(loanIDs) == 0 {
+        return sums, nil
+    }
+
+    var rows []struct {
+        LoanID uint
+        Total  float64
+    }
+    result := r.db.Model(&models.Payment{}).
+        Select("loan_id, COALESCE(SUM(amount_paid), 0) AS total").
+        Where("loan_id IN ?", loanIDs).
+        Group("loan_id").
+        Scan(&rows)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    for _, row := range rows {
+        sums[row.LoanID] = row.Total
+    }
+    return sums, nil
+}
+
+// SumPaidByLoanIDsBefore returns the total paid on each of the given loans with a payment date before a time
+func (r *PaymentRepository) SumPaidByLoanIDsBefore(loanIDs []uint, before time.Time) (map[uint]float64, error) {
+    sums := make(map[uint]float64, len(loanIDs))
+    if len(loanIDs) == 0 {
+        return sums, nil
+    }
+
+    var rows []struct {
+        LoanID uint
+        Total  float64
+    }
+    result := r.db.Model(&models.Payment{}).
+        Select("loan_id, COALESCE(SUM(amount_paid), 0) AS total").
+        Where("loan_id IN ? AND payment_date < ?", loanIDs, before).
+        Group("loan_id").
+        Scan(&rows)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    for _, row := range rows {
+        sums[row.LoanID] = row.Total
+    }
+    return sums, nil
+}
+
 // FindAll retrieves all payments with pagination
 func (r *PaymentRepository) FindAll(offset, limit int) ([]models.Payment, error) {
     var payments []models.Payment
@@ -65,11 +169,10 @@ func (r *PaymentRepository) FindAll(offset, limit int) ([]models.Payment, error)
     return payments, nil
 }
 
-// Update updates an existing payment
+// Update updates an existing payment, failing with ErrVersionConflict if it changed since it was read
 func (r *PaymentRepository) Update(payment *models.Payment) (*models.Payment, error) {
-    result := r.db.Save(payment)
-    if result.Error != nil {
-        return nil, result.Error
+    if err := saveVersioned(r.db, payment, payment.ID, &payment.Version); err != nil {
+        return nil, err
     }
     return payment, nil
 }
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+    "database/sql"
+    "micro-lending-platform/backend/internal/models"
+
+    "gorm.io/gorm"
+)
+
+type ProvisioningRepository struct {
+    db *gorm.DB
+}
+
+func NewProvisioningRepository(db *gorm.DB) *ProvisioningRepository {
+    return &ProvisioningRepository{db: db}
+}
+
+// FindSettings returns the saved provisioning rates, or nil if none were saved
+func (r *ProvisioningRepository) FindSettings() (*models.ProvisioningSettings, error) {
+    var settings models.ProvisioningSettings
+    result := r.db.Order("id DESC").First(&settings)
+    if result.Error != nil {
+        if result.Error == gorm.ErrRecordNotFound {
+            return nil, nil
+        }
+        return nil, result.Error
+    }
+    return &settings, nil
+}
+
+// SaveSettings creates or updates the provisioning rates
+func (r *ProvisioningRepository) SaveSettings(settings *models.ProvisioningSettings) (*models.ProvisioningSettings, error) {
+    result := r.db.Save(settings)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return settings, nil
+}
+
+// CreateRun records a provisioning run with its buckets
+func (r *ProvisioningRepository) CreateRun(run *models.ProvisionRun) (*models.ProvisionRun, error) {
+    result := r.db.Create(run)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return run, nil
+}
+
+// CountRunsForMonth counts the runs made for a month
+func (r *ProvisioningRepository) CountRunsForMonth(month string) (int64, error) {
+    var count int64
+    result := r.db.Model(&models.ProvisionRun{}).Where("month = ?", month).Count(&count)
+    return count, result.Error
+}
+
+// LatestRunMonth returns the latest month a run was made for, empty when there are none
+func (r *ProvisioningRepository) LatestRunMonth() (string, error) {
+    var month sql.NullString
+    result := r.db.Model(&models.ProvisionRun{}).Select("MAX(month)").Scan(&month)
+    return month.String, result.Error
+}
+
+// FindRuns lists runs with their buckets, newest first
+func (r *ProvisioningRepository) FindRuns(offset, limit int) ([]models.ProvisionRun, int64, error) {
+    var runs []models.ProvisionRun
+    var total int64
+    if err := r.db.Model(&models.ProvisionRun{}).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+    result := r.db.Preload("Buckets").Order("id DESC").Offset(offset).Limit(limit).Find(&runs)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return runs, total, nil
+}
+
+// FindRunsForMonths lists the runs made for the given months, oldest first
+func (r *ProvisioningRepository) FindRunsForMonths(months []string) ([]models.ProvisionRun, error) {
+    var runs []models.ProvisionRun
+    result := r.db.Where("month IN ?", months).Order("id").Find(&runs)
+    return runs, result.Error
+}
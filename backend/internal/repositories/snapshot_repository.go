@@ -0,0 +1,97 @@
+package repositories
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+type SnapshotRepository struct {
+    db *gorm.DB
+}
+
+func NewSnapshotRepository(db *gorm.DB) *SnapshotRepository {
+    return &SnapshotRepository{db: db}
+}
+
+// ReplaceForDate swaps a day's snapshot rows for new ones, so a job can be re-run safely
+func (r *SnapshotRepository) ReplaceForDate(date time.Time, snapshots []models.PortfolioSnapshot) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Where("snapshot_date = ?", date).Delete(&models.PortfolioSnapshot{}).Error; err != nil {
+            return err
+        }
+        if len(snapshots) == 0 {
+            return nil
+        }
+        return tx.CreateInBatches(snapshots, 200).Error
+    })
+}
+
+// LatestDateBetween returns the most recent snapshot date within a range, or nil when there is none
+func (r *SnapshotRepository) LatestDateBetween(from, to time.Time) (*time.Time, error) {
+    var snapshot models.PortfolioSnapshot
+    result := r.db.Where("snapshot_date BETWEEN ? AND ?", from, to).
+        Order("snapshot_date DESC").
+        Limit(1).
+        Find(&snapshot)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, nil
+    }
+    return &snapshot.SnapshotDate, nil
+}
+
+// CountClientsByStatus counts distinct clients with a loan in any of the given statuses on a snapshot date
+func (r *SnapshotRepository) CountClientsByStatus(date time.Time, statuses []models.LoanStatus) (int64, error) {
+    var count int64
+    err := r.db.Model(&models.PortfolioSnapshot{}).
+        Where("snapshot_date = ? AND status IN ?", date, statuses).
+        Distinct("client_id").
+        Count(&count).Error
+    return count, err
+}
+
+// FindByDate retrieves every loan's snapshot for a day
+func (r *SnapshotRepository) FindByDate(date time.Time) ([]models.PortfolioSnapshot, error) {
+    var snapshots []models.PortfolioSnapshot
+    result := r.db.Where("snapshot_date = ?", date).
+        Order("loan_id ASC").
+        Find(&snapshots)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return snapshots, nil
+}
+
+// Summaries lists the most recent snapshot days with their totals
+func (r *SnapshotRepository) Summaries(limit int) ([]models.SnapshotSummary, error) {
+    var rows []struct {
+        SnapshotDate  time.Time
+        Loans         int64
+        Outstanding   float64
+        AmountPastDue float64
+    }
+    err := r.db.Model(&models.PortfolioSnapshot{}).
+        Select("snapshot_date, COUNT(*) AS loans, COALESCE(SUM(outstanding), 0) AS outstanding, COALESCE(SUM(amount_past_due), 0) AS amount_past_due").
+        Group("snapshot_date").
+        Order("snapshot_date DESC").
+        Limit(limit).
+        Scan(&rows).Error
+    if err != nil {
+        return nil, err
+    }
+
+    summaries := make([]models.SnapshotSummary, len(rows))
+    for i, row := range rows {
+        summaries[i] = models.SnapshotSummary{
+            SnapshotDate:  row.SnapshotDate.Format("2006-01-02"),
+            Loans:         row.Loans,
+            Outstanding:   row.Outstanding,
+            AmountPastDue: row.AmountPastDue,
+        }
+    }
+    return summaries, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+    "errors"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict means the row changed after it was read, so the write was not applied
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// saveVersioned writes every column of a record, but only while the row with its ID still has the
+// version that was read. The record must be a full one, read and then changed: columns left at their
+// zero value are written as such. On success the version is bumped; on a conflict it is left unchanged.
+func saveVersioned(db *gorm.DB, record interface{}, id uint, version *int) error {
+    if id == 0 {
+        return errors.New("cannot update a record without an ID")
+    }
+    expected := *version
+    *version = expected + 1
+
+    result := db.Model(record).
+        Where("id = ? AND version = ?", id, expected).
+        Select("*").
+        Omit(clause.Associations, "created_at").
+        Updates(record)
+    if result.Error != nil {
+        *version = expected
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        *version = expected
+        return ErrVersionConflict
+    }
+    return nil
+}
+
+// updateVersioned applies column updates to one row while it still has the version that was read
+func updateVersioned(db *gorm.DB, model interface{}, id uint, version int, updates map[string]interface{}) error {
+    updates["version"] = gorm.Expr("version + 1")
+
+    result := db.Model(model).
+        Where("id = ? AND version = ?", id, version).
+        Updates(updates)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrVersionConflict
+    }
+    return nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+type WalletRepository struct {
+    db *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) *WalletRepository {
+    return &WalletRepository{db: db}
+}
+
+// Create inserts a notification; the unique provider/event index rejects redeliveries
+func (r *WalletRepository) Create(notification *models.WalletNotification) (*models.WalletNotification, error) {
+    result := r.db.Create(notification)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return notification, nil
+}
+
+// Update saves a notification
+func (r *WalletRepository) Update(notification *models.WalletNotification) (*models.WalletNotification, error) {
+    result := r.db.Save(notification)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return notification, nil
+}
+
+// ClaimForReview saves a reviewer's decision on a notification only while it still has the status it
+// was read with, and reports false when another request changed the status first
+func (r *WalletRepository) ClaimForReview(notification *models.WalletNotification, from string) (bool, error) {
+    result := r.db.Model(&models.WalletNotification{}).
+        Where("id = ? AND status = ?", notification.ID, from).
+        Updates(map[string]interface{}{
+            "status":       notification.Status,
+            "reviewed_by":  notification.ReviewedBy,
+            "reviewed_at":  notification.ReviewedAt,
+            "review_notes": notification.ReviewNotes,
+        })
+    if result.Error != nil {
+        return false, result.Error
+    }
+    return result.RowsAffected == 1, nil
+}
+
+// FindByID retrieves a notification by ID
+func (r *WalletRepository) FindByID(id uint) (*models.WalletNotification, error) {
+    var notification models.WalletNotification
+    result := r.db.First(&notification, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &notification, nil
+}
+
+// FindByEvent returns a provider's notification by event ID, or nil if it has not been received
+func (r *WalletRepository) FindByEvent(provider, eventID string) (*models.WalletNotification, error) {
+    var notification models.WalletNotification
+    result := r.db.Where("provider = ? AND event_id = ?", provider, eventID).Limit(1).Find(&notification)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, nil
+    }
+    return &notification, nil
+}
+
+// FindPostedMatch looks for an already-posted notification of the same payment under another event ID
+func (r *WalletRepository) FindPostedMatch(provider, reference string, amount float64, paidAt time.Time, excludeID uint) (*models.WalletNotification, error) {
+    var notification models.WalletNotification
+    result := r.db.
+        Where("provider = ? AND reference = ? AND amount = ? AND paid_at = ? AND id <> ?", provider, reference, amount, paidAt, excludeID).
+        Where("status IN ?", []string{models.WalletNotificationPosted, models.WalletNotificationResolved}).
+        Limit(1).
+        Find(&notification)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, nil
+    }
+    return &notification, nil
+}
+
+// FindByStatuses retrieves notifications in the given states with pagination, oldest first
+func (r *WalletRepository) FindByStatuses(statuses []string, offset, limit int) ([]models.WalletNotification, int64, error) {
+    query := r.db.Model(&models.WalletNotification{})
+    if len(statuses) > 0 {
+        query = query.Where("status IN ?", statuses)
+    }
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var notifications []models.WalletNotification
+    result := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&notifications)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+    return notifications, total, nil
+}
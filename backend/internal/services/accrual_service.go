@@ -0,0 +1,324 @@
+package services
+
+import (
+    "fmt"
+    "math"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "micro-lending-platform/backend/internal/repositories"
+    "time"
+)
+
+// defaultAccrualSettings are used until settings are saved: straight-line for every product, and a
+// loan stops accruing once it is more than 90 days past due
+var defaultAccrualSettings = models.InterestAccrualSettings{
+    DefaultMethod:     models.AccrualMethodStraightLine,
+    NonPerformingDays: 90,
+}
+
+type AccrualService struct {
+    accrualRepo   *repositories.AccrualRepository
+    ledgerService *LedgerService
+}
+
+func NewAccrualService(accrualRepo *repositories.AccrualRepository, ledgerService *LedgerService) *AccrualService {
+    return &AccrualService{
+        accrualRepo:   accrualRepo,
+        ledgerService: ledgerService,
+    }
+}
+
+// GetSettings returns the accrual settings in force with the method set for each product
+func (s *AccrualService) GetSettings() (*models.InterestAccrualSettings, error) {
+    settings, err := s.accrualRepo.FindSettings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get accrual settings: %w", err)
+    }
+    if settings == nil {
+        defaults := defaultAccrualSettings
+        settings = &defaults
+    }
+    methods, err := s.accrualRepo.FindProductMethods()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get accrual methods: %w", err)
+    }
+    settings.Products = make(map[string]string, len(methods))
+    for _, method := range methods {
+        settings.Products[method.Product] = method.Method
+    }
+    return settings, nil
+}
+
+// UpdateSettings saves the accrual settings; they apply from the next run. Interest already
+// recognized is not restated, the next run moves each loan to what its new method has earned.
+func (s *AccrualService) UpdateSettings(req *models.InterestAccrualSettingsRequest, username string) (*models.InterestAccrualSettings, error) {
+    var products []models.AccrualProductMethod
+    for product, method := range req.Products {
+        if method != models.AccrualMethodStraightLine && method != models.AccrualMethodEffectiveInterest {
+            return nil, fmt.Errorf("invalid accrual method %q for %s", method, product)
+        }
+        products = append(products, models.AccrualProductMethod{Product: product, Method: method})
+    }
+
+    settings, err := s.accrualRepo.FindSettings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get accrual settings: %w", err)
+    }
+    if settings == nil {
+        settings = &models.InterestAccrualSettings{}
+    }
+    settings.DefaultMethod = req.DefaultMethod
+    settings.NonPerformingDays = req.NonPerformingDays
+    settings.UpdatedBy = username
+
+    if _, err := s.accrualRepo.SaveSettings(settings, products); err != nil {
+        return nil, fmt.Errorf("failed to save accrual settings: %w", err)
+    }
+    return s.GetSettings()
+}
+
+// PendingMonths lists the months that have ended since the latest accrual run and can still be
+// posted to, oldest first, so runs missed while the server was down are made up
+func (s *AccrualService) PendingMonths() ([]string, error) {
+    latest, err := s.accrualRepo.LatestRunMonth()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get accrual runs: %w", err)
+    }
+    return s.ledgerService.monthsEndedSince(latest)
+}
+
+// RunAccruals recognizes the interest every loan had earned by the end of a month that has ended,
+// posting for each loan the difference from the interest income already booked, dated that month-end.
+// A performing loan earns its schedule's interest by its product's method; a loan more than the
+// non-performing days past due is put on a cash basis, so interest accrued beyond what was collected
+// is reversed. A loan settled in full has earned all its interest. Running a month again posts only
+// what changed since.
+func (s *AccrualService) RunAccruals(month string, username string) (*models.InterestAccrualRun, error) {
+    start, err := time.Parse("2006-01", month)
+    if err != nil {
+        return nil, fmt.Errorf("invalid month, use YYYY-MM")
+    }
+    ledger := s.ledgerService
+    r := period.Between(start, start.AddDate(0, 1, -1), ledger.periods)
+    monthEnd := r.LastDay()
+    if !monthEnd.Before(period.Today(ledger.periods).FirstDay()) {
+        return nil, fmt.Errorf("%s has not ended yet", start.Format("January 2006"))
+    }
+    if err := ledger.checkOpen(monthEnd); err != nil {
+        return nil, err
+    }
+
+    settings, err := s.GetSettings()
+    if err != nil {
+        return nil, err
+    }
+    if _, err := ledger.SyncAll(); err != nil {
+        return nil, err
+    }
+    closedThrough, err := ledger.closedThrough()
+    if err != nil {
+        return nil, err
+    }
+
+    loans, err := ledger.loanRepo.FindReleasedThrough(monthEnd, []models.LoanStatus{
+        models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusDefault, models.LoanStatusPaid,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+    loanIDs := make([]uint, len(loans))
+    for i, loan := range loans {
+        loanIDs[i] = loan.ID
+    }
+    payments, err := ledger.paymentRepo.FindByLoanIDsBefore(loanIDs, monthEnd.AddDate(0, 0, 1))
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+    paid := make(map[uint]float64, len(loans))
+    for _, payment := range payments {
+        paid[payment.LoanID] += payment.AmountPaid
+    }
+
+    run := &models.InterestAccrualRun{Month: start.Format("2006-01"), PeriodEnd: monthEnd, RunBy: username}
+    calendars := newCalendarCache(ledger.calendarService)
+    for i := range loans {
+        loan := &loans[i]
+        entries, err := ledger.ledgerRepo.FindEntriesByLoanID(loan.ID)
+        if err != nil {
+            return nil, fmt.Errorf("failed to get ledger entries for loan %d: %w", loan.ID, err)
+        }
+        book := newLoanBook(loan, entries)
+        book.closedThrough = closedThrough
+        // A written-off loan's unearned interest was dropped with the write-off
+        if len(book.active(models.JournalSourceWriteOff)) > 0 {
+            continue
+        }
+        schedule, err := calendars.schedule(loan)
+        if err != nil {
+            return nil, err
+        }
+
+        recognized := -book.balanceThrough(models.AccountInterestIncome, monthEnd)
+        unearned := -book.balanceThrough(models.AccountUnearnedInterest, monthEnd)
+        total := recognized + unearned
+        run.Loans++
+
+        var target float64
+        nonPerforming := false
+        receivable := book.balanceThrough(models.AccountLoansReceivable, monthEnd) + book.balanceThrough(models.AccountChargesReceivable, monthEnd)
+        switch {
+        case roundCurrency(receivable) <= 0:
+            target = total
+        case computeArrears(schedule, paid[loan.ID], monthEnd).DaysPastDue > settings.NonPerformingDays:
+            nonPerforming = true
+            run.NonPerforming++
+            _, target, _ = scheduleSplit(schedule, 0, paid[loan.ID])
+        default:
+            method := settings.DefaultMethod
+            if m, ok := settings.Products[loanProduct(loan)]; ok {
+                method = m
+            }
+            target = earnedInterest(schedule, period.LocalDate(loan.DateOfRelease, ledger.periods.Location), monthEnd, method)
+        }
+        target = math.Min(math.Max(target, 0), total)
+
+        delta := roundCurrency(target - recognized)
+        if delta == 0 {
+            continue
+        }
+        entry := accrualEntry(loan, delta, nonPerforming, monthEnd)
+        key := fmt.Sprintf("accrual:%d:%s:%d", loan.ID, run.Month, book.count(models.JournalSourceAccrual, nil)+1)
+        posted := book.posted
+        if err := ledger.postForLoan(book, key, entry); err != nil {
+            return nil, err
+        }
+        if book.posted > posted {
+            run.Entries++
+            if delta > 0 {
+                run.Accrued += delta
+            } else {
+                run.Reversed -= delta
+            }
+        }
+    }
+    run.Accrued = roundCurrency(run.Accrued)
+    run.Reversed = roundCurrency(run.Reversed)
+
+    saved, err := s.accrualRepo.CreateRun(run)
+    if err != nil {
+        return nil, fmt.Errorf("failed to save accrual run: %w", err)
+    }
+    return saved, nil
+}
+
+// GetRuns lists accrual runs with pagination, newest first
+func (s *AccrualService) GetRuns(page, limit int) ([]models.InterestAccrualRun, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+    runs, total, err := s.accrualRepo.FindRuns((page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get accrual runs: %w", err)
+    }
+    return runs, total, nil
+}
+
+// accrualEntry moves interest between unearned and earned: a positive amount recognizes it,
+// a negative one takes back interest recognized earlier
+func accrualEntry(loan *models.Loan, amount float64, nonPerforming bool, entryDate time.Time) *models.JournalEntry {
+    description := fmt.Sprintf("Interest accrued on loan %s", loan.ControlNumber)
+    if amount < 0 {
+        description = fmt.Sprintf("Interest accrual adjusted on loan %s", loan.ControlNumber)
+        if nonPerforming {
+            description = fmt.Sprintf("Accrued interest reversed on non-performing loan %s", loan.ControlNumber)
+        }
+    }
+    var lines entryLines
+    lines.debit(models.AccountUnearnedInterest, amount, "Interest earned")
+    lines.credit(models.AccountInterestIncome, amount, "")
+    return &models.JournalEntry{
+        EntryDate:   entryDate,
+        Source:      models.JournalSourceAccrual,
+        Description: description,
+        Lines:       lines,
+    }
+}
+
+// earnedInterest is the schedule's interest earned by the end of a day. Each installment's share is
+// earned evenly over the days from the previous due date (the release for the first) to its own.
+func earnedInterest(schedule []models.Installment, released, day time.Time, method string) float64 {
+    shares := make([]float64, len(schedule))
+    for i, inst := range schedule {
+        shares[i] = inst.Interest
+    }
+    if method == models.AccrualMethodEffectiveInterest {
+        shares = effectiveInterestShares(schedule)
+    }
+
+    earned := 0.0
+    start := released
+    for i, inst := range schedule {
+        due := dateOnly(inst.DueDate)
+        switch {
+        case !day.Before(due):
+            earned += shares[i]
+        case day.After(start):
+            earned += shares[i] * day.Sub(start).Hours() / due.Sub(start).Hours()
+        }
+        start = due
+    }
+    return roundCurrency(earned)
+}
+
+// effectiveInterestShares spreads the schedule's interest over its installments at the constant rate
+// per installment that discounts the amounts due back to the amount financed (what is due less interest),
+// so each period earns that rate on the balance still outstanding
+func effectiveInterestShares(schedule []models.Installment) []float64 {
+    shares := make([]float64, len(schedule))
+    due, interest := 0.0, 0.0
+    for _, inst := range schedule {
+        due += inst.AmountDue
+        interest += inst.Interest
+    }
+    financed := due - interest
+    if interest <= 0 || financed <= 0 {
+        return shares
+    }
+
+    presentValue := func(rate float64) float64 {
+        pv, factor := 0.0, 1.0
+        for _, inst := range schedule {
+            factor /= 1 + rate
+            pv += inst.AmountDue * factor
+        }
+        return pv
+    }
+    lo, hi := 0.0, 1.0
+    for presentValue(hi) > financed {
+        hi *= 2
+    }
+    for i := 0; i < 100; i++ {
+        mid := (lo + hi) / 2
+        if presentValue(mid) > financed {
+            lo = mid
+        } else {
+            hi = mid
+        }
+    }
+    rate := (lo + hi) / 2
+
+    carrying, spread := financed, 0.0
+    for i, inst := range schedule {
+        shares[i] = carrying * rate
+        spread += shares[i]
+        carrying += shares[i] - inst.AmountDue
+    }
+    // Rounding in the schedule leaves the spread a little off the interest to earn
+    for i := range shares {
+        shares[i] *= interest / spread
+    }
+    return shares
+}
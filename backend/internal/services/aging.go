@@ -0,0 +1,54 @@
+package services
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "time"
+)
+
+// LoanArrears describes how far a loan is behind its schedule on a given day
+type LoanArrears struct {
+    AmountDue           float64    `json:"amount_due"`            // Scheduled through the day before as-of
+    AmountPaid          float64    `json:"amount_paid"`
+    AmountPastDue       float64    `json:"amount_past_due"`
+    InstallmentsPastDue int        `json:"installments_past_due"`
+    DaysPastDue         int        `json:"days_past_due"`
+    OldestDueDate       *time.Time `json:"oldest_due_date,omitempty"`
+}
+
+// computeArrears applies payments to installments oldest first and reports what remains unpaid.
+// An installment only falls past due the day after its due date, which has already been moved
+// off holidays, so a borrower is never late because of a non-working day.
+func computeArrears(schedule []models.Installment, paid float64, asOf time.Time) LoanArrears {
+    arrears := LoanArrears{AmountPaid: roundCurrency(paid)}
+    day := dateOnly(asOf)
+
+    remaining := paid
+    for _, inst := range schedule {
+        if !dateOnly(inst.DueDate).Before(day) {
+            break
+        }
+        arrears.AmountDue += inst.AmountDue
+
+        covered := remaining
+        if covered > inst.AmountDue {
+            covered = inst.AmountDue
+        }
+        remaining -= covered
+
+        // Ignore sub-centavo leftovers from rounding
+        if inst.AmountDue-covered > 0.005 {
+            arrears.InstallmentsPastDue++
+            if arrears.OldestDueDate == nil {
+                dueDate := inst.DueDate
+                arrears.OldestDueDate = &dueDate
+                arrears.DaysPastDue = int(day.Sub(dateOnly(dueDate)).Hours() / 24)
+            }
+        }
+    }
+
+    arrears.AmountDue = roundCurrency(arrears.AmountDue)
+    if pastDue := roundCurrency(arrears.AmountDue - paid); pastDue > 0 {
+        arrears.AmountPastDue = pastDue
+    }
+    return arrears
+}
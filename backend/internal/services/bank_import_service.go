@@ -0,0 +1,605 @@
+package services
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Match scores. A credit posts on its own only when one loan reaches bankAutoPostScore,
+// which takes the loan's control number in the text plus an amount or date that fits.
+const (
+    bankScoreLoanControlNumber   = 60
+    bankScoreClientControlNumber = 40
+    bankScoreClientName          = 20
+    bankScoreAmount              = 20
+    bankScoreDueDate             = 10
+
+    bankCandidateMinScore = 30
+    bankAutoPostScore     = 80
+    bankMaxCandidates     = 5
+
+    // bankDueDateWindow is how close to an installment's due date a deposit counts as paying it
+    bankDueDateWindow = 7 * 24 * time.Hour
+)
+
+// bankDateFormats are tried in order when the mapping does not name a date format
+var bankDateFormats = []string{"2006-01-02", "01/02/2006", "1/2/2006", "01/02/06", "02-Jan-2006", "02 Jan 2006", "Jan 2, 2006", "2006/01/02"}
+
+// BankPaymentMethod is recorded on payments posted from bank statements
+const BankPaymentMethod = "Bank Transfer"
+
+type BankImportService struct {
+    importRepo      *repositories.BankImportRepository
+    loanRepo        *repositories.LoanRepository
+    calendarService *CalendarService
+    paymentService  *PaymentService
+}
+
+func NewBankImportService(importRepo *repositories.BankImportRepository, loanRepo *repositories.LoanRepository, calendarService *CalendarService, paymentService *PaymentService) *BankImportService {
+    return &BankImportService{
+        importRepo:      importRepo,
+        loanRepo:        loanRepo,
+        calendarService: calendarService,
+        paymentService:  paymentService,
+    }
+}
+
+// bankColumns are the resolved column positions of a mapping; -1 means not mapped
+type bankColumns struct {
+    date, description, reference, amount, credit, debit int
+}
+
+// ImportStatement parses a bank statement CSV, stores its credits, and matches each to a loan.
+// Confident matches are posted; the rest wait for review.
+func (s *BankImportService) ImportStatement(fileName string, file io.Reader, mapping *models.BankColumnMapping, username string, userID *uint) (*models.BankImport, error) {
+    txns, rowCount, err := s.parseStatement(file, mapping)
+    if err != nil {
+        return nil, err
+    }
+
+    mappingJSON, _ := json.Marshal(mapping)
+    bankImport := &models.BankImport{
+        FileName:     fileName,
+        ImportedBy:   username,
+        ImportedByID: userID,
+        Mapping:      string(mappingJSON),
+        RowCount:     rowCount,
+        CreditCount:  len(txns),
+    }
+
+    // Lines seen in an earlier statement (overlapping date ranges) are kept but never posted twice
+    for i := range txns {
+        txn := &txns[i]
+        bankImport.TotalCredits += txn.Amount
+        exists, err := s.importRepo.ExistsTransaction(txn.Date, txn.Amount, txn.Reference, txn.Description)
+        if err != nil {
+            return nil, fmt.Errorf("failed to check for duplicates: %w", err)
+        }
+        if exists {
+            txn.Status = models.BankTxnDuplicate
+            txn.Reason = "same line was imported from an earlier statement"
+        }
+    }
+    bankImport.TotalCredits = roundCurrency(bankImport.TotalCredits)
+    bankImport.Transactions = txns
+
+    if _, err := s.importRepo.Create(bankImport); err != nil {
+        return nil, fmt.Errorf("failed to save import: %w", err)
+    }
+
+    loans, err := s.loanRepo.FindByStatusesWithClient([]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue})
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+    schedules := make(map[uint][]models.Installment)
+
+    for i := range bankImport.Transactions {
+        txn := &bankImport.Transactions[i]
+        if txn.Status != models.BankTxnDuplicate {
+            if err := s.matchTransaction(txn, loans, schedules, userID); err != nil {
+                return nil, err
+            }
+            if _, err := s.importRepo.UpdateTransaction(txn); err != nil {
+                return nil, fmt.Errorf("failed to save transaction: %w", err)
+            }
+        }
+
+        switch txn.Status {
+        case models.BankTxnPosted:
+            bankImport.PostedCount++
+        case models.BankTxnReview:
+            bankImport.ReviewCount++
+        case models.BankTxnUnmatched:
+            bankImport.UnmatchedCount++
+        case models.BankTxnDuplicate:
+            bankImport.DuplicateCount++
+        }
+    }
+
+    if _, err := s.importRepo.Update(bankImport); err != nil {
+        return nil, fmt.Errorf("failed to save import: %w", err)
+    }
+    return bankImport, nil
+}
+
+// matchTransaction ranks candidate loans for a credit and posts it when one clearly wins
+func (s *BankImportService) matchTransaction(txn *models.BankTransaction, loans []models.Loan, schedules map[uint][]models.Installment, userID *uint) error {
+    candidates, err := s.rankCandidates(txn, loans, schedules)
+    if err != nil {
+        return err
+    }
+
+    if len(candidates) > 0 {
+        encoded, _ := json.Marshal(candidates)
+        txn.Candidates = string(encoded)
+    }
+
+    switch {
+    case len(candidates) == 0:
+        txn.Status = models.BankTxnUnmatched
+        txn.Reason = "no loan matches the reference, amount or date"
+    case candidates[0].Score >= bankAutoPostScore && (len(candidates) == 1 || candidates[1].Score < bankAutoPostScore):
+        if err := s.postTransaction(txn, candidates[0].LoanID, userID); err != nil {
+            txn.Status = models.BankTxnReview
+            txn.Reason = fmt.Sprintf("matched loan %s but posting failed: %v", candidates[0].LoanControlNumber, err)
+            return nil
+        }
+        txn.Status = models.BankTxnPosted
+        txn.Reason = ""
+    default:
+        txn.Status = models.BankTxnReview
+        txn.Reason = fmt.Sprintf("%d possible loans; best match %s scored %d", len(candidates), candidates[0].LoanControlNumber, candidates[0].Score)
+    }
+    return nil
+}
+
+// rankCandidates scores every open loan against a credit and keeps the plausible ones, best first
+func (s *BankImportService) rankCandidates(txn *models.BankTransaction, loans []models.Loan, schedules map[uint][]models.Installment) ([]models.BankMatchCandidate, error) {
+    text := normalizeBankText(txn.Reference + " " + txn.Description)
+    words := strings.Fields(strings.ToUpper(txn.Reference + " " + txn.Description))
+
+    var candidates []models.BankMatchCandidate
+    for i := range loans {
+        loan := &loans[i]
+        if txn.Amount > roundCurrency(loan.OutstandingBalance) {
+            continue
+        }
+
+        candidate := models.BankMatchCandidate{
+            LoanID:            loan.ID,
+            LoanControlNumber: loan.ControlNumber,
+            ClientName:        strings.Join(strings.Fields(loan.Client.FirstName+" "+loan.Client.LastName), " "),
+        }
+        if loan.ControlNumber != "" && strings.Contains(text, normalizeBankText(loan.ControlNumber)) {
+            candidate.Score += bankScoreLoanControlNumber
+            candidate.Reasons = append(candidate.Reasons, "loan control number in reference")
+        }
+        if loan.Client.ControlNumber != "" && strings.Contains(text, normalizeBankText(loan.Client.ControlNumber)) {
+            candidate.Score += bankScoreClientControlNumber
+            candidate.Reasons = append(candidate.Reasons, "client control number in reference")
+        }
+        if containsWord(words, loan.Client.LastName) && containsWord(words, loan.Client.FirstName) {
+            candidate.Score += bankScoreClientName
+            candidate.Reasons = append(candidate.Reasons, "depositor name matches client")
+        }
+        if loan.Ammortization > 0 && isMultipleOf(txn.Amount, loan.Ammortization) {
+            candidate.Score += bankScoreAmount
+            candidate.Reasons = append(candidate.Reasons, "amount matches amortization")
+        }
+
+        // Only check due dates for loans with other evidence, to avoid building every schedule
+        if candidate.Score > 0 {
+            schedule, ok := schedules[loan.ID]
+            if !ok {
+                var err error
+                if schedule, err = s.calendarService.ScheduleForLoan(loan); err != nil {
+                    return nil, err
+                }
+                schedules[loan.ID] = schedule
+            }
+            if dueNear(schedule, txn.Date) {
+                candidate.Score += bankScoreDueDate
+                candidate.Reasons = append(candidate.Reasons, "installment due near deposit date")
+            }
+        }
+
+        if candidate.Score >= bankCandidateMinScore {
+            candidates = append(candidates, candidate)
+        }
+    }
+
+    sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+    if len(candidates) > bankMaxCandidates {
+        candidates = candidates[:bankMaxCandidates]
+    }
+    return candidates, nil
+}
+
+// postTransaction records a bank credit as a payment on a loan
+func (s *BankImportService) postTransaction(txn *models.BankTransaction, loanID uint, userID *uint) error {
+    return s.paymentService.InTransaction(func(tx *PaymentService) error {
+        loan, err := tx.loanRepo.FindByID(loanID)
+        if err != nil {
+            return fmt.Errorf("loan not found")
+        }
+        if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusOverdue {
+            return fmt.Errorf("loan is %s", loan.Status)
+        }
+
+        payment, err := tx.postCollectedAmount(loan, txn.Amount, txn.Date.Format("2006-01-02"), BankPaymentMethod, nil, userID)
+        if err != nil {
+            return err
+        }
+        txn.LoanID = &loan.ID
+        txn.PaymentID = &payment.ID
+        return nil
+    })
+}
+
+// GetImports lists statement imports with pagination
+func (s *BankImportService) GetImports(page, limit int) ([]models.BankImport, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+
+    imports, total, err := s.importRepo.FindAll((page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get imports: %w", err)
+    }
+    return imports, total, nil
+}
+
+// GetImport returns one import with all its lines
+func (s *BankImportService) GetImport(id uint) (*models.BankImport, error) {
+    bankImport, err := s.importRepo.FindByID(id)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("import not found")
+        }
+        return nil, fmt.Errorf("failed to get import: %w", err)
+    }
+    return bankImport, nil
+}
+
+// GetReviewQueue lists statement lines by status; with no status it returns everything awaiting review
+func (s *BankImportService) GetReviewQueue(status string, page, limit int) ([]models.BankTransaction, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+
+    statuses := []string{models.BankTxnReview, models.BankTxnUnmatched, models.BankTxnDuplicate}
+    if status != "" {
+        statuses = []string{status}
+    }
+
+    txns, total, err := s.importRepo.FindTransactionsByStatuses(statuses, (page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get transactions: %w", err)
+    }
+    return txns, total, nil
+}
+
+// ConfirmTransaction posts a queued line to the loan a reviewer chose, or to its best candidate
+func (s *BankImportService) ConfirmTransaction(id uint, req *models.BankTransactionConfirmRequest, username string, userID *uint) (*models.BankTransaction, error) {
+    txn, err := s.findQueued(id)
+    if err != nil {
+        return nil, err
+    }
+
+    loanID := req.LoanID
+    if loanID == 0 {
+        var candidates []models.BankMatchCandidate
+        if txn.Candidates != "" {
+            json.Unmarshal([]byte(txn.Candidates), &candidates)
+        }
+        if len(candidates) == 0 {
+            return nil, fmt.Errorf("no candidate loan; choose a loan_id")
+        }
+        loanID = candidates[0].LoanID
+    }
+
+    if err := s.postTransaction(txn, loanID, userID); err != nil {
+        return nil, err
+    }
+
+    s.markReviewed(txn, models.BankTxnConfirmed, username, req.Notes)
+    return s.saveReviewed(txn)
+}
+
+// IgnoreTransaction closes a queued line without posting it
+func (s *BankImportService) IgnoreTransaction(id uint, req *models.BankTransactionIgnoreRequest, username string) (*models.BankTransaction, error) {
+    txn, err := s.findQueued(id)
+    if err != nil {
+        return nil, err
+    }
+
+    s.markReviewed(txn, models.BankTxnIgnored, username, req.Notes)
+    return s.saveReviewed(txn)
+}
+
+// findQueued loads a statement line that is waiting for review
+func (s *BankImportService) findQueued(id uint) (*models.BankTransaction, error) {
+    txn, err := s.importRepo.FindTransactionByID(id)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("transaction not found")
+        }
+        return nil, fmt.Errorf("failed to get transaction: %w", err)
+    }
+    switch txn.Status {
+    case models.BankTxnReview, models.BankTxnUnmatched, models.BankTxnDuplicate:
+        return txn, nil
+    default:
+        return nil, fmt.Errorf("transaction is not awaiting review")
+    }
+}
+
+// markReviewed stamps a reviewer's decision on a statement line
+func (s *BankImportService) markReviewed(txn *models.BankTransaction, status, username, notes string) {
+    now := time.Now()
+    txn.Status = status
+    txn.ReviewedBy = username
+    txn.ReviewedAt = &now
+    txn.ReviewNotes = notes
+}
+
+// saveReviewed saves a reviewed statement line
+func (s *BankImportService) saveReviewed(txn *models.BankTransaction) (*models.BankTransaction, error) {
+    saved, err := s.importRepo.UpdateTransaction(txn)
+    if err != nil {
+        return nil, fmt.Errorf("failed to save transaction: %w", err)
+    }
+    return saved, nil
+}
+
+// parseStatement reads the credits out of a statement. Debits and lines without a date
+// (opening balances, totals) are counted as rows but not kept.
+func (s *BankImportService) parseStatement(file io.Reader, mapping *models.BankColumnMapping) ([]models.BankTransaction, int, error) {
+    reader := csv.NewReader(file)
+    reader.FieldsPerRecord = -1
+    reader.LazyQuotes = true
+    reader.TrimLeadingSpace = true
+    if mapping.Delimiter != "" {
+        reader.Comma = []rune(mapping.Delimiter)[0]
+    }
+
+    records, err := reader.ReadAll()
+    if err != nil {
+        return nil, 0, fmt.Errorf("invalid CSV: %w", err)
+    }
+    if mapping.SkipRows > 0 {
+        if mapping.SkipRows >= len(records) {
+            return nil, 0, fmt.Errorf("file has no rows after skipping %d", mapping.SkipRows)
+        }
+        records = records[mapping.SkipRows:]
+    }
+
+    var header []string
+    firstRow := 1 + mapping.SkipRows
+    if !mapping.NoHeader {
+        if len(records) == 0 {
+            return nil, 0, fmt.Errorf("file has no header row")
+        }
+        header = records[0]
+        records = records[1:]
+        firstRow++
+    }
+
+    cols, err := resolveBankColumns(mapping, header)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    var txns []models.BankTransaction
+    for i, record := range records {
+        rowNumber := firstRow + i
+        dateCell := bankCell(record, cols.date)
+        if dateCell == "" {
+            continue
+        }
+        date, err := parseBankDate(dateCell, mapping.DateFormat)
+        if err != nil {
+            return nil, 0, fmt.Errorf("row %d: %w", rowNumber, err)
+        }
+
+        amount, err := bankAmount(record, cols)
+        if err != nil {
+            return nil, 0, fmt.Errorf("row %d: %w", rowNumber, err)
+        }
+        if amount <= 0 {
+            continue
+        }
+
+        txns = append(txns, models.BankTransaction{
+            RowNumber:   rowNumber,
+            Date:        date,
+            Description: truncateBankText(bankCell(record, cols.description), 255),
+            Reference:   truncateBankText(bankCell(record, cols.reference), 100),
+            Amount:      roundCurrency(amount),
+        })
+    }
+    return txns, len(records), nil
+}
+
+// resolveBankColumns turns a mapping's header names or 1-based positions into column indexes
+func resolveBankColumns(mapping *models.BankColumnMapping, header []string) (*bankColumns, error) {
+    resolve := func(field, name string) (int, error) {
+        name = strings.TrimSpace(name)
+        if name == "" {
+            return -1, nil
+        }
+        if pos, err := strconv.Atoi(name); err == nil {
+            if pos < 1 {
+                return -1, fmt.Errorf("%s column position must be 1 or more", field)
+            }
+            return pos - 1, nil
+        }
+        for i, h := range header {
+            if strings.EqualFold(strings.TrimSpace(h), name) {
+                return i, nil
+            }
+        }
+        return -1, fmt.Errorf("%s column %q not found in header", field, name)
+    }
+
+    cols := &bankColumns{}
+    var err error
+    for _, c := range []struct {
+        field, name string
+        index       *int
+    }{
+        {"date", mapping.Date, &cols.date},
+        {"description", mapping.Description, &cols.description},
+        {"reference", mapping.Reference, &cols.reference},
+        {"amount", mapping.Amount, &cols.amount},
+        {"credit", mapping.Credit, &cols.credit},
+        {"debit", mapping.Debit, &cols.debit},
+    } {
+        if *c.index, err = resolve(c.field, c.name); err != nil {
+            return nil, err
+        }
+    }
+
+    if cols.date < 0 {
+        return nil, fmt.Errorf("date column is required")
+    }
+    if cols.amount < 0 && cols.credit < 0 {
+        return nil, fmt.Errorf("an amount or credit column is required")
+    }
+    return cols, nil
+}
+
+// bankAmount reads a row's credit amount; debits come back negative
+func bankAmount(record []string, cols *bankColumns) (float64, error) {
+    if cols.credit >= 0 {
+        credit, err := parseBankAmount(bankCell(record, cols.credit))
+        if err != nil || credit != 0 {
+            return credit, err
+        }
+        debit, err := parseBankAmount(bankCell(record, cols.debit))
+        return -debit, err
+    }
+    return parseBankAmount(bankCell(record, cols.amount))
+}
+
+// parseBankAmount reads amounts like "1,250.00", "PHP 560", "(300.00)" or "300.00 CR"
+func parseBankAmount(cell string) (float64, error) {
+    cell = strings.TrimSpace(cell)
+    if cell == "" {
+        return 0, nil
+    }
+
+    negative := false
+    upper := strings.ToUpper(cell)
+    if strings.HasPrefix(cell, "(") && strings.HasSuffix(cell, ")") || strings.HasSuffix(upper, "DR") || strings.HasPrefix(cell, "-") {
+        negative = true
+    }
+
+    cleaned := strings.Map(func(r rune) rune {
+        if (r >= '0' && r <= '9') || r == '.' {
+            return r
+        }
+        return -1
+    }, cell)
+    amount, err := strconv.ParseFloat(cleaned, 64)
+    if err != nil {
+        return 0, fmt.Errorf("invalid amount %q", cell)
+    }
+    if negative {
+        amount = -amount
+    }
+    return amount, nil
+}
+
+// parseBankDate parses a statement date with the mapping's layout, or common bank layouts
+func parseBankDate(cell, layout string) (time.Time, error) {
+    layouts := bankDateFormats
+    if layout != "" {
+        layouts = []string{layout}
+    }
+    for _, l := range layouts {
+        if date, err := time.Parse(l, cell); err == nil {
+            return date, nil
+        }
+    }
+    return time.Time{}, fmt.Errorf("invalid date %q", cell)
+}
+
+// bankCell returns a trimmed cell, or empty when the column is unmapped or missing
+func bankCell(record []string, index int) string {
+    if index < 0 || index >= len(record) {
+        return ""
+    }
+    return strings.TrimSpace(record[index])
+}
+
+// truncateBankText keeps text within its column size
+func truncateBankText(text string, max int) string {
+    if len(text) > max {
+        return text[:max]
+    }
+    return text
+}
+
+// normalizeBankText upper-cases and drops spaces and dashes, since depositors
+// type control numbers as "LOAN 123", "loan-123" or "LOAN123"
+func normalizeBankText(text string) string {
+    return strings.Map(func(r rune) rune {
+        switch {
+        case r >= 'a' && r <= 'z':
+            return r - 'a' + 'A'
+        case r == ' ' || r == '-' || r == '_':
+            return -1
+        }
+        return r
+    }, text)
+}
+
+// containsWord reports whether an upper-cased word list contains a name
+func containsWord(words []string, name string) bool {
+    name = strings.ToUpper(strings.TrimSpace(name))
+    if name == "" {
+        return false
+    }
+    for _, w := range words {
+        if strings.Trim(w, ".,;:/") == name {
+            return true
+        }
+    }
+    return false
+}
+
+// isMultipleOf reports whether an amount pays a whole number of installments
+func isMultipleOf(amount, installment float64) bool {
+    count := amount / installment
+    whole := float64(int(count + 0.5))
+    return whole >= 1 && roundCurrency(whole*installment) == roundCurrency(amount)
+}
+
+// dueNear reports whether any installment falls due within a window of a date
+func dueNear(schedule []models.Installment, date time.Time) bool {
+    for _, inst := range schedule {
+        diff := inst.DueDate.Sub(date)
+        if diff < 0 {
+            diff = -diff
+        }
+        if diff <= bankDueDateWindow {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "strings"
+    "time"
+)
+
+type CalendarService struct {
+    holidayRepo *repositories.HolidayRepository
+    branchRepo  *repositories.BranchRepository
+}
+
+func NewCalendarService(holidayRepo *repositories.HolidayRepository, branchRepo *repositories.BranchRepository) *CalendarService {
+    return &CalendarService{
+        holidayRepo: holidayRepo,
+        branchRepo:  branchRepo,
+    }
+}
+
+// HolidayCalendar answers whether a date is a non-working day for one branch.
+// A nil calendar has no holidays, so callers without branch context keep plain dates.
+type HolidayCalendar struct {
+    dates     map[string]bool // "2006-01-02" one-off holidays
+    recurring map[string]bool // "01-02" holidays observed every year
+}
+
+// IsHoliday reports whether no collections happen on the given date
+func (c *HolidayCalendar) IsHoliday(date time.Time) bool {
+    if c == nil {
+        return false
+    }
+    return c.dates[date.Format("2006-01-02")] || c.recurring[date.Format("01-02")]
+}
+
+// NextWorkingDay rolls a date forward past any consecutive holidays
+func (c *HolidayCalendar) NextWorkingDay(date time.Time) time.Time {
+    for c.IsHoliday(date) {
+        date = date.AddDate(0, 0, 1)
+    }
+    return date
+}
+
+// CalendarForBranch loads the holidays a branch observes, including company-wide ones
+func (s *CalendarService) CalendarForBranch(branchID *uint) (*HolidayCalendar, error) {
+    holidays, err := s.holidayRepo.FindForBranch(branchID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get holidays: %w", err)
+    }
+
+    calendar := &HolidayCalendar{
+        dates:     make(map[string]bool),
+        recurring: make(map[string]bool),
+    }
+    for _, h := range holidays {
+        if h.Recurring {
+            calendar.recurring[h.Date.Format("01-02")] = true
+        } else {
+            calendar.dates[h.Date.Format("2006-01-02")] = true
+        }
+    }
+    return calendar, nil
+}
+
+// ScheduleForLoan builds a loan's schedule with due dates moved off its branch's holidays
+func (s *CalendarService) ScheduleForLoan(loan *models.Loan) ([]models.Installment, error) {
+    calendar, err := s.CalendarForBranch(loan.BranchID)
+    if err != nil {
+        return nil, err
+    }
+    return ScheduleForLoan(loan, calendar), nil
+}
+
+// GetBranches returns all branches
+func (s *CalendarService) GetBranches() ([]models.Branch, error) {
+    branches, err := s.branchRepo.FindAll()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get branches: %w", err)
+    }
+    return branches, nil
+}
+
+// CreateBranch adds a branch
+func (s *CalendarService) CreateBranch(req *models.BranchRequest) (*models.Branch, error) {
+    branch := &models.Branch{
+        Code:    strings.ToUpper(strings.TrimSpace(req.Code)),
+        Name:    strings.TrimSpace(req.Name),
+        Address: req.Address,
+    }
+    created, err := s.branchRepo.Create(branch)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create branch: %w", err)
+    }
+    return created, nil
+}
+
+// UpdateBranch changes a branch's code, name or address
+func (s *CalendarService) UpdateBranch(id uint, req *models.BranchRequest) (*models.Branch, error) {
+    branch, err := s.branchRepo.FindByID(id)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("branch not found")
+        }
+        return nil, fmt.Errorf("failed to get branch: %w", err)
+    }
+
+    branch.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+    branch.Name = strings.TrimSpace(req.Name)
+    branch.Address = req.Address
+
+    updated, err := s.branchRepo.Update(branch)
+    if err != nil {
+        return nil, fmt.Errorf("failed to update branch: %w", err)
+    }
+    return updated, nil
+}
+
+// DeleteBranch removes a branch
+func (s *CalendarService) DeleteBranch(id uint) error {
+    if err := s.branchRepo.Delete(id); err != nil {
+        if err.Error() == "branch not found" {
+            return err
+        }
+        return fmt.Errorf("failed to delete branch: %w", err)
+    }
+    return nil
+}
+
+// GetHolidays lists holidays, optionally only those defined for one branch
+func (s *CalendarService) GetHolidays(branchID *uint) ([]models.Holiday, error) {
+    holidays, err := s.holidayRepo.FindAll(branchID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get holidays: %w", err)
+    }
+    return holidays, nil
+}
+
+// CreateHoliday adds a company-wide or branch holiday
+func (s *CalendarService) CreateHoliday(req *models.HolidayRequest, username string) (*models.Holiday, error) {
+    holiday := &models.Holiday{CreatedBy: username}
+    if err := s.applyHolidayRequest(holiday, req); err != nil {
+        return nil, err
+    }
+
+    created, err := s.holidayRepo.Create(holiday)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create holiday: %w", err)
+    }
+    return created, nil
+}
+
+// UpdateHoliday changes an existing holiday
+func (s *CalendarService) UpdateHoliday(id uint, req *models.HolidayRequest) (*models.Holiday, error) {
+    holiday, err := s.holidayRepo.FindByID(id)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("holiday not found")
+        }
+        return nil, fmt.Errorf("failed to get holiday: %w", err)
+    }
+    if err := s.applyHolidayRequest(holiday, req); err != nil {
+        return nil, err
+    }
+
+    updated, err := s.holidayRepo.Update(holiday)
+    if err != nil {
+        return nil, fmt.Errorf("failed to update holiday: %w", err)
+    }
+    return updated, nil
+}
+
+// DeleteHoliday removes a holiday
+func (s *CalendarService) DeleteHoliday(id uint) error {
+    if err := s.holidayRepo.Delete(id); err != nil {
+        if err.Error() == "holiday not found" {
+            return err
+        }
+        return fmt.Errorf("failed to delete holiday: %w", err)
+    }
+    return nil
+}
+
+// applyHolidayRequest validates a holiday request and copies it onto the model
+func (s *CalendarService) applyHolidayRequest(holiday *models.Holiday, req *models.HolidayRequest) error {
+    date, err := time.Parse("2006-01-02", req.Date)
+    if err != nil {
+        return fmt.Errorf("invalid date: %w", err)
+    }
+
+    holidayType := req.Type
+    switch strings.ToLower(holidayType) {
+    case "", "regular":
+        holidayType = models.HolidayTypeRegular
+    case "special":
+        holidayType = models.HolidayTypeSpecial
+    case "suspension":
+        holidayType = models.HolidayTypeSuspension
+    default:
+        return fmt.Errorf("holiday type must be Regular, Special or Suspension")
+    }
+
+    if req.BranchID != nil {
+        if _, err := s.branchRepo.FindByID(*req.BranchID); err != nil {
+            return fmt.Errorf("branch not found")
+        }
+    }
+
+    holiday.BranchID = req.BranchID
+    holiday.Name = strings.TrimSpace(req.Name)
+    holiday.Date = date
+    holiday.Recurring = req.Recurring
+    holiday.Type = holidayType
+    return nil
+}
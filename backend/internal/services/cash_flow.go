@@ -0,0 +1,203 @@
+package services
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// cashFlowDay is the raw projection for one day of the forecast
+type cashFlowDay struct {
+    scheduled float64
+    expected  float64
+    releases  float64
+}
+
+// GetCashFlowForecast projects cash in and out for the given number of weeks starting today. Inflows are
+// the unpaid parts of installments falling due in the forecast, scaled by the current collection efficiency
+// each branch achieved over the lookback weeks before today; arrears already past due are left out.
+// Outflows are loans booked ahead of a release date that is still to come.
+func (s *ReportService) GetCashFlowForecast(weeks, lookbackWeeks int, openingBalance float64, opts period.Options) (*models.CashFlowForecast, error) {
+    today := period.Today(opts)
+    first := today.FirstDay()
+    loc := today.Location()
+
+    var weekRanges []period.Range
+    for w := period.Week(time.Now(), opts); len(weekRanges) < weeks; w = period.Week(w.Next(), opts) {
+        weekRanges = append(weekRanges, w)
+    }
+    last := weekRanges[len(weekRanges)-1].LastDay()
+    lookback := period.Between(first.AddDate(0, 0, -7*lookbackWeeks), first.AddDate(0, 0, -1), opts)
+
+    loans, err := s.efficiencyLoans(last)
+    if err != nil {
+        return nil, err
+    }
+    branches, err := s.branchNames()
+    if err != nil {
+        return nil, err
+    }
+
+    // Efficiency each branch achieved on installments due in the lookback weeks
+    portfolioHistory := &models.CollectionEfficiency{}
+    history := make(map[string]*models.CollectionEfficiency)
+    for _, el := range loans {
+        collection, ok := collectionFor(el, lookback.FirstDay(), lookback.LastDay(), loc)
+        if !ok {
+            continue
+        }
+        branchID := loanBranchID(el.loan)
+        addCollection(portfolioHistory, collection)
+        addCollection(efficiencyGroup(history, branchID, groupName(branches, branchID)), collection)
+    }
+    finishEfficiency(portfolioHistory)
+    efficiencyOf := func(branchID uint) float64 {
+        group := history[strconv.FormatUint(uint64(branchID), 10)]
+        if group != nil {
+            finishEfficiency(group)
+            if group.DueInPeriod > 0 {
+                return group.CurrentEfficiency
+            }
+        }
+        if portfolioHistory.DueInPeriod > 0 {
+            return portfolioHistory.CurrentEfficiency
+        }
+        return 100
+    }
+
+    days := make([]cashFlowDay, int(last.Sub(first).Hours()/24)+1)
+    byBranch := make(map[uint]*models.CashFlowBranch)
+    branchLine := func(branchID uint) *models.CashFlowBranch {
+        line, ok := byBranch[branchID]
+        if !ok {
+            line = &models.CashFlowBranch{
+                BranchID:   branchID,
+                BranchName: groupName(branches, branchID),
+                Efficiency: efficiencyOf(branchID),
+            }
+            byBranch[branchID] = line
+        }
+        return line
+    }
+
+    var pipeline []models.CashFlowRelease
+    for _, el := range loans {
+        if el.loan.Status != models.LoanStatusActive && el.loan.Status != models.LoanStatusOverdue {
+            continue
+        }
+        branchID := loanBranchID(el.loan)
+
+        released := dateOnly(el.loan.DateOfRelease)
+        if !released.Before(first) && period.LocalDate(el.loan.CreatedAt, loc).Before(released) {
+            line := branchLine(branchID)
+            line.Releases += el.loan.AmountRelease
+            days[int(released.Sub(first).Hours()/24)].releases += el.loan.AmountRelease
+            pipeline = append(pipeline, models.CashFlowRelease{
+                LoanID:        el.loan.ID,
+                ControlNumber: el.loan.ControlNumber,
+                ClientName:    el.loan.Client.LastName + ", " + el.loan.Client.FirstName,
+                BranchName:    line.BranchName,
+                ReleaseDate:   released.Format(period.DateLayout),
+                AmountRelease: el.loan.AmountRelease,
+            })
+        }
+
+        paid := 0.0
+        for _, payment := range el.payments {
+            paid += payment.AmountPaid
+        }
+        covered := 0.0
+        for _, inst := range el.schedule {
+            lo, hi := covered, covered+inst.AmountDue
+            covered = hi
+            unpaid := hi - max(lo, paid)
+            due := dateOnly(inst.DueDate)
+            if unpaid <= 0 || due.Before(first) || due.After(last) {
+                continue
+            }
+            line := branchLine(branchID)
+            expected := unpaid * line.Efficiency / 100
+            line.Scheduled += unpaid
+            line.Expected += expected
+            day := &days[int(due.Sub(first).Hours()/24)]
+            day.scheduled += unpaid
+            day.expected += expected
+        }
+    }
+
+    report := &models.CashFlowForecast{
+        From:           first.Format(period.DateLayout),
+        To:             last.Format(period.DateLayout),
+        Timezone:       loc.String(),
+        LookbackFrom:   lookback.From(),
+        LookbackTo:     lookback.To(),
+        OpeningBalance: roundCurrency(openingBalance),
+        Daily:          []models.CashFlowLine{},
+        Weekly:         []models.CashFlowLine{},
+        ByBranch:       []models.CashFlowBranch{},
+        Pipeline:       []models.CashFlowRelease{},
+        GeneratedAt:    time.Now().Format("2006-01-02 15:04:05"),
+    }
+
+    total := cashFlowDay{}
+    for i, d := range days {
+        day := first.AddDate(0, 0, i)
+        total.scheduled += d.scheduled
+        total.expected += d.expected
+        total.releases += d.releases
+        line := cashFlowLine(day.Format("Mon Jan 02"), day, day, d, openingBalance, total)
+        if line.Position < 0 && report.ShortfallDate == "" {
+            report.ShortfallDate = line.StartDate
+        }
+        report.Daily = append(report.Daily, line)
+    }
+    report.Total = cashFlowLine("Total", first, last, total, openingBalance, total)
+
+    running := cashFlowDay{}
+    for _, w := range weekRanges {
+        start, end := w.FirstDay(), w.LastDay()
+        if start.Before(first) {
+            start = first
+        }
+        week := cashFlowDay{}
+        for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+            d := days[int(day.Sub(first).Hours()/24)]
+            week.scheduled += d.scheduled
+            week.expected += d.expected
+            week.releases += d.releases
+        }
+        running.expected += week.expected
+        running.releases += week.releases
+        label := start.Format("Jan 02") + " - " + end.Format("Jan 02, 2006")
+        report.Weekly = append(report.Weekly, cashFlowLine(label, start, end, week, openingBalance, running))
+    }
+
+    for _, line := range byBranch {
+        line.Scheduled = roundCurrency(line.Scheduled)
+        line.Expected = roundCurrency(line.Expected)
+        line.Releases = roundCurrency(line.Releases)
+        report.ByBranch = append(report.ByBranch, *line)
+    }
+    sort.Slice(report.ByBranch, func(i, j int) bool { return report.ByBranch[i].BranchName < report.ByBranch[j].BranchName })
+
+    sort.SliceStable(pipeline, func(i, j int) bool { return pipeline[i].ReleaseDate < pipeline[j].ReleaseDate })
+    report.Pipeline = append(report.Pipeline, pipeline...)
+    return report, nil
+}
+
+// cashFlowLine rounds one day's or week's projection; running holds the flows from the start of the
+// forecast through the end of the line, for the cash position
+func cashFlowLine(label string, start, end time.Time, flow cashFlowDay, openingBalance float64, running cashFlowDay) models.CashFlowLine {
+    return models.CashFlowLine{
+        Period:    label,
+        StartDate: start.Format(period.DateLayout),
+        EndDate:   end.Format(period.DateLayout),
+        Scheduled: roundCurrency(flow.scheduled),
+        Expected:  roundCurrency(flow.expected),
+        Releases:  roundCurrency(flow.releases),
+        NetFlow:   roundCurrency(flow.expected - flow.releases),
+        Position:  roundCurrency(openingBalance + running.expected - running.releases),
+    }
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "time"
+)
+
+type CashierService struct {
+    cashierRepo *repositories.CashierRepository
+    branchRepo  *repositories.BranchRepository
+}
+
+func NewCashierService(cashierRepo *repositories.CashierRepository, branchRepo *repositories.BranchRepository) *CashierService {
+    return &CashierService{
+        cashierRepo: cashierRepo,
+        branchRepo:  branchRepo,
+    }
+}
+
+// OpenSession starts a cashier's drawer with its opening count. A cashier has at most one open session.
+func (s *CashierService) OpenSession(userID uint, username string, req *models.CashierSessionOpenRequest) (*models.CashierSession, error) {
+    open, err := s.cashierRepo.FindOpenByUser(userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to check open session: %w", err)
+    }
+    if open != nil {
+        return nil, fmt.Errorf("cashier already has an open session")
+    }
+    if req.BranchID != nil {
+        if _, err := s.branchRepo.FindByID(*req.BranchID); err != nil {
+            return nil, fmt.Errorf("branch not found")
+        }
+    }
+
+    session := &models.CashierSession{
+        UserID:          userID,
+        Username:        username,
+        BranchID:        req.BranchID,
+        Status:          models.CashierSessionOpen,
+        OpenedAt:        time.Now(),
+        OpeningBalance:  roundCurrency(req.OpeningBalance),
+        ExpectedClosing: roundCurrency(req.OpeningBalance),
+        Notes:           req.Notes,
+    }
+    created, err := s.cashierRepo.Create(session)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open session: %w", err)
+    }
+    return created, nil
+}
+
+// GetCurrentSession returns a cashier's open session with running totals
+func (s *CashierService) GetCurrentSession(userID uint) (*models.CashierSession, error) {
+    session, err := s.cashierRepo.FindOpenByUser(userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get session: %w", err)
+    }
+    if session == nil {
+        return nil, fmt.Errorf("no open session")
+    }
+    if err := s.applyTotals(session, time.Now()); err != nil {
+        return nil, err
+    }
+    return session, nil
+}
+
+// CloseSession records the cashier's actual count and flags any difference from the expected closing
+func (s *CashierService) CloseSession(id, userID uint, isAdmin bool, req *models.CashierSessionCloseRequest) (*models.CashierSession, error) {
+    session, err := s.findSession(id)
+    if err != nil {
+        return nil, err
+    }
+    if session.UserID != userID && !isAdmin {
+        return nil, fmt.Errorf("session belongs to another cashier")
+    }
+    if session.Status != models.CashierSessionOpen {
+        return nil, fmt.Errorf("session is already closed")
+    }
+
+    closedAt := time.Now()
+    if err := s.applyTotals(session, closedAt); err != nil {
+        return nil, err
+    }
+
+    actual := roundCurrency(*req.ActualCount)
+    session.ClosedAt = &closedAt
+    session.ActualCount = &actual
+    session.Variance = roundCurrency(actual - session.ExpectedClosing)
+    session.Flagged = session.Variance != 0
+    session.Status = models.CashierSessionClosed
+    if req.Notes != "" {
+        session.Notes = req.Notes
+    }
+
+    updated, err := s.cashierRepo.Update(session)
+    if err != nil {
+        return nil, fmt.Errorf("failed to close session: %w", err)
+    }
+    return updated, nil
+}
+
+// ReviewSession records the branch manager's sign-off on a closed session
+func (s *CashierService) ReviewSession(id uint, username string, req *models.CashierSessionReviewRequest) (*models.CashierSession, error) {
+    session, err := s.findSession(id)
+    if err != nil {
+        return nil, err
+    }
+    if session.Status == models.CashierSessionOpen {
+        return nil, fmt.Errorf("session is still open")
+    }
+
+    now := time.Now()
+    session.Status = models.CashierSessionReviewed
+    session.ReviewedBy = username
+    session.ReviewedAt = &now
+    session.ReviewNotes = req.Notes
+
+    updated, err := s.cashierRepo.Update(session)
+    if err != nil {
+        return nil, fmt.Errorf("failed to review session: %w", err)
+    }
+    return updated, nil
+}
+
+// GetSession returns one session, with running totals while it is still open. A cashier may
+// only read their own; another cashier's session is reported as not found.
+func (s *CashierService) GetSession(id, userID uint, isAdmin bool) (*models.CashierSession, error) {
+    session, err := s.findSession(id)
+    if err != nil {
+        return nil, err
+    }
+    if session.UserID != userID && !isAdmin {
+        return nil, fmt.Errorf("session not found")
+    }
+    if session.Status == models.CashierSessionOpen {
+        if err := s.applyTotals(session, time.Now()); err != nil {
+            return nil, err
+        }
+    }
+    return session, nil
+}
+
+// GetSessions lists sessions with pagination. A cashier only ever sees their own, whatever the filter asks for.
+func (s *CashierService) GetSessions(filter models.CashierSessionFilter, userID uint, isAdmin bool, page, limit int) ([]models.CashierSession, int64, error) {
+    if !isAdmin {
+        filter.UserID = &userID
+    }
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+
+    sessions, total, err := s.cashierRepo.FindAll(filter, (page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get sessions: %w", err)
+    }
+    return sessions, total, nil
+}
+
+// applyTotals recomputes a session's receipts, releases and expected closing up to an instant
+func (s *CashierService) applyTotals(session *models.CashierSession, until time.Time) error {
+    totals, err := s.cashierRepo.SumActivity(session.UserID, session.OpenedAt, until)
+    if err != nil {
+        return err
+    }
+
+    session.PaymentsReceived = roundCurrency(totals.CashReceived)
+    session.NonCashReceived = roundCurrency(totals.NonCashReceived)
+    session.PaymentCount = totals.PaymentCount
+    session.DisbursementsReleased = roundCurrency(totals.Disbursed)
+    session.DisbursementCount = totals.DisbursementCount
+    session.ExpectedClosing = roundCurrency(session.OpeningBalance + totals.CashReceived - totals.Disbursed)
+    return nil
+}
+
+// findSession loads a session, translating a missing record
+func (s *CashierService) findSession(id uint) (*models.CashierSession, error) {
+    session, err := s.cashierRepo.FindByID(id)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("session not found")
+        }
+        return nil, fmt.Errorf("failed to get session: %w", err)
+    }
+    return session, nil
+}
@@ -8,11 +8,14 @@ import (
 )
 
 type ClientService struct {
-    clientRepo *repositories.ClientRepository
+    clientRepo        *repositories.ClientRepository
+    calendarService   *CalendarService
+    complianceService *ComplianceService
+    ledgerService     *LedgerService
 }
 
-func NewClientService(clientRepo *repositories.ClientRepository) *ClientService {
-    return &ClientService{clientRepo: clientRepo}
+func NewClientService(clientRepo *repositories.ClientRepository, calendarService *CalendarService, complianceService *ComplianceService, ledgerService *LedgerService) *ClientService {
+    return &ClientService{clientRepo: clientRepo, calendarService: calendarService, complianceService: complianceService, ledgerService: ledgerService}
 }
 
 type DuplicateCheckResult struct {
@@ -77,6 +80,9 @@ func (s *ClientService) CreateClientWithRelatedData(req *models.ClientCreateRequ
     if err != nil {
         return nil, fmt.Errorf("failed to create client with related data: %w", err)
     }
+    if createdClientData.Loan != nil {
+        s.ledgerService.RecordLoanChange(createdClientData.Loan.ID)
+    }
 
     return createdClientData, nil
 }
@@ -105,6 +111,8 @@ func (s *ClientService) CreateClient(req *models.ClientCreate) (*models.Client,
         FacebookAccount:  req.FacebookAccount,
         Age:              req.Age,
         ContactNumber:    req.ContactNumber,
+        BranchID:         req.BranchID,
+        Area:             req.Area,
     }
 
     // Generate control number if not provided
@@ -192,9 +200,11 @@ func (s *ClientService) GetAllClients(page, limit int, search string) ([]models.
 
 // UpdateClient updates client information
 func (s *ClientService) UpdateClient(client *models.Client) (*models.Client, error) {
+    if client.Version == 0 {
+        return nil, ErrVersionRequired
+    }
     // Check if client exists
-    _, err := s.clientRepo.FindByID(client.ID)
-    if err != nil {
+    if _, err := s.clientRepo.FindByID(client.ID); err != nil {
         return nil, fmt.Errorf("client not found")
     }
 
@@ -439,6 +449,8 @@ func (s *ClientService) convertRequestToClientData(req *models.ClientCreateReque
         FacebookAccount:  req.Client.FacebookAccount,
         Age:              req.Client.Age,
         ContactNumber:    req.Client.ContactNumber,
+        BranchID:         req.Client.BranchID,
+        Area:             req.Client.Area,
     }
 
     // Convert income info
@@ -456,6 +468,7 @@ func (s *ClientService) convertRequestToClientData(req *models.ClientCreateReque
 
     // Parse dates for loan
     var loan *models.Loan
+    var override *models.ComplianceOverride
     if req.Loan != nil {
         dateOfRelease, err := s.parseDate(req.Loan.DateOfRelease)
         if err != nil {
@@ -491,6 +504,30 @@ func (s *ClientService) convertRequestToClientData(req *models.ClientCreateReque
             NameCI:                req.Loan.NameCI,
             NotedBy:               req.Loan.NotedBy,
             ApplicationDate:       applicationDate,
+            BranchID:              req.Loan.BranchID,
+            CollectorID:           req.Loan.CollectorID,
+            OfficerID:             req.Loan.OfficerID,
+            ReleasedByID:          req.Loan.ReleasedByID,
+        }
+        if loan.BranchID == nil {
+            loan.BranchID = client.BranchID
+        }
+
+        calendar, err := s.calendarService.CalendarForBranch(loan.BranchID)
+        if err != nil {
+            return nil, err
+        }
+        if err := applyLoanCalculation(loan, req.Loan, calendar); err != nil {
+            return nil, fmt.Errorf("failed to calculate loan: %w", err)
+        }
+
+        // Pricing must stay within the regulatory caps unless an admin overrides
+        check, err := s.complianceService.EnforceOnNewLoan(loan, ScheduleForLoan(loan, calendar), req.Loan.ComplianceOverride)
+        if err != nil {
+            return nil, err
+        }
+        if loan.ComplianceStatus == models.ComplianceStatusOverridden {
+            override = s.complianceService.NewOverrideRecord(check, req.Loan.ComplianceOverride)
         }
     }
 
@@ -547,14 +584,15 @@ func (s *ClientService) convertRequestToClientData(req *models.ClientCreateReque
     }
 
     return &models.ClientWithRelatedData{
-        Client:     client,
-        Income:     income,
-        Loan:       loan,
-        CoMakers:   coMakers,
-        Family:     req.Family,
-        Siblings:   siblings,
-        Spouse:     spouse,
-        Dependents: dependents,
+        Client:             client,
+        Income:             income,
+        Loan:               loan,
+        CoMakers:           coMakers,
+        Family:             req.Family,
+        Siblings:           siblings,
+        Spouse:             spouse,
+        Dependents:         dependents,
+        ComplianceOverride: override,
     }, nil
 }
 
@@ -0,0 +1,126 @@
+package services
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "time"
+)
+
+// cohortLoans is the loans of one product released in one month
+type cohortLoans struct {
+    month   time.Time // First day of the release month
+    product string
+    loans   []*loanHistory
+}
+
+// GetCohortReport follows the loans released in each month of a range, per product, month by month
+// on book: how much they have repaid, how much is at risk and how much has gone into default.
+// An empty product includes every product.
+func (s *ReportService) GetCohortReport(r period.Range, maxMonths int, product string, opts period.Options) (*models.CohortReport, error) {
+    today := period.Today(opts).LastDay()
+    loc := r.Location()
+    loans, err := s.loanHistories(today, loc)
+    if err != nil {
+        return nil, err
+    }
+
+    first := period.Month(r.Start(), opts).FirstDay()
+    last := period.Month(r.End(), opts).LastDay()
+    groups := make(map[string]*cohortLoans)
+    for _, ol := range loans {
+        if ol.released.Before(first) || ol.released.After(last) {
+            continue
+        }
+        loanProductName := loanProduct(ol.loan)
+        if product != "" && loanProductName != product {
+            continue
+        }
+        month := time.Date(ol.released.Year(), ol.released.Month(), 1, 0, 0, 0, 0, time.UTC)
+        key := month.Format("2006-01") + "|" + loanProductName
+        group, ok := groups[key]
+        if !ok {
+            group = &cohortLoans{month: month, product: loanProductName}
+            groups[key] = group
+        }
+        group.loans = append(group.loans, ol)
+    }
+
+    report := &models.CohortReport{
+        From:        first.Format("2006-01"),
+        To:          last.Format("2006-01"),
+        Timezone:    loc.String(),
+        MaxMonths:   maxMonths,
+        Cohorts:     []models.Cohort{},
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }
+    for _, group := range groups {
+        report.Cohorts = append(report.Cohorts, cohortFor(group, maxMonths, today, loc))
+    }
+    sort.Slice(report.Cohorts, func(i, j int) bool {
+        if report.Cohorts[i].Cohort != report.Cohorts[j].Cohort {
+            return report.Cohorts[i].Cohort < report.Cohorts[j].Cohort
+        }
+        return report.Cohorts[i].Product < report.Cohorts[j].Product
+    })
+    return report, nil
+}
+
+// cohortFor works out a cohort's position at the end of each month on book, up to today
+func cohortFor(group *cohortLoans, maxMonths int, today time.Time, loc *time.Location) models.Cohort {
+    cohort := models.Cohort{
+        Cohort:  group.month.Format("2006-01"),
+        Product: group.product,
+        Loans:   len(group.loans),
+        Months:  []models.CohortMonth{},
+    }
+    writtenOff := 0.0
+    for _, ol := range group.loans {
+        cohort.Disbursed += ol.loan.AmountRelease
+        cohort.TotalDue += ol.loan.TotalAmount
+        if ol.loan.Status == models.LoanStatusDefault {
+            cohort.WrittenOffLoans++
+            writtenOff += ol.loan.TotalAmount
+        }
+    }
+    cohort.Disbursed = roundCurrency(cohort.Disbursed)
+    cohort.TotalDue = roundCurrency(cohort.TotalDue)
+    cohort.WrittenOffRate = percentOf(writtenOff, cohort.TotalDue)
+
+    // The month still running is measured as of today
+    for mob := 0; mob <= maxMonths; mob++ {
+        monthStart := group.month.AddDate(0, mob, 0)
+        if monthStart.After(today) {
+            break
+        }
+        asOf := monthStart.AddDate(0, 1, -1)
+        if asOf.After(today) {
+            asOf = today
+        }
+
+        point := models.CohortMonth{MonthOnBook: mob, AsOf: asOf.Format("2006-01-02")}
+        par30, defaulted := 0.0, 0.0
+        for _, ol := range group.loans {
+            point.Repaid += ol.paidThrough(asOf, loc)
+            outstanding, arrears := ol.positionAt(asOf, loc)
+            if outstanding <= 0 {
+                continue
+            }
+            point.Outstanding += outstanding
+            if arrears.DaysPastDue > 30 {
+                par30 += outstanding
+            }
+            if arrears.DaysPastDue > 90 {
+                point.DefaultLoans++
+                defaulted += outstanding
+            }
+        }
+        point.Repaid = roundCurrency(point.Repaid)
+        point.Outstanding = roundCurrency(point.Outstanding)
+        point.RepaidRate = percentOf(point.Repaid, cohort.TotalDue)
+        point.Par30 = percentOf(par30, cohort.TotalDue)
+        point.DefaultRate = percentOf(defaulted, cohort.TotalDue)
+        cohort.Months = append(cohort.Months, point)
+    }
+    return cohort
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// efficiencyLoan is one loan's schedule and payments, loaded once for every period of a report
+type efficiencyLoan struct {
+    loan     *models.Loan
+    schedule []models.Installment
+    payments []models.Payment
+}
+
+// loanCollection is what one loan owed and paid in a period
+type loanCollection struct {
+    due            float64
+    pastDueAtStart float64
+    current        float64
+    pastDue        float64
+    advance        float64
+}
+
+// GetCollectionEfficiency compares amounts due over a range of business days with what was collected,
+// per collector and branch, with a trend over the given number of weeks ending in the range's last week
+func (s *ReportService) GetCollectionEfficiency(r period.Range, weeks int, opts period.Options) (*models.CollectionEfficiencyReport, error) {
+    from, to := r.FirstDay(), r.LastDay()
+    loc := r.Location()
+
+    loans, err := s.efficiencyLoans(to)
+    if err != nil {
+        return nil, err
+    }
+    branches, err := s.branchNames()
+    if err != nil {
+        return nil, err
+    }
+    users, err := s.userNames()
+    if err != nil {
+        return nil, err
+    }
+
+    portfolio := &models.CollectionEfficiency{Name: "Portfolio"}
+    byCollector := make(map[string]*models.CollectionEfficiency)
+    byBranch := make(map[string]*models.CollectionEfficiency)
+    for _, el := range loans {
+        collection, ok := collectionFor(el, from, to, loc)
+        if !ok {
+            continue
+        }
+        collectorID := loanCollectorID(el.loan)
+        branchID := loanBranchID(el.loan)
+        addCollection(portfolio, collection)
+        addCollection(efficiencyGroup(byCollector, collectorID, groupName(users, collectorID)), collection)
+        addCollection(efficiencyGroup(byBranch, branchID, groupName(branches, branchID)), collection)
+    }
+    finishEfficiency(portfolio)
+
+    // Trend weeks are cut like the historical report's, newest first
+    var trend []models.CollectionEfficiencyPeriod
+    for _, w := range period.Weeks(r.End(), weeks, opts) {
+        start, end := w.FirstDay(), w.LastDay()
+
+        week := &models.CollectionEfficiency{}
+        for _, el := range loans {
+            if collection, ok := collectionFor(el, start, end, loc); ok {
+                addCollection(week, collection)
+            }
+        }
+        finishEfficiency(week)
+
+        trend = append(trend, models.CollectionEfficiencyPeriod{
+            Period:            start.Format("Jan 02") + " - " + end.Format("Jan 02, 2006"),
+            StartDate:         w.From(),
+            EndDate:           w.To(),
+            DueInPeriod:       week.DueInPeriod,
+            PastDueAtStart:    week.PastDueAtStart,
+            CurrentCollected:  week.CurrentCollected,
+            PastDueCollected:  week.PastDueCollected,
+            AdvanceCollected:  week.AdvanceCollected,
+            CurrentEfficiency: week.CurrentEfficiency,
+            OverallEfficiency: week.OverallEfficiency,
+        })
+    }
+
+    return &models.CollectionEfficiencyReport{
+        From:        r.From(),
+        To:          r.To(),
+        Timezone:    loc.String(),
+        Portfolio:   *portfolio,
+        ByCollector: sortedEfficiencyGroups(byCollector),
+        ByBranch:    sortedEfficiencyGroups(byBranch),
+        Trend:       trend,
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }, nil
+}
+
+// efficiencyLoans loads every loan released by a day with its schedule and the payments made by then
+func (s *ReportService) efficiencyLoans(through time.Time) ([]efficiencyLoan, error) {
+    loans, err := s.loanRepo.FindReleasedThrough(through, []models.LoanStatus{
+        models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusDefault, models.LoanStatusPaid,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    loanIDs := make([]uint, len(loans))
+    for i, loan := range loans {
+        loanIDs[i] = loan.ID
+    }
+    payments, err := s.paymentRepo.FindByLoanIDsBefore(loanIDs, through.AddDate(0, 0, 1))
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+    byLoan := make(map[uint][]models.Payment)
+    for _, payment := range payments {
+        byLoan[payment.LoanID] = append(byLoan[payment.LoanID], payment)
+    }
+
+    calendars := newCalendarCache(s.calendarService)
+    result := make([]efficiencyLoan, len(loans))
+    for i := range loans {
+        schedule, err := calendars.schedule(&loans[i])
+        if err != nil {
+            return nil, err
+        }
+        result[i] = efficiencyLoan{loan: &loans[i], schedule: schedule, payments: byLoan[loans[i].ID]}
+    }
+    return result, nil
+}
+
+// collectionFor works out what a loan owed and paid between two business days. Each payment in the period
+// is applied to the schedule where earlier payments left off, and split by when the installments
+// it covers fell due; anything paid beyond the schedule (charges) counts as past-due recovery.
+// It reports false when the loan had nothing due, owing or paid in the period.
+func collectionFor(el efficiencyLoan, from, to time.Time, loc *time.Location) (loanCollection, bool) {
+    var c loanCollection
+    for _, inst := range el.schedule {
+        due := dateOnly(inst.DueDate)
+        if !due.Before(from) && !due.After(to) {
+            c.due += inst.AmountDue
+        }
+    }
+
+    paid := 0.0
+    for _, payment := range el.payments {
+        day := period.LocalDate(payment.PaymentDate, loc)
+        if day.After(to) {
+            break
+        }
+        if day.Before(from) {
+            paid += payment.AmountPaid
+            continue
+        }
+
+        start, end := paid, paid+payment.AmountPaid
+        covered := 0.0
+        for _, inst := range el.schedule {
+            lo, hi := covered, covered+inst.AmountDue
+            covered = hi
+
+            overlap := min(end, hi) - max(start, lo)
+            if overlap <= 0 {
+                continue
+            }
+            due := dateOnly(inst.DueDate)
+            switch {
+            case due.Before(from):
+                c.pastDue += overlap
+            case due.After(to):
+                c.advance += overlap
+            default:
+                c.current += overlap
+            }
+        }
+        if beyond := end - max(start, covered); beyond > 0 {
+            c.pastDue += beyond
+        }
+        paid = end
+    }
+
+    paidBefore := 0.0
+    for _, payment := range el.payments {
+        if !period.LocalDate(payment.PaymentDate, loc).Before(from) {
+            break
+        }
+        paidBefore += payment.AmountPaid
+    }
+    c.pastDueAtStart = computeArrears(el.schedule, paidBefore, from).AmountPastDue
+
+    active := c.due > 0 || c.pastDueAtStart > 0 || c.current+c.pastDue+c.advance > 0
+    return c, active
+}
+
+// efficiencyGroup returns the grouping for an ID, starting it if needed
+func efficiencyGroup(groups map[string]*models.CollectionEfficiency, id uint, name string) *models.CollectionEfficiency {
+    key := strconv.FormatUint(uint64(id), 10)
+    group, ok := groups[key]
+    if !ok {
+        group = &models.CollectionEfficiency{Key: key, Name: name}
+        groups[key] = group
+    }
+    return group
+}
+
+// addCollection adds one loan's period to a grouping
+func addCollection(group *models.CollectionEfficiency, c loanCollection) {
+    group.Loans++
+    group.DueInPeriod += c.due
+    group.PastDueAtStart += c.pastDueAtStart
+    group.CurrentCollected += c.current
+    group.PastDueCollected += c.pastDue
+    group.AdvanceCollected += c.advance
+}
+
+// finishEfficiency rounds a grouping's totals and works out its efficiency ratios
+func finishEfficiency(group *models.CollectionEfficiency) {
+    group.DueInPeriod = roundCurrency(group.DueInPeriod)
+    group.PastDueAtStart = roundCurrency(group.PastDueAtStart)
+    group.CurrentCollected = roundCurrency(group.CurrentCollected)
+    group.PastDueCollected = roundCurrency(group.PastDueCollected)
+    group.AdvanceCollected = roundCurrency(group.AdvanceCollected)
+    group.TotalCollected = roundCurrency(group.CurrentCollected + group.PastDueCollected + group.AdvanceCollected)
+    group.CurrentEfficiency = percentOf(group.CurrentCollected, group.DueInPeriod)
+    group.OverallEfficiency = percentOf(group.CurrentCollected+group.PastDueCollected, group.DueInPeriod+group.PastDueAtStart)
+}
+
+// sortedEfficiencyGroups finishes each grouping and orders them by name
+func sortedEfficiencyGroups(groups map[string]*models.CollectionEfficiency) []models.CollectionEfficiency {
+    result := make([]models.CollectionEfficiency, 0, len(groups))
+    for _, group := range groups {
+        finishEfficiency(group)
+        result = append(result, *group)
+    }
+    sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+    return result
+}
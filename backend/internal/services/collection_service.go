@@ -0,0 +1,364 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "micro-lending-platform/backend/internal/export"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "micro-lending-platform/backend/internal/repositories"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+type CollectionService struct {
+    loanRepo        *repositories.LoanRepository
+    paymentRepo     *repositories.PaymentRepository
+    userRepo        *repositories.UserRepository
+    calendarService *CalendarService
+    paymentService  *PaymentService
+    periods         period.Options
+}
+
+func NewCollectionService(loanRepo *repositories.LoanRepository, paymentRepo *repositories.PaymentRepository, userRepo *repositories.UserRepository, calendarService *CalendarService, paymentService *PaymentService, periods period.Options) *CollectionService {
+    return &CollectionService{
+        loanRepo:        loanRepo,
+        paymentRepo:     paymentRepo,
+        userRepo:        userRepo,
+        calendarService: calendarService,
+        paymentService:  paymentService,
+        periods:         periods,
+    }
+}
+
+var (
+    // ErrRemittanceMismatch is returned when the cash turned over does not equal the amounts collected
+    ErrRemittanceMismatch = errors.New("cash remitted does not match total collected")
+    // ErrCollectionBatchFailed is returned when any line fails and the whole batch is rolled back
+    ErrCollectionBatchFailed = errors.New("collection batch not posted")
+)
+
+// noAreaLabel groups borrowers whose client record has no area yet
+const noAreaLabel = "Unassigned"
+
+// GetCollectionSheet lists what a collector should collect on a date, grouped by area.
+// The collector may be a user ID or username; an empty date means today in the business timezone.
+func (s *CollectionService) GetCollectionSheet(collector, date string) (*models.CollectionSheet, error) {
+    user, err := s.findCollector(collector)
+    if err != nil {
+        return nil, err
+    }
+
+    day := period.Today(s.periods).FirstDay()
+    if date != "" {
+        if day, err = time.Parse("2006-01-02", date); err != nil {
+            return nil, fmt.Errorf("invalid date: %w", err)
+        }
+    }
+
+    loans, err := s.loanRepo.FindByCollector(user.ID, []models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue})
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    loanIDs := make([]uint, len(loans))
+    for i, loan := range loans {
+        loanIDs[i] = loan.ID
+    }
+    paid, err := s.paymentRepo.SumPaidByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+
+    sheet := &models.CollectionSheet{
+        CollectorID:   user.ID,
+        CollectorName: user.Username,
+        Date:          day.Format("2006-01-02"),
+        Areas:         []models.CollectionSheetArea{},
+    }
+    calendars := make(map[uint]*HolidayCalendar)
+    byArea := make(map[string][]models.CollectionSheetLine)
+
+    for i := range loans {
+        loan := &loans[i]
+
+        var branchKey uint
+        if loan.BranchID != nil {
+            branchKey = *loan.BranchID
+        }
+        calendar, ok := calendars[branchKey]
+        if !ok {
+            if calendar, err = s.calendarService.CalendarForBranch(loan.BranchID); err != nil {
+                return nil, err
+            }
+            calendars[branchKey] = calendar
+        }
+        schedule := ScheduleForLoan(loan, calendar)
+
+        before := computeArrears(schedule, paid[loan.ID], day)
+        throughToday := computeArrears(schedule, paid[loan.ID], day.AddDate(0, 0, 1))
+        dueToday := roundCurrency(throughToday.AmountPastDue - before.AmountPastDue)
+
+        // Skip borrowers with nothing to collect unless an installment falls on the day
+        if throughToday.AmountPastDue <= 0 && !hasInstallmentOn(schedule, day) {
+            continue
+        }
+
+        weekBalance, err := s.paymentService.CalculateRemainingBalance(loan.ID, loan.PaidWeeks+1)
+        if err != nil {
+            return nil, fmt.Errorf("failed to get current balance: %w", err)
+        }
+
+        area := strings.TrimSpace(loan.Client.Area)
+        if area == "" {
+            area = noAreaLabel
+        }
+        byArea[area] = append(byArea[area], models.CollectionSheetLine{
+            LoanID:             loan.ID,
+            LoanControlNumber:  loan.ControlNumber,
+            ClientID:           loan.ClientID,
+            ClientName:         strings.Join(strings.Fields(loan.Client.LastName+", "+loan.Client.FirstName+" "+loan.Client.MiddleName), " "),
+            Address:            loan.Client.HomeAddress,
+            ContactNumber:      loan.Client.ContactNumber,
+            InstallmentNumber:  loan.PaidWeeks + 1,
+            Ammortization:      loan.Ammortization,
+            CurrentWeekBalance: roundCurrency(weekBalance),
+            Arrears:            before.AmountPastDue,
+            DaysPastDue:        before.DaysPastDue,
+            DueToday:           dueToday,
+            TotalDue:           throughToday.AmountPastDue,
+            OutstandingBalance: loan.OutstandingBalance,
+        })
+    }
+
+    areas := make([]string, 0, len(byArea))
+    for area := range byArea {
+        areas = append(areas, area)
+    }
+    sort.Strings(areas)
+
+    for _, area := range areas {
+        lines := byArea[area]
+        sort.Slice(lines, func(i, j int) bool { return lines[i].ClientName < lines[j].ClientName })
+
+        group := models.CollectionSheetArea{Area: area, Lines: lines}
+        for _, line := range lines {
+            group.TotalDue += line.TotalDue
+            sheet.TotalArrears += line.Arrears
+            sheet.TotalDueToday += line.DueToday
+        }
+        group.TotalDue = roundCurrency(group.TotalDue)
+        sheet.TotalDue += group.TotalDue
+        sheet.TotalAccounts += len(lines)
+        sheet.Areas = append(sheet.Areas, group)
+    }
+    sheet.TotalArrears = roundCurrency(sheet.TotalArrears)
+    sheet.TotalDueToday = roundCurrency(sheet.TotalDueToday)
+    sheet.TotalDue = roundCurrency(sheet.TotalDue)
+
+    return sheet, nil
+}
+
+// SheetTable lays out a collection sheet for printing, with blank columns the
+// collector fills in by hand and the borrower signs
+func (s *CollectionService) SheetTable(sheet *models.CollectionSheet) *export.Table {
+    table := &export.Table{
+        Title: "Daily Collection Sheet",
+        Subtitle: [][2]string{
+            {"Collector", sheet.CollectorName},
+            {"Date", sheet.Date},
+            {"Accounts", strconv.Itoa(sheet.TotalAccounts)},
+            {"Total to Collect", export.Money(sheet.TotalDue)},
+        },
+        Columns: []export.Column{
+            {Header: "Loan", Width: 30, Align: "L"},
+            {Header: "Borrower", Width: 44, Align: "L"},
+            {Header: "Inst.", Width: 12, Align: "C"},
+            {Header: "Amortization", Width: 24, Align: "R"},
+            {Header: "Week Balance", Width: 24, Align: "R"},
+            {Header: "Arrears", Width: 22, Align: "R"},
+            {Header: "DPD", Width: 12, Align: "C"},
+            {Header: "Total Due", Width: 24, Align: "R"},
+            {Header: "Collected", Width: 26, Align: "R"},
+            {Header: "Signature", Width: 42, Align: "L"},
+        },
+        GroupHeader: "Area",
+        Totals:      []string{"", "Total", "", "", "", export.Money(sheet.TotalArrears), "", export.Money(sheet.TotalDue), "", ""},
+        Footer:      fmt.Sprintf("Collector %s - %s", sheet.CollectorName, sheet.Date),
+        Landscape:   true,
+        RowHeight:   10,
+    }
+
+    for _, area := range sheet.Areas {
+        group := export.Group{Title: area.Area}
+        arrears := 0.0
+        for _, line := range area.Lines {
+            arrears += line.Arrears
+            dpd := ""
+            if line.DaysPastDue > 0 {
+                dpd = strconv.Itoa(line.DaysPastDue)
+            }
+            group.Rows = append(group.Rows, []string{
+                line.LoanControlNumber,
+                line.ClientName,
+                strconv.Itoa(line.InstallmentNumber),
+                export.Money(line.Ammortization),
+                export.Money(line.CurrentWeekBalance),
+                blankIfZero(line.Arrears),
+                dpd,
+                export.Money(line.TotalDue),
+                "",
+                "",
+            })
+        }
+        group.Subtotal = []string{"", fmt.Sprintf("Accounts: %d", len(area.Lines)), "", "", "", export.Money(roundCurrency(arrears)), "", export.Money(area.TotalDue), "", ""}
+        table.Groups = append(table.Groups, group)
+    }
+    return table
+}
+
+// PostCollectionBatch posts every amount collected on a sheet as payments in one transaction.
+// The batch must balance to the cash remitted, and if any line fails nothing is saved.
+func (s *CollectionService) PostCollectionBatch(req *models.CollectionBatchRequest) (*models.CollectionBatchResult, error) {
+    if _, err := time.Parse("2006-01-02", req.CollectionDate); err != nil {
+        return nil, fmt.Errorf("invalid collection date: %w", err)
+    }
+    method := req.PaymentMethod
+    if method == "" {
+        method = "Cash"
+    }
+
+    result := &models.CollectionBatchResult{
+        CollectionDate: req.CollectionDate,
+        CashRemitted:   roundCurrency(req.CashRemitted),
+        Lines:          make([]models.CollectionBatchLineResult, len(req.Lines)),
+    }
+    seen := make(map[uint]bool, len(req.Lines))
+    for i, line := range req.Lines {
+        result.Lines[i] = models.CollectionBatchLineResult{LoanID: line.LoanID, AmountCollected: roundCurrency(line.AmountCollected)}
+        switch {
+        case line.AmountCollected < 0:
+            result.Lines[i].Status = models.CollectionLineFailed
+            result.Lines[i].Error = "amount collected cannot be negative"
+        case seen[line.LoanID]:
+            result.Lines[i].Status = models.CollectionLineFailed
+            result.Lines[i].Error = "loan appears more than once in the batch"
+        }
+        seen[line.LoanID] = true
+        result.TotalCollected += line.AmountCollected
+    }
+    result.TotalCollected = roundCurrency(result.TotalCollected)
+    result.Variance = roundCurrency(result.CashRemitted - result.TotalCollected)
+
+    if result.Variance != 0 {
+        return result, fmt.Errorf("%w: remitted %s, collected %s", ErrRemittanceMismatch,
+            export.Money(result.CashRemitted), export.Money(result.TotalCollected))
+    }
+
+    err := s.paymentService.InTransaction(func(tx *PaymentService) error {
+        for i := range result.Lines {
+            line := &result.Lines[i]
+            if line.Status == models.CollectionLineFailed {
+                continue
+            }
+            if line.AmountCollected == 0 {
+                line.Status = models.CollectionLineSkipped
+                continue
+            }
+            if err := s.postBatchLine(tx, req, method, line); err != nil {
+                line.Status = models.CollectionLineFailed
+                line.Error = err.Error()
+                continue
+            }
+            line.Status = models.CollectionLinePosted
+        }
+
+        for _, line := range result.Lines {
+            if line.Status == models.CollectionLineFailed {
+                return ErrCollectionBatchFailed
+            }
+        }
+        return nil
+    })
+
+    for _, line := range result.Lines {
+        switch line.Status {
+        case models.CollectionLinePosted:
+            result.PostedCount++
+        case models.CollectionLineFailed:
+            result.FailedCount++
+        }
+    }
+    if err != nil {
+        // Rolled back, so payment IDs handed out inside the transaction no longer exist
+        for i := range result.Lines {
+            if result.Lines[i].Status == models.CollectionLinePosted {
+                result.Lines[i].Status = models.CollectionLineRolledBack
+            }
+            result.Lines[i].PaymentID = 0
+        }
+        result.PostedCount = 0
+        if errors.Is(err, ErrCollectionBatchFailed) {
+            return result, err
+        }
+        return result, fmt.Errorf("failed to post collection batch: %w", err)
+    }
+
+    result.Posted = true
+    return result, nil
+}
+
+// postBatchLine checks that a line's loan can take a field payment and posts it
+func (s *CollectionService) postBatchLine(tx *PaymentService, req *models.CollectionBatchRequest, method string, line *models.CollectionBatchLineResult) error {
+    loan, err := tx.loanRepo.FindByID(line.LoanID)
+    if err != nil {
+        return fmt.Errorf("loan not found")
+    }
+    if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusOverdue {
+        return fmt.Errorf("loan is %s", loan.Status)
+    }
+    if req.CollectorID != 0 && (loan.CollectorID == nil || *loan.CollectorID != req.CollectorID) {
+        return fmt.Errorf("loan is not assigned to this collector")
+    }
+
+    payment, err := tx.postCollectedAmount(loan, line.AmountCollected, req.CollectionDate, method, nil, req.ReceivedBy)
+    if err != nil {
+        return err
+    }
+
+    line.PaymentID = payment.ID
+    line.WeekNumber = payment.WeekNumber
+    line.IsPartial = payment.IsPartial
+    return nil
+}
+
+// findCollector resolves a collector given as a user ID or a username
+func (s *CollectionService) findCollector(collector string) (*models.User, error) {
+    collector = strings.TrimSpace(collector)
+    if collector == "" {
+        return nil, fmt.Errorf("collector is required")
+    }
+
+    if id, err := strconv.ParseUint(collector, 10, 32); err == nil {
+        if user, err := s.userRepo.FindByID(uint(id)); err == nil {
+            return user, nil
+        }
+    }
+    user, err := s.userRepo.FindByUsername(collector)
+    if err != nil {
+        return nil, fmt.Errorf("collector not found")
+    }
+    return user, nil
+}
+
+// hasInstallmentOn reports whether any installment falls due on the given day
+func hasInstallmentOn(schedule []models.Installment, day time.Time) bool {
+    for _, inst := range schedule {
+        if dateOnly(inst.DueDate).Equal(day) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "strings"
+)
+
+// ErrComplianceCapExceeded is returned when a loan priced above the caps is rejected
+var ErrComplianceCapExceeded = errors.New("loan exceeds compliance caps")
+
+// defaultComplianceSettings follow the BSP ceilings on small-value, short-term loans:
+// 6% nominal interest and 15% total cost of credit per month
+var defaultComplianceSettings = models.ComplianceSettings{
+    MaxNominalMonthlyRate:   0.06,
+    MaxEffectiveMonthlyRate: 0.15,
+    Action:                  models.ComplianceActionReject,
+}
+
+type ComplianceService struct {
+    complianceRepo  *repositories.ComplianceRepository
+    loanRepo        *repositories.LoanRepository
+    calendarService *CalendarService
+}
+
+func NewComplianceService(complianceRepo *repositories.ComplianceRepository, loanRepo *repositories.LoanRepository, calendarService *CalendarService) *ComplianceService {
+    return &ComplianceService{
+        complianceRepo:  complianceRepo,
+        loanRepo:        loanRepo,
+        calendarService: calendarService,
+    }
+}
+
+// GetSettings returns the saved caps, or the defaults until an admin saves their own
+func (s *ComplianceService) GetSettings() (*models.ComplianceSettings, error) {
+    saved, err := s.complianceRepo.FindSettings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get compliance settings: %w", err)
+    }
+    if saved != nil {
+        return saved, nil
+    }
+    defaults := defaultComplianceSettings
+    return &defaults, nil
+}
+
+// UpdateSettings saves new caps
+func (s *ComplianceService) UpdateSettings(req *models.ComplianceSettingsRequest, username string) (*models.ComplianceSettings, error) {
+    action := strings.ToLower(req.Action)
+    if action != models.ComplianceActionReject && action != models.ComplianceActionFlag {
+        return nil, fmt.Errorf("action must be reject or flag")
+    }
+
+    settings, err := s.complianceRepo.FindSettings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get compliance settings: %w", err)
+    }
+    if settings == nil {
+        settings = &models.ComplianceSettings{}
+    }
+    settings.MaxNominalMonthlyRate = req.MaxNominalMonthlyRate
+    settings.MaxEffectiveMonthlyRate = req.MaxEffectiveMonthlyRate
+    settings.MaxFeeRate = req.MaxFeeRate
+    settings.Action = action
+    settings.UpdatedBy = username
+
+    saved, err := s.complianceRepo.SaveSettings(settings)
+    if err != nil {
+        return nil, fmt.Errorf("failed to save compliance settings: %w", err)
+    }
+    return saved, nil
+}
+
+// CheckSimulation tests a quote against the caps so officers see problems before booking
+func (s *ComplianceService) CheckSimulation(sim *models.LoanSimulation) (*models.ComplianceCheck, error) {
+    settings, err := s.GetSettings()
+    if err != nil {
+        return nil, err
+    }
+
+    feeRate := 0.0
+    if sim.Principal > 0 {
+        feeRate = sim.TotalDeductions / sim.Principal
+    }
+    return evaluateCompliance(settings, sim.InterestRate, monthlyCostOfCredit(sim.TotalAmount, sim.NetRelease, sim.Terms), feeRate), nil
+}
+
+// EnforceOnNewLoan checks a loan about to be booked and sets its compliance fields.
+// Above-cap loans are rejected, flagged for review, or accepted when an admin override is given.
+func (s *ComplianceService) EnforceOnNewLoan(loan *models.Loan, schedule []models.Installment, override *models.ComplianceOverrideRequest) (*models.ComplianceCheck, error) {
+    settings, err := s.GetSettings()
+    if err != nil {
+        return nil, err
+    }
+
+    check := checkLoanCompliance(settings, loan, schedule)
+    loan.EffectiveMonthlyRate = check.EffectiveMonthlyRate
+
+    switch {
+    case check.Compliant:
+        loan.ComplianceStatus = models.ComplianceStatusCompliant
+    case override != nil:
+        if override.ApprovedBy == "" {
+            return nil, fmt.Errorf("compliance override requires an admin")
+        }
+        loan.ComplianceStatus = models.ComplianceStatusOverridden
+    case settings.Action == models.ComplianceActionFlag:
+        loan.ComplianceStatus = models.ComplianceStatusFlagged
+    default:
+        return check, fmt.Errorf("%w: %s", ErrComplianceCapExceeded, strings.Join(check.Violations, "; "))
+    }
+    return check, nil
+}
+
+// NewOverrideRecord builds the audit entry saved alongside an overridden loan
+func (s *ComplianceService) NewOverrideRecord(check *models.ComplianceCheck, override *models.ComplianceOverrideRequest) *models.ComplianceOverride {
+    return &models.ComplianceOverride{
+        Reason:               override.Reason,
+        ApprovedBy:           override.ApprovedBy,
+        ApprovedByID:         override.ApprovedByID,
+        NominalMonthlyRate:   check.NominalMonthlyRate,
+        EffectiveMonthlyRate: check.EffectiveMonthlyRate,
+        FeeRate:              check.FeeRate,
+        Violations:           strings.Join(check.Violations, "; "),
+    }
+}
+
+// OverrideFlaggedLoan lets an admin accept a loan that was booked and flagged
+func (s *ComplianceService) OverrideFlaggedLoan(loanID uint, override *models.ComplianceOverrideRequest) (*models.ComplianceOverride, error) {
+    loan, err := s.loanRepo.FindByID(loanID)
+    if err != nil {
+        return nil, fmt.Errorf("loan not found")
+    }
+    if loan.ComplianceStatus != models.ComplianceStatusFlagged {
+        return nil, fmt.Errorf("loan is not flagged for compliance review")
+    }
+
+    settings, err := s.GetSettings()
+    if err != nil {
+        return nil, err
+    }
+    schedule, err := s.calendarService.ScheduleForLoan(loan)
+    if err != nil {
+        return nil, err
+    }
+    check := checkLoanCompliance(settings, loan, schedule)
+
+    record := s.NewOverrideRecord(check, override)
+    record.LoanID = loan.ID
+    created, err := s.complianceRepo.CreateOverride(record)
+    if err != nil {
+        return nil, fmt.Errorf("failed to record override: %w", err)
+    }
+    if err := s.complianceRepo.UpdateLoanComplianceStatus(loan.ID, models.ComplianceStatusOverridden); err != nil {
+        return nil, fmt.Errorf("failed to update loan: %w", err)
+    }
+    return created, nil
+}
+
+// GetFlaggedLoans lists loans awaiting compliance review
+func (s *ComplianceService) GetFlaggedLoans() ([]models.Loan, error) {
+    loans, err := s.complianceRepo.FindFlaggedLoans()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get flagged loans: %w", err)
+    }
+    return loans, nil
+}
+
+// GetOverrides returns the override audit trail with pagination
+func (s *ComplianceService) GetOverrides(page, limit int) ([]models.ComplianceOverride, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+
+    overrides, err := s.complianceRepo.FindOverrides((page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get overrides: %w", err)
+    }
+    total, err := s.complianceRepo.CountOverrides()
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to count overrides: %w", err)
+    }
+    return overrides, total, nil
+}
+
+// checkLoanCompliance derives a loan's monthly rates and fee share from its stored figures.
+// Manually priced loans have no quoted rate and are held to the effective rate cap alone.
+func checkLoanCompliance(settings *models.ComplianceSettings, loan *models.Loan, schedule []models.Installment) *models.ComplianceCheck {
+    effective := monthlyCostOfCredit(loan.TotalAmount, loan.AmountRelease, loan.Terms)
+
+    feeRate := 0.0
+    if loan.Principal > 0 {
+        fees := math.Max(loan.Principal-loan.AmountRelease, 0)
+        for _, inst := range schedule {
+            fees += inst.Charges
+        }
+        feeRate = fees / loan.Principal
+    }
+
+    return evaluateCompliance(settings, loan.InterestRate, effective, feeRate)
+}
+
+// monthlyCostOfCredit is everything the borrower pays beyond the net proceeds (interest,
+// financed fees and deductions) per month of term, as a share of the net proceeds.
+// This is how the regulator states its total-cost ceiling, so it is not compounded.
+func monthlyCostOfCredit(totalAmount, netRelease float64, terms int) float64 {
+    if netRelease <= 0 || terms <= 0 {
+        return 0
+    }
+    return math.Max(totalAmount-netRelease, 0) / netRelease / float64(terms)
+}
+
+// evaluateCompliance compares rates against each configured cap
+func evaluateCompliance(settings *models.ComplianceSettings, nominal, effective, feeRate float64) *models.ComplianceCheck {
+    check := &models.ComplianceCheck{
+        NominalMonthlyRate:   roundRate(nominal),
+        EffectiveMonthlyRate: roundRate(effective),
+        FeeRate:              roundRate(feeRate),
+        Violations:           []string{},
+    }
+
+    if settings.MaxNominalMonthlyRate > 0 && check.NominalMonthlyRate > settings.MaxNominalMonthlyRate {
+        check.Violations = append(check.Violations, fmt.Sprintf("nominal rate %.2f%% per month exceeds the %.2f%% cap",
+            check.NominalMonthlyRate*100, settings.MaxNominalMonthlyRate*100))
+    }
+    if settings.MaxEffectiveMonthlyRate > 0 && check.EffectiveMonthlyRate > settings.MaxEffectiveMonthlyRate {
+        check.Violations = append(check.Violations, fmt.Sprintf("effective rate %.2f%% per month exceeds the %.2f%% cap",
+            check.EffectiveMonthlyRate*100, settings.MaxEffectiveMonthlyRate*100))
+    }
+    if settings.MaxFeeRate > 0 && check.FeeRate > settings.MaxFeeRate {
+        check.Violations = append(check.Violations, fmt.Sprintf("fees of %.2f%% of principal exceed the %.2f%% cap",
+            check.FeeRate*100, settings.MaxFeeRate*100))
+    }
+
+    check.Compliant = len(check.Violations) == 0
+    return check
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+    "bytes"
+    "fmt"
+    "micro-lending-platform/backend/internal/export"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "strings"
+    "text/template"
+    "time"
+)
+
+type DocumentService struct {
+    templateRepo    *repositories.DocumentTemplateRepository
+    loanRepo        *repositories.LoanRepository
+    calendarService *CalendarService
+}
+
+func NewDocumentService(templateRepo *repositories.DocumentTemplateRepository, loanRepo *repositories.LoanRepository, calendarService *CalendarService) *DocumentService {
+    return &DocumentService{
+        templateRepo:    templateRepo,
+        loanRepo:        loanRepo,
+        calendarService: calendarService,
+    }
+}
+
+// LoanDocumentData is what document templates are rendered against
+type LoanDocumentData struct {
+    Loan                 models.Loan
+    Client               models.Client
+    ClientName           string
+    CoMakers             []models.CoMaker
+    CoMakerNames         []string
+    Schedule             []models.Installment
+    Principal            float64
+    DeductedCharges      float64
+    FinanceCharge        float64
+    EffectiveMonthlyRate float64
+    EffectiveAnnualRate  float64
+    FirstDueDate         time.Time
+    LastDueDate          time.Time
+    Today                time.Time
+}
+
+// defaultDocumentTemplates are used until an admin saves their own wording
+var defaultDocumentTemplates = map[string]models.DocumentTemplate{
+    models.DocumentTypeDisclosure: {
+        Type:  models.DocumentTypeDisclosure,
+        Title: "Disclosure Statement on Loan/Credit Transaction",
+        Body: `Borrower: {{.ClientName}}
+Address: {{.Client.HomeAddress}}
+Loan Control No.: {{.Loan.ControlNumber}}
+Date of Release: {{date .Loan.DateOfRelease}}
+
+1. Amount of loan (principal): PHP {{money .Principal}}
+2. Charges deducted from the loan proceeds: PHP {{money .DeductedCharges}}
+3. Net proceeds of the loan: PHP {{money .Loan.AmountRelease}}
+4. Finance charges (interest and all other charges): PHP {{money .FinanceCharge}}
+5. Total amount payable: PHP {{money .Loan.TotalAmount}}
+6. Payable in {{len .Schedule}} {{lower .Loan.Mode}} installments of PHP {{money .Loan.Ammortization}}, from {{date .FirstDueDate}} to {{date .LastDueDate}}
+7. Effective interest rate: {{percent .EffectiveMonthlyRate}} per month ({{percent .EffectiveAnnualRate}} per annum)
+
+I acknowledge receipt of a copy of this statement prior to the consummation of the credit transaction, and that I understand and fully agree to the terms and conditions thereof.`,
+    },
+    models.DocumentTypePromissoryNote: {
+        Type:  models.DocumentTypePromissoryNote,
+        Title: "Promissory Note",
+        Body: `PHP {{money .Loan.TotalAmount}}                                                        Date: {{date .Loan.DateOfRelease}}
+Loan Control No.: {{.Loan.ControlNumber}}
+
+FOR VALUE RECEIVED, I, {{.ClientName}}, of {{.Client.HomeAddress}}, promise to pay to the order of the lender the sum of PHP {{money .Loan.TotalAmount}}, payable in {{len .Schedule}} {{lower .Loan.Mode}} installments of PHP {{money .Loan.Ammortization}} each, the first installment falling due on {{date .FirstDueDate}} and the last on {{date .LastDueDate}}, in accordance with the schedule below.
+
+Failure to pay any installment when due shall make the entire unpaid balance immediately due and demandable at the option of the lender.
+{{if .CoMakerNames}}
+The undersigned co-maker(s), {{join .CoMakerNames ", "}}, bind themselves jointly and severally with the borrower for the full payment of this note.{{end}}`,
+    },
+}
+
+var documentTemplateFuncs = template.FuncMap{
+    "money":   export.Money,
+    "date":    func(t time.Time) string { return t.Format("January 2, 2006") },
+    "percent": func(rate float64) string { return fmt.Sprintf("%.2f%%", rate*100) },
+    "lower":   strings.ToLower,
+    "upper":   strings.ToUpper,
+    "join":    strings.Join,
+}
+
+// GetTemplate returns the saved template for a document type, or the default wording
+func (s *DocumentService) GetTemplate(docType string) (*models.DocumentTemplate, error) {
+    defaultTemplate, ok := defaultDocumentTemplates[docType]
+    if !ok {
+        return nil, fmt.Errorf("unknown document type: %s", docType)
+    }
+
+    saved, err := s.templateRepo.FindByType(docType)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get template: %w", err)
+    }
+    if saved != nil {
+        return saved, nil
+    }
+    return &defaultTemplate, nil
+}
+
+// ListTemplates returns the effective template for every document type
+func (s *DocumentService) ListTemplates() ([]models.DocumentTemplate, error) {
+    templates := make([]models.DocumentTemplate, 0, len(defaultDocumentTemplates))
+    for _, docType := range []string{models.DocumentTypeDisclosure, models.DocumentTypePromissoryNote} {
+        t, err := s.GetTemplate(docType)
+        if err != nil {
+            return nil, err
+        }
+        templates = append(templates, *t)
+    }
+    return templates, nil
+}
+
+// UpdateTemplate validates and saves an admin's template wording
+func (s *DocumentService) UpdateTemplate(docType string, req *models.DocumentTemplateUpdateRequest, username string) (*models.DocumentTemplate, error) {
+    if _, ok := defaultDocumentTemplates[docType]; !ok {
+        return nil, fmt.Errorf("unknown document type: %s", docType)
+    }
+    if _, err := template.New(docType).Funcs(documentTemplateFuncs).Parse(req.Body); err != nil {
+        return nil, fmt.Errorf("invalid template: %w", err)
+    }
+
+    existing, err := s.templateRepo.FindByType(docType)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get template: %w", err)
+    }
+    if existing == nil {
+        existing = &models.DocumentTemplate{Type: docType}
+    }
+    existing.Title = req.Title
+    existing.Body = req.Body
+    existing.UpdatedBy = username
+
+    saved, err := s.templateRepo.Save(existing)
+    if err != nil {
+        return nil, fmt.Errorf("failed to save template: %w", err)
+    }
+    return saved, nil
+}
+
+// GenerateLoanDocument renders a loan document as PDF
+func (s *DocumentService) GenerateLoanDocument(loanID uint, docType string) ([]byte, error) {
+    tmpl, err := s.GetTemplate(docType)
+    if err != nil {
+        return nil, err
+    }
+
+    loan, err := s.loanRepo.FindWithClient(loanID)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("loan not found")
+        }
+        return nil, fmt.Errorf("failed to get loan: %w", err)
+    }
+
+    schedule, err := s.calendarService.ScheduleForLoan(loan)
+    if err != nil {
+        return nil, err
+    }
+    data := s.buildDocumentData(loan, schedule)
+
+    parsed, err := template.New(docType).Funcs(documentTemplateFuncs).Parse(tmpl.Body)
+    if err != nil {
+        return nil, fmt.Errorf("invalid template: %w", err)
+    }
+    var body bytes.Buffer
+    if err := parsed.Execute(&body, data); err != nil {
+        return nil, fmt.Errorf("failed to fill template: %w", err)
+    }
+
+    doc := export.NewPDF(tmpl.Title, export.PDFOptions{Footer: "Loan " + loan.ControlNumber})
+    doc.Paragraph(body.String())
+
+    doc.Heading("Schedule of Payments")
+    rows := make([][]string, 0, len(data.Schedule))
+    for _, inst := range data.Schedule {
+        rows = append(rows, []string{
+            fmt.Sprintf("%d", inst.Number),
+            inst.DueDate.Format("2006-01-02"),
+            export.Money(inst.Principal),
+            export.Money(inst.Interest),
+            export.Money(inst.Charges),
+            export.Money(inst.AmountDue),
+            export.Money(inst.RemainingBalance),
+        })
+    }
+    doc.Table(
+        []string{"No.", "Due Date", "Principal", "Interest", "Charges", "Amount Due", "Balance"},
+        []float64{12, 28, 28, 26, 24, 30, 32},
+        []string{"C", "C", "R", "R", "R", "R", "R"},
+        rows, nil,
+    )
+
+    signatures := [][2]string{{data.ClientName, "Borrower"}}
+    for _, name := range data.CoMakerNames {
+        signatures = append(signatures, [2]string{name, "Co-Maker"})
+    }
+    signatures = append(signatures,
+        [2]string{loan.ApprovedBy, "Approved by"},
+        [2]string{loan.NotedBy, "Noted by"},
+    )
+    doc.SignatureBlocks(signatures)
+
+    return doc.Bytes()
+}
+
+// buildDocumentData derives the figures printed on loan documents
+func (s *DocumentService) buildDocumentData(loan *models.Loan, schedule []models.Installment) *LoanDocumentData {
+    principal := loan.Principal
+    if principal <= 0 {
+        principal = loan.AmountRelease
+    }
+
+    data := &LoanDocumentData{
+        Loan:            *loan,
+        Client:          loan.Client,
+        ClientName:      strings.Join(strings.Fields(loan.Client.FirstName+" "+loan.Client.MiddleName+" "+loan.Client.LastName), " "),
+        CoMakers:        loan.CoMakers,
+        Schedule:        schedule,
+        Principal:       principal,
+        DeductedCharges: roundCurrency(principal - loan.AmountRelease),
+        FinanceCharge:   roundCurrency(loan.TotalAmount - loan.AmountRelease),
+        Today:           time.Now(),
+    }
+    for _, cm := range loan.CoMakers {
+        data.CoMakerNames = append(data.CoMakerNames, cm.Name)
+    }
+    if len(schedule) > 0 {
+        data.FirstDueDate = schedule[0].DueDate
+        data.LastDueDate = schedule[len(schedule)-1].DueDate
+    }
+    data.EffectiveMonthlyRate, data.EffectiveAnnualRate = effectiveRatesForLoan(loan, schedule)
+
+    return data
+}
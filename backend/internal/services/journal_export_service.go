@@ -0,0 +1,330 @@
+package services
+
+import (
+    "bytes"
+    "encoding/csv"
+    "errors"
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "micro-lending-platform/backend/internal/repositories"
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// xeroTaxRate is the tax rate name put on every Xero journal line; loans and their income are exempt
+const xeroTaxRate = "Tax Exempt"
+
+type JournalExportService struct {
+    exportRepo    *repositories.JournalExportRepository
+    ledgerService *LedgerService
+}
+
+func NewJournalExportService(exportRepo *repositories.JournalExportRepository, ledgerService *LedgerService) *JournalExportService {
+    return &JournalExportService{
+        exportRepo:    exportRepo,
+        ledgerService: ledgerService,
+    }
+}
+
+// GetMappings returns the external code and name set for each account
+func (s *JournalExportService) GetMappings() ([]models.LedgerAccountMapping, error) {
+    mappings, err := s.exportRepo.FindMappings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get account mapping: %w", err)
+    }
+    return mappings, nil
+}
+
+// UpdateMappings replaces the account mapping; it applies from the next export
+func (s *JournalExportService) UpdateMappings(req *models.LedgerAccountMappingRequest) ([]models.LedgerAccountMapping, error) {
+    accounts, err := s.ledgerService.GetAccounts()
+    if err != nil {
+        return nil, err
+    }
+    known := make(map[string]bool, len(accounts))
+    for _, account := range accounts {
+        known[account.Code] = true
+    }
+
+    var mappings []models.LedgerAccountMapping
+    seen := make(map[string]bool)
+    for _, line := range req.Mappings {
+        if !known[line.AccountCode] {
+            return nil, fmt.Errorf("account %s does not exist", line.AccountCode)
+        }
+        if seen[line.AccountCode] {
+            return nil, fmt.Errorf("account %s is mapped more than once", line.AccountCode)
+        }
+        seen[line.AccountCode] = true
+        code, name := strings.TrimSpace(line.ExternalCode), strings.TrimSpace(line.ExternalName)
+        if code == "" && name == "" {
+            continue
+        }
+        mappings = append(mappings, models.LedgerAccountMapping{AccountCode: line.AccountCode, ExternalCode: code, ExternalName: name})
+    }
+
+    if err := s.exportRepo.SaveMappings(mappings); err != nil {
+        return nil, fmt.Errorf("failed to save account mapping: %w", err)
+    }
+    return s.GetMappings()
+}
+
+// Export brings the ledger up to date and writes every entry dated in a range that no standing batch
+// has exported yet to a file in the given format, recording the batch
+func (s *JournalExportService) Export(r period.Range, format string, username string) (*models.JournalExportBatch, error) {
+    if _, err := s.ledgerService.SyncAll(); err != nil {
+        return nil, err
+    }
+    entries, err := s.exportRepo.FindUnexportedEntries(r.FirstDay(), r.LastDay().AddDate(0, 0, 1))
+    if err != nil {
+        return nil, fmt.Errorf("failed to get journal entries: %w", err)
+    }
+    if len(entries) == 0 {
+        return nil, fmt.Errorf("no journal entries from %s to %s are waiting to be exported", r.From(), r.To())
+    }
+    accounts, err := s.externalAccounts()
+    if err != nil {
+        return nil, err
+    }
+
+    var content []byte
+    switch format {
+    case models.JournalExportCSV:
+        content, err = journalCSV(entries, accounts)
+    case models.JournalExportIIF:
+        content = journalIIF(entries, accounts)
+    case models.JournalExportXero:
+        content, err = journalXero(entries, accounts)
+    default:
+        return nil, fmt.Errorf("format must be csv, iif or xero")
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to write export: %w", err)
+    }
+
+    batch := &models.JournalExportBatch{
+        Format:      format,
+        PeriodStart: r.FirstDay(),
+        PeriodEnd:   r.LastDay(),
+        Entries:     len(entries),
+        Content:     string(content),
+        ExportedBy:  username,
+    }
+    entryIDs := make([]uint, len(entries))
+    for i, entry := range entries {
+        entryIDs[i] = entry.ID
+        for _, line := range entry.Lines {
+            batch.TotalDebit += line.Debit
+        }
+    }
+    batch.TotalDebit = roundCurrency(batch.TotalDebit)
+
+    saved, err := s.exportRepo.CreateBatch(batch, entryIDs)
+    if err != nil {
+        if errors.Is(err, repositories.ErrAlreadyExported) {
+            return nil, err
+        }
+        return nil, fmt.Errorf("failed to save export batch: %w", err)
+    }
+    return saved, nil
+}
+
+// GetBatches lists export batches with pagination, newest first
+func (s *JournalExportService) GetBatches(page, limit int) ([]models.JournalExportBatch, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+    batches, total, err := s.exportRepo.FindBatches((page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get export batches: %w", err)
+    }
+    return batches, total, nil
+}
+
+// GetBatch returns an export batch with its file
+func (s *JournalExportService) GetBatch(id uint) (*models.JournalExportBatch, error) {
+    batch, err := s.exportRepo.FindBatchByID(id)
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, fmt.Errorf("export batch not found")
+        }
+        return nil, fmt.Errorf("failed to get export batch: %w", err)
+    }
+    return batch, nil
+}
+
+// VoidBatch marks a batch as not imported, freeing its entries for the next export
+func (s *JournalExportService) VoidBatch(id uint, req *models.JournalExportVoidRequest, username string) (*models.JournalExportBatch, error) {
+    batch, err := s.GetBatch(id)
+    if err != nil {
+        return nil, err
+    }
+    if batch.VoidedAt != nil {
+        return nil, fmt.Errorf("export batch is already voided")
+    }
+    now := time.Now()
+    batch.VoidedAt = &now
+    batch.VoidedBy = username
+    batch.VoidReason = req.Reason
+    if err := s.exportRepo.VoidBatch(batch); err != nil {
+        return nil, fmt.Errorf("failed to void export batch: %w", err)
+    }
+    return batch, nil
+}
+
+// JournalExportFile names a batch's file and gives its content type
+func JournalExportFile(batch *models.JournalExportBatch) (filename, contentType string) {
+    name := fmt.Sprintf("journal-%s-to-%s-batch-%d", batch.PeriodStart.Format(period.DateLayout), batch.PeriodEnd.Format(period.DateLayout), batch.ID)
+    switch batch.Format {
+    case models.JournalExportIIF:
+        return name + ".iif", "text/plain; charset=utf-8"
+    case models.JournalExportXero:
+        return name + "-xero.csv", "text/csv; charset=utf-8"
+    }
+    return name + ".csv", "text/csv; charset=utf-8"
+}
+
+// externalAccount is the code and name an account is exported under
+type externalAccount struct {
+    code string
+    name string
+}
+
+// externalAccounts maps every account code to its external code and name, its own where not mapped
+func (s *JournalExportService) externalAccounts() (map[string]externalAccount, error) {
+    accounts, err := s.ledgerService.GetAccounts()
+    if err != nil {
+        return nil, err
+    }
+    mappings, err := s.GetMappings()
+    if err != nil {
+        return nil, err
+    }
+    result := make(map[string]externalAccount, len(accounts))
+    for _, account := range accounts {
+        result[account.Code] = externalAccount{code: account.Code, name: account.Name}
+    }
+    for _, mapping := range mappings {
+        account := result[mapping.AccountCode]
+        if mapping.ExternalCode != "" {
+            account.code = mapping.ExternalCode
+        }
+        if mapping.ExternalName != "" {
+            account.name = mapping.ExternalName
+        }
+        result[mapping.AccountCode] = account
+    }
+    return result, nil
+}
+
+// accountFor looks up a line's external account, falling back to the code itself
+func accountFor(accounts map[string]externalAccount, code string) externalAccount {
+    if account, ok := accounts[code]; ok {
+        return account
+    }
+    return externalAccount{code: code, name: code}
+}
+
+// journalNumber is the document number an entry is exported under
+func journalNumber(entry models.JournalEntry) string {
+    return fmt.Sprintf("JE-%d", entry.ID)
+}
+
+// journalCSV writes the generic journal: one row per line, debits and credits in their own columns
+func journalCSV(entries []models.JournalEntry, accounts map[string]externalAccount) ([]byte, error) {
+    var buf bytes.Buffer
+    w := csv.NewWriter(&buf)
+    rows := [][]string{{"Date", "Journal No", "Source", "Reference", "Description", "Account Code", "Account Name", "Debit", "Credit", "Memo"}}
+    for _, entry := range entries {
+        for _, line := range entry.Lines {
+            account := accountFor(accounts, line.AccountCode)
+            rows = append(rows, []string{
+                entry.EntryDate.Format(period.DateLayout),
+                journalNumber(entry),
+                entry.Source,
+                entry.Reference,
+                entry.Description,
+                account.code,
+                account.name,
+                exportAmount(line.Debit),
+                exportAmount(line.Credit),
+                line.Memo,
+            })
+        }
+    }
+    if err := w.WriteAll(rows); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// journalIIF writes QuickBooks general journal transactions. QuickBooks matches accounts by name;
+// debits are positive amounts and credits negative, and the first line of each entry is its TRNS line.
+func journalIIF(entries []models.JournalEntry, accounts map[string]externalAccount) []byte {
+    var buf bytes.Buffer
+    row := func(fields ...string) {
+        for i, field := range fields {
+            fields[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ", `"`, "'").Replace(field)
+        }
+        buf.WriteString(strings.Join(fields, "\t"))
+        buf.WriteString("\r\n")
+    }
+    row("!TRNS", "TRNSID", "TRNSTYPE", "DATE", "ACCNT", "AMOUNT", "DOCNUM", "MEMO")
+    row("!SPL", "SPLID", "TRNSTYPE", "DATE", "ACCNT", "AMOUNT", "DOCNUM", "MEMO")
+    row("!ENDTRNS")
+    for _, entry := range entries {
+        for i, line := range entry.Lines {
+            kind := "SPL"
+            if i == 0 {
+                kind = "TRNS"
+            }
+            memo := line.Memo
+            if memo == "" {
+                memo = entry.Description
+            }
+            row(kind, "", "GENERAL JOURNAL", entry.EntryDate.Format("01/02/2006"), accountFor(accounts, line.AccountCode).name,
+                fmt.Sprintf("%.2f", line.Debit-line.Credit), journalNumber(entry), memo)
+        }
+        row("ENDTRNS")
+    }
+    return buf.Bytes()
+}
+
+// journalXero writes Xero's manual journal import. Lines sharing a narration and date become one
+// journal, so each entry's narration leads with its number; debits are positive and credits negative.
+func journalXero(entries []models.JournalEntry, accounts map[string]externalAccount) ([]byte, error) {
+    var buf bytes.Buffer
+    w := csv.NewWriter(&buf)
+    rows := [][]string{{"*Narration", "*Date", "Description", "*AccountCode", "*TaxRate", "*Amount"}}
+    for _, entry := range entries {
+        narration := journalNumber(entry) + " " + entry.Description
+        for _, line := range entry.Lines {
+            rows = append(rows, []string{
+                narration,
+                entry.EntryDate.Format(period.DateLayout),
+                line.Memo,
+                accountFor(accounts, line.AccountCode).code,
+                xeroTaxRate,
+                fmt.Sprintf("%.2f", line.Debit-line.Credit),
+            })
+        }
+    }
+    if err := w.WriteAll(rows); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// exportAmount writes an amount to two decimals, blank when zero
+func exportAmount(amount float64) string {
+    if amount == 0 {
+        return ""
+    }
+    return fmt.Sprintf("%.2f", amount)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+    "fmt"
+    "math"
+    "micro-lending-platform/backend/internal/models"
+    "time"
+)
+
+// loanBook is one loan's journal entries and the running balance of each account while the loan is synced
+type loanBook struct {
+    loan     *models.Loan
+    entries  []models.JournalEntry
+    keys     map[string]bool
+    balances map[string]float64 // Net debit per account code
+    posted   int                // Entries posted during this sync
+
+    closedThrough time.Time // Last day of the latest closed period, zero when none is closed
+}
+
+func newLoanBook(loan *models.Loan, entries []models.JournalEntry) *loanBook {
+    book := &loanBook{loan: loan, keys: make(map[string]bool), balances: make(map[string]float64)}
+    for _, entry := range entries {
+        book.track(entry)
+    }
+    return book
+}
+
+// track records an entry's key and lines without counting it as posted
+func (b *loanBook) track(entry models.JournalEntry) {
+    b.entries = append(b.entries, entry)
+    if entry.SourceKey != nil {
+        b.keys[*entry.SourceKey] = true
+    }
+    for _, line := range entry.Lines {
+        b.balances[line.AccountCode] += line.Debit - line.Credit
+    }
+}
+
+// add records an entry posted during this sync
+func (b *loanBook) add(entry models.JournalEntry) {
+    b.track(entry)
+    b.posted++
+}
+
+// reverse records that an entry was reversed by a newly posted one
+func (b *loanBook) reverse(entryID uint, reversal models.JournalEntry) {
+    for i := range b.entries {
+        if b.entries[i].ID == entryID {
+            b.entries[i].ReversedByID = &reversal.ID
+        }
+    }
+    b.add(reversal)
+}
+
+// closed reports whether a day falls in a closed period
+func (b *loanBook) closed(day time.Time) bool {
+    return !b.closedThrough.IsZero() && !day.After(b.closedThrough)
+}
+
+// openDate moves an entry dated in a closed period to the first day after it
+func (b *loanBook) openDate(day time.Time) time.Time {
+    if b.closed(day) {
+        return b.closedThrough.AddDate(0, 0, 1)
+    }
+    return day
+}
+
+// has reports whether an entry with the source key was ever posted, even if since reversed
+func (b *loanBook) has(sourceKey string) bool {
+    return b.keys[sourceKey]
+}
+
+// active returns entries from a source that still stand; a blank source means every source
+func (b *loanBook) active(source string) []models.JournalEntry {
+    var result []models.JournalEntry
+    for _, entry := range b.entries {
+        if entry.ReversedByID != nil || entry.Source == models.JournalSourceReversal {
+            continue
+        }
+        if source == "" || entry.Source == source {
+            result = append(result, entry)
+        }
+    }
+    return result
+}
+
+// forPayment returns the standing entries posted for a payment
+func (b *loanBook) forPayment(paymentID uint) []models.JournalEntry {
+    var result []models.JournalEntry
+    for _, entry := range b.active(models.JournalSourcePayment) {
+        if entry.PaymentID != nil && *entry.PaymentID == paymentID {
+            result = append(result, entry)
+        }
+    }
+    return result
+}
+
+// count is how many entries a source ever posted, optionally for one payment, so reposts get new keys
+func (b *loanBook) count(source string, paymentID *uint) int {
+    n := 0
+    for _, entry := range b.entries {
+        if entry.Source != source {
+            continue
+        }
+        if paymentID != nil && (entry.PaymentID == nil || *entry.PaymentID != *paymentID) {
+            continue
+        }
+        n++
+    }
+    return n
+}
+
+// balanceThrough is an account's net debit balance in the entries dated on or before a day
+func (b *loanBook) balanceThrough(code string, day time.Time) float64 {
+    total := 0.0
+    for _, entry := range b.entries {
+        if entry.EntryDate.After(day) {
+            continue
+        }
+        for _, line := range entry.Lines {
+            if line.AccountCode == code {
+                total += line.Debit - line.Credit
+            }
+        }
+    }
+    return roundCurrency(total)
+}
+
+// paymentEntry books money received on the loan. It settles the schedule first and charges after,
+// like collections are applied elsewhere, and anything beyond the balance is held for the client.
+// Interest stays unearned until the accrual run recognizes it. Money received after a loan was
+// written off is a recovery.
+func (b *loanBook) paymentEntry(payment models.Payment, schedule []models.Installment, entryDate time.Time) *models.JournalEntry {
+    var lines entryLines
+    method := payment.PaymentMethod
+    if method == "" {
+        method = "Cash"
+    }
+    lines.debit(cashAccount(payment.PaymentMethod), payment.AmountPaid, "Received by "+method)
+
+    if len(b.active(models.JournalSourceWriteOff)) > 0 {
+        lines.credit(models.AccountRecoveries, payment.AmountPaid, "Recovery on written-off loan")
+    } else {
+        loans := math.Max(b.balances[models.AccountLoansReceivable], 0)
+        toLoan := math.Min(payment.AmountPaid, loans)
+        toCharges := math.Min(payment.AmountPaid-toLoan, math.Max(b.balances[models.AccountChargesReceivable], 0))
+        over := payment.AmountPaid - toLoan - toCharges
+
+        principal, interest, financed := scheduleSplit(schedule, b.loan.TotalAmount-loans, toLoan)
+        lines.credit(models.AccountLoansReceivable, principal, "Principal")
+        lines.credit(models.AccountLoansReceivable, interest, "Interest")
+        lines.credit(models.AccountLoansReceivable, financed, "Financed charges")
+        lines.credit(models.AccountChargesReceivable, toCharges, "Penalties and fees")
+        lines.credit(models.AccountClientOverpayments, over, "Paid beyond the balance")
+    }
+
+    return &models.JournalEntry{
+        EntryDate:   entryDate,
+        Source:      models.JournalSourcePayment,
+        Description: fmt.Sprintf("Payment #%d on loan %s", payment.ID, b.loan.ControlNumber),
+        PaymentID:   &payment.ID,
+        Lines:       lines,
+    }
+}
+
+// writeOffEntry takes a defaulted loan off the books: the interest not yet earned is dropped and the
+// rest of what the borrower owes is charged to loan losses
+func (b *loanBook) writeOffEntry(entryDate time.Time) *models.JournalEntry {
+    loans := b.balances[models.AccountLoansReceivable]
+    charges := b.balances[models.AccountChargesReceivable]
+    unearned := -b.balances[models.AccountUnearnedInterest]
+
+    var lines entryLines
+    lines.debit(models.AccountUnearnedInterest, unearned, "Interest not earned")
+    lines.debit(models.AccountLoanLosses, loans+charges-unearned, "Balance written off")
+    lines.credit(models.AccountLoansReceivable, loans, "")
+    lines.credit(models.AccountChargesReceivable, charges, "")
+    return &models.JournalEntry{
+        EntryDate:   entryDate,
+        Source:      models.JournalSourceWriteOff,
+        Description: fmt.Sprintf("Write-off of loan %s", b.loan.ControlNumber),
+        Lines:       lines,
+    }
+}
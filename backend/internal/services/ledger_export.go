@@ -0,0 +1,119 @@
+package services
+
+import (
+    "micro-lending-platform/backend/internal/export"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "time"
+)
+
+// TrialBalanceTable lays out one row per account with its balance on the debit or credit side
+func (s *LedgerService) TrialBalanceTable(report *models.TrialBalance) *export.Table {
+    table := &export.Table{
+        Title:    "Trial Balance",
+        Subtitle: [][2]string{{"As of", report.AsOf}},
+        Columns: []export.Column{
+            {Header: "Code", Width: 20, Align: "L"},
+            {Header: "Account", Width: 70, Align: "L"},
+            {Header: "Type", Width: 25, Align: "L"},
+            {Header: "Debit", Width: 32, Align: "R"},
+            {Header: "Credit", Width: 32, Align: "R"},
+        },
+        Totals:  []string{"", "Total", "", export.Money(report.TotalDebit), export.Money(report.TotalCredit)},
+        Footer:  "General ledger | As of " + report.AsOf,
+        Printed: s.printed(),
+    }
+    for _, line := range report.Lines {
+        table.Rows = append(table.Rows, []string{line.Code, line.Name, line.Type, export.Money(line.Debit), export.Money(line.Credit)})
+    }
+    if report.Subledger != nil {
+        table.Subtitle = append(table.Subtitle,
+            [2]string{"Loan balances (current)", export.Money(report.Subledger.SubledgerOutstanding)},
+            [2]string{"Ledger receivable (current)", export.Money(report.Subledger.LedgerReceivable)},
+        )
+    }
+    return table
+}
+
+// IncomeStatementTable lays out income and expenses as sections with net income as the total
+func (s *LedgerService) IncomeStatementTable(report *models.IncomeStatement) *export.Table {
+    return &export.Table{
+        Title:       "Income Statement",
+        Subtitle:    [][2]string{{"Period", report.From + " to " + report.To}},
+        Columns:     statementColumns(),
+        GroupHeader: "Section",
+        Groups:      []export.Group{statementGroup(report.Income), statementGroup(report.Expenses)},
+        Totals:      []string{"", "Net income", export.Money(report.NetIncome)},
+        Footer:      "General ledger | " + report.From + " to " + report.To,
+        Printed:     s.printed(),
+    }
+}
+
+// BalanceSheetTable lays out assets, liabilities and equity as sections
+func (s *LedgerService) BalanceSheetTable(report *models.BalanceSheet) *export.Table {
+    return &export.Table{
+        Title:       "Balance Sheet",
+        Subtitle:    [][2]string{{"As of", report.AsOf}, {"Total assets", export.Money(report.Assets.Total)}},
+        Columns:     statementColumns(),
+        GroupHeader: "Section",
+        Groups:      []export.Group{statementGroup(report.Assets), statementGroup(report.Liabilities), statementGroup(report.Equity)},
+        Totals:      []string{"", "Total liabilities and equity", export.Money(report.TotalLiabilitiesAndEquity)},
+        Footer:      "General ledger | As of " + report.AsOf,
+        Printed:     s.printed(),
+    }
+}
+
+// MovementTable lays out the allowance for loan losses month by month
+func (s *ProvisioningService) MovementTable(report *models.ProvisionMovement) *export.Table {
+    table := &export.Table{
+        Title:    "Allowance for Loan Losses",
+        Subtitle: [][2]string{{"Period", report.From + " to " + report.To}},
+        Columns: []export.Column{
+            {Header: "Month", Width: 25, Align: "L"},
+            {Header: "Opening", Width: 30, Align: "R"},
+            {Header: "Charged", Width: 30, Align: "R"},
+            {Header: "Released", Width: 30, Align: "R"},
+            {Header: "Closing", Width: 30, Align: "R"},
+            {Header: "Required", Width: 30, Align: "R"},
+        },
+        Totals:  append([]string{""}, movementRow(report.Total)[1:]...),
+        Footer:  "General ledger | " + report.From + " to " + report.To,
+        Printed: s.ledgerService.printed(),
+    }
+    for _, line := range report.Months {
+        table.Rows = append(table.Rows, movementRow(line))
+    }
+    return table
+}
+
+func movementRow(line models.ProvisionMovementLine) []string {
+    required := "Not run"
+    switch {
+    case line.Run:
+        required = export.Money(line.Required)
+    case line.Month == "Total":
+        required = ""
+    }
+    return []string{line.Month, export.Money(line.Opening), export.Money(line.Charged), export.Money(line.Released), export.Money(line.Closing), required}
+}
+
+// printed is the print time in the business timezone
+func (s *LedgerService) printed() time.Time {
+    return time.Now().In(period.Today(s.periods).Location())
+}
+
+func statementColumns() []export.Column {
+    return []export.Column{
+        {Header: "Code", Width: 25, Align: "L"},
+        {Header: "Account", Width: 100, Align: "L"},
+        {Header: "Amount", Width: 40, Align: "R"},
+    }
+}
+
+func statementGroup(section models.FinancialStatementSection) export.Group {
+    group := export.Group{Title: section.Title, Subtotal: []string{"", "Total " + section.Title, export.Money(section.Total)}}
+    for _, line := range section.Lines {
+        group.Rows = append(group.Rows, []string{line.Code, line.Name, export.Money(line.Amount)})
+    }
+    return group
+}
@@ -0,0 +1,729 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "micro-lending-platform/backend/internal/repositories"
+    "sort"
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// chartOfAccounts is the set of accounts the platform posts to, added to the chart when missing
+var chartOfAccounts = []models.LedgerAccount{
+    {Code: models.AccountCashOnHand, Name: "Cash on Hand", Type: models.AccountTypeAsset},
+    {Code: models.AccountCashInBank, Name: "Cash in Bank and E-Wallets", Type: models.AccountTypeAsset},
+    {Code: models.AccountLoansReceivable, Name: "Loans Receivable", Type: models.AccountTypeAsset},
+    {Code: models.AccountUnearnedInterest, Name: "Unearned Interest Income", Type: models.AccountTypeAsset, Contra: true},
+    {Code: models.AccountChargesReceivable, Name: "Penalties and Fees Receivable", Type: models.AccountTypeAsset},
+    {Code: models.AccountLoanLossAllowance, Name: "Allowance for Loan Losses", Type: models.AccountTypeAsset, Contra: true},
+    {Code: models.AccountClientOverpayments, Name: "Client Overpayments", Type: models.AccountTypeLiability},
+    {Code: models.AccountCapital, Name: "Capital", Type: models.AccountTypeEquity},
+    {Code: models.AccountRetainedEarnings, Name: "Retained Earnings", Type: models.AccountTypeEquity},
+    {Code: models.AccountInterestIncome, Name: "Interest Income", Type: models.AccountTypeIncome},
+    {Code: models.AccountServiceFeeIncome, Name: "Service Fee Income", Type: models.AccountTypeIncome},
+    {Code: models.AccountPenaltyIncome, Name: "Penalty Income", Type: models.AccountTypeIncome},
+    {Code: models.AccountRecoveries, Name: "Recoveries on Written-off Loans", Type: models.AccountTypeIncome},
+    {Code: models.AccountLoanLosses, Name: "Loan Losses", Type: models.AccountTypeExpense},
+    {Code: models.AccountProvisionExpense, Name: "Provision for Loan Losses", Type: models.AccountTypeExpense},
+    {Code: models.AccountBalanceAdjustments, Name: "Loan Balance Adjustments", Type: models.AccountTypeExpense},
+}
+
+// receivableAccounts are the ledger accounts that together make up a loan's outstanding balance
+var receivableAccounts = []string{models.AccountLoansReceivable, models.AccountChargesReceivable}
+
+type LedgerService struct {
+    ledgerRepo      *repositories.LedgerRepository
+    loanRepo        *repositories.LoanRepository
+    paymentRepo     *repositories.PaymentRepository
+    chargeRepo      *repositories.LoanChargeRepository
+    calendarService *CalendarService
+    periods         period.Options
+}
+
+func NewLedgerService(ledgerRepo *repositories.LedgerRepository, loanRepo *repositories.LoanRepository, paymentRepo *repositories.PaymentRepository, chargeRepo *repositories.LoanChargeRepository, calendarService *CalendarService, periods period.Options) *LedgerService {
+    return &LedgerService{
+        ledgerRepo:      ledgerRepo,
+        loanRepo:        loanRepo,
+        paymentRepo:     paymentRepo,
+        chargeRepo:      chargeRepo,
+        calendarService: calendarService,
+        periods:         periods,
+    }
+}
+
+// PeriodOptions is the business timezone entries are dated in
+func (s *LedgerService) PeriodOptions() period.Options {
+    return s.periods
+}
+
+// EnsureChartOfAccounts adds the accounts the platform posts to when they are missing
+func (s *LedgerService) EnsureChartOfAccounts() error {
+    accounts := make([]models.LedgerAccount, len(chartOfAccounts))
+    for i, account := range chartOfAccounts {
+        account.System = true
+        accounts[i] = account
+    }
+    if err := s.ledgerRepo.EnsureAccounts(accounts); err != nil {
+        return fmt.Errorf("failed to set up chart of accounts: %w", err)
+    }
+    return nil
+}
+
+// GetAccounts returns the chart of accounts
+func (s *LedgerService) GetAccounts() ([]models.LedgerAccount, error) {
+    accounts, err := s.ledgerRepo.FindAccounts()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get accounts: %w", err)
+    }
+    return accounts, nil
+}
+
+// CreateAccount adds an account to the chart, e.g. an operating expense for manual entries
+func (s *LedgerService) CreateAccount(req *models.LedgerAccountRequest) (*models.LedgerAccount, error) {
+    code := strings.TrimSpace(req.Code)
+    accountType := ""
+    for _, t := range []string{models.AccountTypeAsset, models.AccountTypeLiability, models.AccountTypeEquity, models.AccountTypeIncome, models.AccountTypeExpense} {
+        if strings.EqualFold(req.Type, t) {
+            accountType = t
+        }
+    }
+    if accountType == "" {
+        return nil, fmt.Errorf("account type must be Asset, Liability, Equity, Income or Expense")
+    }
+
+    existing, err := s.ledgerRepo.FindAccountByCode(code)
+    if err != nil {
+        return nil, fmt.Errorf("failed to check account code: %w", err)
+    }
+    if existing != nil {
+        return nil, fmt.Errorf("account %s already exists", code)
+    }
+
+    account, err := s.ledgerRepo.CreateAccount(&models.LedgerAccount{
+        Code:        code,
+        Name:        strings.TrimSpace(req.Name),
+        Type:        accountType,
+        Contra:      req.Contra,
+        Description: req.Description,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to create account: %w", err)
+    }
+    return account, nil
+}
+
+// GetEntries lists journal entries with pagination
+func (s *LedgerService) GetEntries(filter *models.JournalEntryFilter, page, limit int) ([]models.JournalEntry, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 50
+    }
+    entries, total, err := s.ledgerRepo.FindEntries(filter, (page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get journal entries: %w", err)
+    }
+    return entries, total, nil
+}
+
+// GetEntry retrieves one journal entry with its lines
+func (s *LedgerService) GetEntry(id uint) (*models.JournalEntry, error) {
+    entry, err := s.ledgerRepo.FindEntryByID(id)
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, fmt.Errorf("journal entry not found")
+        }
+        return nil, fmt.Errorf("failed to get journal entry: %w", err)
+    }
+    return entry, nil
+}
+
+// CreateManualEntry posts a balanced entry keyed in by hand
+func (s *LedgerService) CreateManualEntry(req *models.JournalEntryRequest, username string) (*models.JournalEntry, error) {
+    entryDate, err := time.Parse(period.DateLayout, req.EntryDate)
+    if err != nil {
+        return nil, fmt.Errorf("invalid entry date, use YYYY-MM-DD")
+    }
+
+    if err := s.checkOpen(entryDate); err != nil {
+        return nil, err
+    }
+
+    var lines entryLines
+    for i, line := range req.Lines {
+        account, err := s.ledgerRepo.FindAccountByCode(line.AccountCode)
+        if err != nil {
+            return nil, fmt.Errorf("failed to check account %s: %w", line.AccountCode, err)
+        }
+        if account == nil {
+            return nil, fmt.Errorf("line %d: account %s does not exist", i+1, line.AccountCode)
+        }
+        if line.Debit < 0 || line.Credit < 0 || (line.Debit > 0) == (line.Credit > 0) {
+            return nil, fmt.Errorf("line %d: enter either a debit or a credit greater than zero", i+1)
+        }
+        lines = append(lines, models.JournalLine{
+            AccountCode: account.Code,
+            Debit:       roundCurrency(line.Debit),
+            Credit:      roundCurrency(line.Credit),
+            Memo:        line.Memo,
+        })
+    }
+    if err := lines.balanced(); err != nil {
+        return nil, err
+    }
+
+    entry, err := s.ledgerRepo.CreateEntry(&models.JournalEntry{
+        EntryDate:   entryDate,
+        Source:      models.JournalSourceManual,
+        Reference:   req.Reference,
+        Description: req.Description,
+        PostedBy:    username,
+        Lines:       lines,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to post journal entry: %w", err)
+    }
+    return entry, nil
+}
+
+// ReverseEntry posts the opposite of a manual entry. Entries posted from loans follow the loan
+// and are reversed by reversing the payment, charge or write-off they came from.
+func (s *LedgerService) ReverseEntry(id uint, req *models.JournalReversalRequest, username string) (*models.JournalEntry, error) {
+    entry, err := s.GetEntry(id)
+    if err != nil {
+        return nil, err
+    }
+    if entry.Source != models.JournalSourceManual {
+        return nil, fmt.Errorf("only manual entries can be reversed by hand")
+    }
+    if entry.ReversedByID != nil {
+        return nil, fmt.Errorf("journal entry is already reversed")
+    }
+
+    entryDate := period.Today(s.periods).FirstDay()
+    if req.EntryDate != "" {
+        if entryDate, err = time.Parse(period.DateLayout, req.EntryDate); err != nil {
+            return nil, fmt.Errorf("invalid entry date, use YYYY-MM-DD")
+        }
+    }
+    if err := s.checkOpen(entryDate); err != nil {
+        return nil, err
+    }
+
+    reversal, err := s.postReversal(s.ledgerRepo, entry, entryDate, nil, fmt.Sprintf("Reversal of entry #%d: %s", entry.ID, req.Reason), username)
+    if err != nil {
+        return nil, fmt.Errorf("failed to reverse journal entry: %w", err)
+    }
+    return reversal, nil
+}
+
+// RecordLoanChange brings a loan's postings up to date after it was written to. Failures are only
+// logged: the loan change itself has been saved, and the next sync posts whatever was missed.
+func (s *LedgerService) RecordLoanChange(loanID uint) {
+    if _, err := s.SyncLoan(loanID); err != nil {
+        fmt.Printf("Warning: Failed to post ledger entries for loan %d: %v\n", loanID, err)
+    }
+}
+
+// SyncAll posts every loan event the ledger has not seen yet, including loans booked before the
+// ledger existed, and returns how many entries it posted
+func (s *LedgerService) SyncAll() (*models.LedgerSyncResult, error) {
+    loanIDs, err := s.loanRepo.FindIDsWithDeleted()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    result := &models.LedgerSyncResult{LoansChecked: len(loanIDs)}
+    for _, loanID := range loanIDs {
+        posted, err := s.SyncLoan(loanID)
+        result.EntriesPosted += posted
+        if err != nil {
+            return result, err
+        }
+    }
+    return result, nil
+}
+
+// SyncLoan posts whatever a loan's release, charges, payments, reversals and write-off still need.
+// It can be run any number of times; each event is posted once. A difference it leaves between the
+// ledger and the loan's outstanding balance is not plugged, the tie-out reports it.
+func (s *LedgerService) SyncLoan(loanID uint) (int, error) {
+    loan, err := s.loanRepo.FindByIDWithDeleted(loanID)
+    if err != nil {
+        return 0, fmt.Errorf("failed to get loan %d: %w", loanID, err)
+    }
+    entries, err := s.ledgerRepo.FindEntriesByLoanID(loanID)
+    if err != nil {
+        return 0, fmt.Errorf("failed to get ledger entries for loan %d: %w", loanID, err)
+    }
+    book := newLoanBook(loan, entries)
+    if book.closedThrough, err = s.closedThrough(); err != nil {
+        return 0, err
+    }
+    loc := s.periods.Location
+
+    // A deleted loan takes everything it posted back out
+    if loan.DeletedAt.Valid {
+        deleted := period.LocalDate(loan.DeletedAt.Time, loc)
+        for _, entry := range book.active("") {
+            if err := s.reverseForLoan(book, entry, deleted, fmt.Sprintf("Loan %s deleted", loan.ControlNumber)); err != nil {
+                return book.posted, err
+            }
+        }
+        return book.posted, nil
+    }
+
+    schedule, err := s.calendarService.ScheduleForLoan(loan)
+    if err != nil {
+        return book.posted, err
+    }
+
+    if key := fmt.Sprintf("loan:%d:release", loan.ID); !book.has(key) {
+        if err := s.postForLoan(book, key, releaseEntry(loan, schedule, loc)); err != nil {
+            return book.posted, err
+        }
+    }
+
+    charges, err := s.chargeRepo.FindByLoanID(loan.ID)
+    if err != nil {
+        return book.posted, fmt.Errorf("failed to get charges: %w", err)
+    }
+    for _, charge := range charges {
+        if key := fmt.Sprintf("charge:%d", charge.ID); !book.has(key) {
+            if err := s.postForLoan(book, key, chargeEntry(loan, charge, loc)); err != nil {
+                return book.posted, err
+            }
+        }
+    }
+
+    payments, err := s.paymentRepo.FindByLoanIDsWithDeleted([]uint{loan.ID})
+    if err != nil {
+        return book.posted, fmt.Errorf("failed to get payments: %w", err)
+    }
+    for _, payment := range payments {
+        posted := book.forPayment(payment.ID)
+        if payment.DeletedAt.Valid {
+            removed := period.LocalDate(payment.DeletedAt.Time, loc)
+            for _, entry := range posted {
+                if err := s.reverseForLoan(book, entry, removed, fmt.Sprintf("Payment #%d reversed", payment.ID)); err != nil {
+                    return book.posted, err
+                }
+            }
+            continue
+        }
+
+        // A payment edited since it was posted is taken back out and posted again as it is now.
+        // One dated in a closed period was posted on a later open day and stays there.
+        paymentDate := period.LocalDate(payment.PaymentDate, loc)
+        postedOn := len(posted) == 1 && (posted[0].EntryDate.Equal(paymentDate) || (book.closed(paymentDate) && posted[0].EntryDate.After(paymentDate)))
+        if postedOn && roundCurrency(cashReceived(posted[0])) == roundCurrency(payment.AmountPaid) {
+            continue
+        }
+        for _, entry := range posted {
+            if err := s.reverseForLoan(book, entry, paymentDate, fmt.Sprintf("Payment #%d changed", payment.ID)); err != nil {
+                return book.posted, err
+            }
+        }
+        key := fmt.Sprintf("payment:%d:%d", payment.ID, book.count(models.JournalSourcePayment, &payment.ID)+1)
+        if err := s.postForLoan(book, key, book.paymentEntry(payment, schedule, paymentDate)); err != nil {
+            return book.posted, err
+        }
+    }
+
+    writeOffs := book.active(models.JournalSourceWriteOff)
+    if loan.Status == models.LoanStatusDefault && len(writeOffs) == 0 {
+        key := fmt.Sprintf("loan:%d:writeoff:%d", loan.ID, book.count(models.JournalSourceWriteOff, nil)+1)
+        if err := s.postForLoan(book, key, book.writeOffEntry(period.LocalDate(loan.UpdatedAt, loc))); err != nil {
+            return book.posted, err
+        }
+    }
+    if loan.Status != models.LoanStatusDefault {
+        for _, entry := range writeOffs {
+            if err := s.reverseForLoan(book, entry, period.LocalDate(loan.UpdatedAt, loc), fmt.Sprintf("Loan %s reinstated", loan.ControlNumber)); err != nil {
+                return book.posted, err
+            }
+        }
+    }
+    return book.posted, nil
+}
+
+// RecordBalanceEdit posts a hand edit of a loan's outstanding balance to balance adjustments, then
+// brings the loan's other postings up to date. Failures are only logged: the edit has been saved,
+// and the tie-out shows the loan apart until the difference is posted by hand.
+func (s *LedgerService) RecordBalanceEdit(loanID uint, difference float64) {
+    if err := s.postBalanceEdit(loanID, difference); err != nil {
+        fmt.Printf("Warning: Failed to post balance adjustment for loan %d: %v\n", loanID, err)
+    }
+    s.RecordLoanChange(loanID)
+}
+
+// postBalanceEdit posts one balance adjustment, dated today, for a loan still on the books
+func (s *LedgerService) postBalanceEdit(loanID uint, difference float64) error {
+    if difference = roundCurrency(difference); difference == 0 {
+        return nil
+    }
+    loan, err := s.loanRepo.FindByID(loanID)
+    if err != nil {
+        return fmt.Errorf("failed to get loan %d: %w", loanID, err)
+    }
+    entries, err := s.ledgerRepo.FindEntriesByLoanID(loanID)
+    if err != nil {
+        return fmt.Errorf("failed to get ledger entries for loan %d: %w", loanID, err)
+    }
+    book := newLoanBook(loan, entries)
+    if book.closedThrough, err = s.closedThrough(); err != nil {
+        return err
+    }
+    key := fmt.Sprintf("loan:%d:adjustment:%d", loan.ID, book.count(models.JournalSourceAdjustment, nil)+1)
+    return s.postForLoan(book, key, adjustmentEntry(loan, difference, period.Today(s.periods).FirstDay()))
+}
+
+// GetTrialBalance lists every account's balance at the end of a day, as posted so far; it writes nothing.
+// For today or later it also ties loans receivable out to the loans' current outstanding balances;
+// a past day has no tie-out, since the balances it had are not kept.
+func (s *LedgerService) GetTrialBalance(asOf period.Range) (*models.TrialBalance, error) {
+    before := asOf.LastDay().AddDate(0, 0, 1)
+    balances, err := s.ledgerRepo.SumByAccount(time.Time{}, before)
+    if err != nil {
+        return nil, fmt.Errorf("failed to total ledger: %w", err)
+    }
+    accounts, err := s.GetAccounts()
+    if err != nil {
+        return nil, err
+    }
+
+    report := &models.TrialBalance{
+        AsOf:        asOf.To(),
+        Lines:       trialBalanceLines(accounts, balances),
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }
+    for _, line := range report.Lines {
+        report.TotalDebit += line.Debit
+        report.TotalCredit += line.Credit
+    }
+    report.TotalDebit = roundCurrency(report.TotalDebit)
+    report.TotalCredit = roundCurrency(report.TotalCredit)
+    report.Balanced = report.TotalDebit == report.TotalCredit
+
+    if !asOf.LastDay().Before(period.Today(s.periods).FirstDay()) {
+        if report.Subledger, err = s.tieOut(); err != nil {
+            return nil, err
+        }
+    }
+    return report, nil
+}
+
+// tieOut compares each open loan's receivable in the ledger with its outstanding balance. Balances are
+// only kept as they are now, so it is the current position whatever day the trial balance is for.
+func (s *LedgerService) tieOut() (*models.SubledgerTieOut, error) {
+    loans, err := s.loanRepo.FindNotInStatuses([]models.LoanStatus{models.LoanStatusDefault})
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+    ledger, err := s.ledgerRepo.SumByLoan(receivableAccounts, time.Time{})
+    if err != nil {
+        return nil, fmt.Errorf("failed to total loan receivables: %w", err)
+    }
+
+    tieOut := &models.SubledgerTieOut{Mismatches: []models.LoanTieOut{}}
+    for _, loan := range loans {
+        tieOut.Loans++
+        tieOut.SubledgerOutstanding += loan.OutstandingBalance
+        tieOut.LedgerReceivable += ledger[loan.ID]
+        if difference := roundCurrency(loan.OutstandingBalance - ledger[loan.ID]); difference != 0 {
+            tieOut.Mismatches = append(tieOut.Mismatches, models.LoanTieOut{
+                LoanID:        loan.ID,
+                ControlNumber: loan.ControlNumber,
+                Ledger:        roundCurrency(ledger[loan.ID]),
+                Subledger:     loan.OutstandingBalance,
+                Difference:    difference,
+            })
+        }
+    }
+    tieOut.SubledgerOutstanding = roundCurrency(tieOut.SubledgerOutstanding)
+    tieOut.LedgerReceivable = roundCurrency(tieOut.LedgerReceivable)
+    tieOut.Difference = roundCurrency(tieOut.SubledgerOutstanding - tieOut.LedgerReceivable)
+    tieOut.TiedOut = tieOut.Difference == 0 && len(tieOut.Mismatches) == 0
+    return tieOut, nil
+}
+
+// netLoanBalances is each loan's receivable less its unearned interest in entries dated before a day
+func (s *LedgerService) netLoanBalances(before time.Time) (map[uint]float64, error) {
+    codes := append([]string{models.AccountUnearnedInterest}, receivableAccounts...)
+    balances, err := s.ledgerRepo.SumByLoan(codes, before)
+    if err != nil {
+        return nil, fmt.Errorf("failed to total loan balances: %w", err)
+    }
+    return balances, nil
+}
+
+// trialBalanceLines shows each account with postings on the side of its net balance, in code order
+func trialBalanceLines(accounts []models.LedgerAccount, balances []models.AccountBalance) []models.TrialBalanceLine {
+    byCode := make(map[string]models.LedgerAccount, len(accounts))
+    for _, account := range accounts {
+        byCode[account.Code] = account
+    }
+
+    lines := make([]models.TrialBalanceLine, 0, len(balances))
+    for _, balance := range balances {
+        account, ok := byCode[balance.AccountCode]
+        if !ok {
+            account = models.LedgerAccount{Code: balance.AccountCode, Name: "Unknown account"}
+        }
+        line := models.TrialBalanceLine{Code: account.Code, Name: account.Name, Type: account.Type}
+        if net := roundCurrency(balance.Debit - balance.Credit); net >= 0 {
+            line.Debit = net
+        } else {
+            line.Credit = -net
+        }
+        lines = append(lines, line)
+    }
+    sort.Slice(lines, func(i, j int) bool { return lines[i].Code < lines[j].Code })
+    return lines
+}
+
+// postForLoan posts an automatic entry for the book's loan under a source key
+func (s *LedgerService) postForLoan(book *loanBook, sourceKey string, entry *models.JournalEntry) error {
+    if len(entry.Lines) == 0 {
+        return nil
+    }
+    if err := entryLines(entry.Lines).balanced(); err != nil {
+        return fmt.Errorf("%s: %w", sourceKey, err)
+    }
+    entry.EntryDate = book.openDate(entry.EntryDate)
+    entry.SourceKey = &sourceKey
+    entry.LoanID = &book.loan.ID
+    entry.Reference = book.loan.ControlNumber
+    entry.PostedBy = "system"
+    for i := range entry.Lines {
+        entry.Lines[i].LoanID = &book.loan.ID
+    }
+
+    created, err := s.ledgerRepo.CreateEntry(entry)
+    if err != nil {
+        return fmt.Errorf("failed to post %s: %w", sourceKey, err)
+    }
+    book.add(*created)
+    return nil
+}
+
+// postSystemEntry posts an automatic entry that is not tied to one loan, e.g. a month-end provision
+func (s *LedgerService) postSystemEntry(sourceKey string, entry *models.JournalEntry, username string) (*models.JournalEntry, error) {
+    if err := entryLines(entry.Lines).balanced(); err != nil {
+        return nil, fmt.Errorf("%s: %w", sourceKey, err)
+    }
+    if err := s.checkOpen(entry.EntryDate); err != nil {
+        return nil, err
+    }
+    entry.SourceKey = &sourceKey
+    entry.PostedBy = username
+
+    created, err := s.ledgerRepo.CreateEntry(entry)
+    if err != nil {
+        return nil, fmt.Errorf("failed to post %s: %w", sourceKey, err)
+    }
+    return created, nil
+}
+
+// accountBalance totals the debits and credits posted to one account in entries dated from a day
+// (the beginning when zero) up to but not including another
+func (s *LedgerService) accountBalance(code string, from, before time.Time) (models.AccountBalance, error) {
+    balances, err := s.ledgerRepo.SumByAccount(from, before)
+    if err != nil {
+        return models.AccountBalance{}, fmt.Errorf("failed to total ledger: %w", err)
+    }
+    for _, balance := range balances {
+        if balance.AccountCode == code {
+            return balance, nil
+        }
+    }
+    return models.AccountBalance{AccountCode: code}, nil
+}
+
+// reverseForLoan reverses one of the book's entries and keeps the book's balances in step
+func (s *LedgerService) reverseForLoan(book *loanBook, entry models.JournalEntry, entryDate time.Time, reason string) error {
+    key := fmt.Sprintf("reversal:%d", entry.ID)
+    reversal, err := s.postReversal(s.ledgerRepo, &entry, book.openDate(entryDate), &key, fmt.Sprintf("Reversal of entry #%d: %s", entry.ID, reason), "system")
+    if err != nil {
+        return err
+    }
+    book.reverse(entry.ID, *reversal)
+    return nil
+}
+
+// postReversal posts the opposite of an entry and marks the entry reversed, in one transaction
+func (s *LedgerService) postReversal(repo *repositories.LedgerRepository, entry *models.JournalEntry, entryDate time.Time, sourceKey *string, description, username string) (*models.JournalEntry, error) {
+    reversal := &models.JournalEntry{
+        EntryDate:   entryDate,
+        Source:      models.JournalSourceReversal,
+        SourceKey:   sourceKey,
+        Reference:   entry.Reference,
+        Description: description,
+        LoanID:      entry.LoanID,
+        PaymentID:   entry.PaymentID,
+        ReversesID:  &entry.ID,
+        PostedBy:    username,
+    }
+    for _, line := range entry.Lines {
+        reversal.Lines = append(reversal.Lines, models.JournalLine{
+            AccountCode: line.AccountCode,
+            LoanID:      line.LoanID,
+            Debit:       line.Credit,
+            Credit:      line.Debit,
+            Memo:        line.Memo,
+        })
+    }
+
+    err := repo.Transaction(func(tx *repositories.LedgerRepository) error {
+        if _, err := tx.CreateEntry(reversal); err != nil {
+            return err
+        }
+        return tx.MarkReversed(entry.ID, reversal.ID)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return reversal, nil
+}
+
+// releaseEntry books a loan at its full amount due against the cash released, with the interest
+// held as unearned and the deducted or financed charges taken as service fee income
+func releaseEntry(loan *models.Loan, schedule []models.Installment, loc *time.Location) *models.JournalEntry {
+    interest := 0.0
+    for _, inst := range schedule {
+        interest += inst.Interest
+    }
+    interest = math.Min(roundCurrency(interest), math.Max(roundCurrency(loan.TotalAmount-loan.AmountRelease), 0))
+    fees := roundCurrency(loan.TotalAmount - loan.AmountRelease - interest)
+
+    var lines entryLines
+    lines.debit(models.AccountLoansReceivable, loan.TotalAmount, "Total amount due")
+    lines.credit(models.AccountCashOnHand, loan.AmountRelease, "Net proceeds released")
+    lines.credit(models.AccountUnearnedInterest, interest, "Interest for the term")
+    lines.credit(models.AccountServiceFeeIncome, fees, "Deducted and financed charges")
+    return &models.JournalEntry{
+        EntryDate:   period.LocalDate(loan.DateOfRelease, loc),
+        Source:      models.JournalSourceRelease,
+        Description: fmt.Sprintf("Release of loan %s", loan.ControlNumber),
+        Lines:       lines,
+    }
+}
+
+// chargeEntry books a penalty or fee added to a loan as income receivable
+func chargeEntry(loan *models.Loan, charge models.LoanCharge, loc *time.Location) *models.JournalEntry {
+    income := models.AccountPenaltyIncome
+    if charge.ChargeType == models.ChargeTypeFee {
+        income = models.AccountServiceFeeIncome
+    }
+    var lines entryLines
+    lines.debit(models.AccountChargesReceivable, charge.Amount, string(charge.ChargeType))
+    lines.credit(income, charge.Amount, charge.Description)
+    return &models.JournalEntry{
+        EntryDate:   period.LocalDate(charge.ChargeDate, loc),
+        Source:      models.JournalSourceCharge,
+        Description: fmt.Sprintf("%s on loan %s", charge.ChargeType, loan.ControlNumber),
+        Lines:       lines,
+    }
+}
+
+// adjustmentEntry brings loans receivable to the loan's outstanding balance after a hand edit
+func adjustmentEntry(loan *models.Loan, difference float64, entryDate time.Time) *models.JournalEntry {
+    var lines entryLines
+    lines.debit(models.AccountLoansReceivable, difference, "Outstanding balance adjusted")
+    lines.credit(models.AccountBalanceAdjustments, difference, "")
+    return &models.JournalEntry{
+        EntryDate:   entryDate,
+        Source:      models.JournalSourceAdjustment,
+        Description: fmt.Sprintf("Balance adjustment on loan %s", loan.ControlNumber),
+        Lines:       lines,
+    }
+}
+
+// cashAccount is where money received by a payment method lands
+func cashAccount(method string) string {
+    if method == "" || strings.EqualFold(method, "Cash") {
+        return models.AccountCashOnHand
+    }
+    return models.AccountCashInBank
+}
+
+// cashReceived totals the cash debited by a payment entry
+func cashReceived(entry models.JournalEntry) float64 {
+    total := 0.0
+    for _, line := range entry.Lines {
+        if line.AccountCode == models.AccountCashOnHand || line.AccountCode == models.AccountCashInBank {
+            total += line.Debit
+        }
+    }
+    return total
+}
+
+// scheduleSplit divides an amount paid on the schedule, starting after what earlier payments covered,
+// into the principal, interest and financed charges of the installments it settles. Anything beyond
+// the schedule counts as principal.
+func scheduleSplit(schedule []models.Installment, paidBefore, amount float64) (principal, interest, charges float64) {
+    start, end := paidBefore, paidBefore+amount
+    covered := 0.0
+    for _, inst := range schedule {
+        lo, hi := covered, covered+inst.AmountDue
+        covered = hi
+        overlap := min(end, hi) - max(start, lo)
+        if overlap <= 0 || inst.AmountDue <= 0 {
+            continue
+        }
+        interest += overlap * inst.Interest / inst.AmountDue
+        charges += overlap * inst.Charges / inst.AmountDue
+    }
+    interest = roundCurrency(interest)
+    charges = roundCurrency(charges)
+    return roundCurrency(amount - interest - charges), interest, charges
+}
+
+// entryLines collects an entry's lines, skipping zero amounts and turning negative amounts
+// into the opposite side
+type entryLines []models.JournalLine
+
+func (l *entryLines) debit(account string, amount float64, memo string) {
+    l.add(account, amount, 0, memo)
+}
+
+func (l *entryLines) credit(account string, amount float64, memo string) {
+    l.add(account, 0, amount, memo)
+}
+
+func (l *entryLines) add(account string, debit, credit float64, memo string) {
+    net := roundCurrency(debit - credit)
+    if net == 0 {
+        return
+    }
+    line := models.JournalLine{AccountCode: account, Memo: memo}
+    if net > 0 {
+        line.Debit = net
+    } else {
+        line.Credit = -net
+    }
+    *l = append(*l, line)
+}
+
+// balanced checks an entry has lines and that its debits equal its credits
+func (l entryLines) balanced() error {
+    if len(l) < 2 {
+        return fmt.Errorf("a journal entry needs at least two lines")
+    }
+    debits, credits := 0.0, 0.0
+    for _, line := range l {
+        debits += line.Debit
+        credits += line.Credit
+    }
+    if roundCurrency(debits) != roundCurrency(credits) {
+        return fmt.Errorf("debits %.2f do not equal credits %.2f", debits, credits)
+    }
+    return nil
+}
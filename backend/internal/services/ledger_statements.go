@@ -0,0 +1,243 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// closedThrough is the last day of the latest closed period, zero when none is closed
+func (s *LedgerService) closedThrough() (time.Time, error) {
+    latest, err := s.ledgerRepo.FindLatestPeriodClose()
+    if err != nil {
+        return time.Time{}, fmt.Errorf("failed to get closed periods: %w", err)
+    }
+    if latest == nil {
+        return time.Time{}, nil
+    }
+    return latest.PeriodEnd, nil
+}
+
+// monthsEndedSince lists the YYYY-MM months after a given one that have ended and are still open,
+// oldest first. With no month given it is only the month just ended, so month-end jobs catching up
+// on missed runs do not go back through history.
+func (s *LedgerService) monthsEndedSince(latest string) ([]string, error) {
+    today := period.Today(s.periods).FirstDay()
+    lastEnded := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+    next := lastEnded
+    if latest != "" {
+        month, err := time.Parse("2006-01", latest)
+        if err != nil {
+            return nil, fmt.Errorf("invalid month %q", latest)
+        }
+        next = month.AddDate(0, 1, 0)
+    }
+    through, err := s.closedThrough()
+    if err != nil {
+        return nil, err
+    }
+
+    var months []string
+    for ; !next.After(lastEnded); next = next.AddDate(0, 1, 0) {
+        if !through.IsZero() && !next.AddDate(0, 1, -1).After(through) {
+            continue
+        }
+        months = append(months, next.Format("2006-01"))
+    }
+    return months, nil
+}
+
+// checkOpen refuses a day that falls in a closed period
+func (s *LedgerService) checkOpen(day time.Time) error {
+    through, err := s.closedThrough()
+    if err != nil {
+        return err
+    }
+    if !through.IsZero() && !day.After(through) {
+        return fmt.Errorf("the books are closed through %s", through.Format(period.DateLayout))
+    }
+    return nil
+}
+
+// GetPeriodCloses lists every closed period, including ones since reopened
+func (s *LedgerService) GetPeriodCloses() ([]models.LedgerPeriodClose, error) {
+    closes, err := s.ledgerRepo.FindPeriodCloses()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get closed periods: %w", err)
+    }
+    return closes, nil
+}
+
+// ClosePeriod locks the books through the end of a month that has ended. Everything the loans still
+// need is posted first, and the month cannot be closed unless the ledger balances through its end.
+func (s *LedgerService) ClosePeriod(req *models.LedgerPeriodCloseRequest, username string) (*models.LedgerPeriodClose, error) {
+    month, err := time.Parse("2006-01", req.Month)
+    if err != nil {
+        return nil, fmt.Errorf("invalid month, use YYYY-MM")
+    }
+    r := period.Between(month, month.AddDate(0, 1, -1), s.periods)
+    if !r.LastDay().Before(period.Today(s.periods).FirstDay()) {
+        return nil, fmt.Errorf("%s has not ended yet", month.Format("January 2006"))
+    }
+    through, err := s.closedThrough()
+    if err != nil {
+        return nil, err
+    }
+    if !through.IsZero() && !r.LastDay().After(through) {
+        return nil, fmt.Errorf("the books are already closed through %s", through.Format(period.DateLayout))
+    }
+
+    if _, err := s.SyncAll(); err != nil {
+        return nil, err
+    }
+    accounts, balances, err := s.accountBalances(time.Time{}, r.LastDay().AddDate(0, 0, 1))
+    if err != nil {
+        return nil, err
+    }
+    debits, credits := 0.0, 0.0
+    for _, balance := range balances {
+        debits += balance.Debit
+        credits += balance.Credit
+    }
+    if roundCurrency(debits) != roundCurrency(credits) {
+        return nil, fmt.Errorf("the ledger does not balance through %s: debits %.2f, credits %.2f", r.To(), debits, credits)
+    }
+    _, monthBalances, err := s.accountBalances(r.FirstDay(), r.LastDay().AddDate(0, 0, 1))
+    if err != nil {
+        return nil, err
+    }
+    netIncome := statementSection("", accounts, monthBalances, models.AccountTypeIncome).Total -
+        statementSection("", accounts, monthBalances, models.AccountTypeExpense).Total
+
+    periodClose, err := s.ledgerRepo.CreatePeriodClose(&models.LedgerPeriodClose{
+        PeriodStart: r.FirstDay(),
+        PeriodEnd:   r.LastDay(),
+        NetIncome:   roundCurrency(netIncome),
+        ClosedBy:    username,
+        Notes:       req.Notes,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to close period: %w", err)
+    }
+    return periodClose, nil
+}
+
+// ReopenPeriod reopens the most recently closed period so entries can be posted to it again
+func (s *LedgerService) ReopenPeriod(id uint, req *models.LedgerPeriodReopenRequest, username string) (*models.LedgerPeriodClose, error) {
+    periodClose, err := s.ledgerRepo.FindPeriodCloseByID(id)
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, fmt.Errorf("closed period not found")
+        }
+        return nil, fmt.Errorf("failed to get closed period: %w", err)
+    }
+    if periodClose.ReopenedAt != nil {
+        return nil, fmt.Errorf("period is already reopened")
+    }
+    latest, err := s.ledgerRepo.FindLatestPeriodClose()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get closed periods: %w", err)
+    }
+    if latest == nil || latest.ID != periodClose.ID {
+        return nil, fmt.Errorf("only the most recently closed period can be reopened")
+    }
+
+    now := time.Now()
+    periodClose.ReopenedAt = &now
+    periodClose.ReopenedBy = username
+    periodClose.ReopenReason = req.Reason
+    if err := s.ledgerRepo.UpdatePeriodClose(periodClose); err != nil {
+        return nil, fmt.Errorf("failed to reopen period: %w", err)
+    }
+    return periodClose, nil
+}
+
+// GetIncomeStatement totals income and expenses posted over a range of days. It only reads the ledger;
+// events not yet synced show up after the nightly sync or POST /accounting/sync.
+func (s *LedgerService) GetIncomeStatement(r period.Range) (*models.IncomeStatement, error) {
+    accounts, balances, err := s.accountBalances(r.FirstDay(), r.LastDay().AddDate(0, 0, 1))
+    if err != nil {
+        return nil, err
+    }
+
+    report := &models.IncomeStatement{
+        From:        r.From(),
+        To:          r.To(),
+        Income:      statementSection("Income", accounts, balances, models.AccountTypeIncome),
+        Expenses:    statementSection("Expenses", accounts, balances, models.AccountTypeExpense),
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }
+    report.NetIncome = roundCurrency(report.Income.Total - report.Expenses.Total)
+    return report, nil
+}
+
+// GetBalanceSheet lists assets, liabilities and equity at the end of a day, as posted so far
+func (s *LedgerService) GetBalanceSheet(asOf period.Range) (*models.BalanceSheet, error) {
+    accounts, balances, err := s.accountBalances(time.Time{}, asOf.LastDay().AddDate(0, 0, 1))
+    if err != nil {
+        return nil, err
+    }
+
+    report := &models.BalanceSheet{
+        AsOf:        asOf.To(),
+        Assets:      statementSection("Assets", accounts, balances, models.AccountTypeAsset),
+        Liabilities: statementSection("Liabilities", accounts, balances, models.AccountTypeLiability),
+        Equity:      statementSection("Equity", accounts, balances, models.AccountTypeEquity),
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }
+    income := statementSection("", accounts, balances, models.AccountTypeIncome)
+    expenses := statementSection("", accounts, balances, models.AccountTypeExpense)
+    earned := roundCurrency(income.Total - expenses.Total)
+    report.Equity.Lines = append(report.Equity.Lines, models.FinancialStatementLine{Name: "Net income to date", Amount: earned})
+    report.Equity.Total = roundCurrency(report.Equity.Total + earned)
+
+    report.TotalLiabilitiesAndEquity = roundCurrency(report.Liabilities.Total + report.Equity.Total)
+    report.Balanced = report.Assets.Total == report.TotalLiabilitiesAndEquity
+    return report, nil
+}
+
+// accountBalances loads the chart of accounts and what was posted to each account between two days
+func (s *LedgerService) accountBalances(from, before time.Time) (map[string]models.LedgerAccount, []models.AccountBalance, error) {
+    accounts, err := s.GetAccounts()
+    if err != nil {
+        return nil, nil, err
+    }
+    balances, err := s.ledgerRepo.SumByAccount(from, before)
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to total ledger: %w", err)
+    }
+    byCode := make(map[string]models.LedgerAccount, len(accounts))
+    for _, account := range accounts {
+        byCode[account.Code] = account
+    }
+    return byCode, balances, nil
+}
+
+// statementSection lists the accounts of one type with their balances on the type's normal side,
+// so contra accounts show as deductions, in code order
+func statementSection(title string, accounts map[string]models.LedgerAccount, balances []models.AccountBalance, accountType string) models.FinancialStatementSection {
+    section := models.FinancialStatementSection{Title: title, Lines: []models.FinancialStatementLine{}}
+    for _, balance := range balances {
+        account, ok := accounts[balance.AccountCode]
+        if !ok || account.Type != accountType {
+            continue
+        }
+        amount := roundCurrency(balance.Debit - balance.Credit)
+        if accountType != models.AccountTypeAsset && accountType != models.AccountTypeExpense {
+            amount = -amount
+        }
+        if amount == 0 {
+            continue
+        }
+        section.Lines = append(section.Lines, models.FinancialStatementLine{Code: account.Code, Name: account.Name, Amount: amount})
+        section.Total += amount
+    }
+    section.Total = roundCurrency(section.Total)
+    sort.Slice(section.Lines, func(i, j int) bool { return section.Lines[i].Code < section.Lines[j].Code })
+    return section
+}
@@ -0,0 +1,439 @@
+package services
+
+import (
+    "fmt"
+    "math"
+    "micro-lending-platform/backend/internal/models"
+    "strings"
+    "time"
+)
+
+// Standard pricing used when a quote does not specify its own
+const (
+    DefaultMonthlyInterestRate = 0.035
+    DefaultServiceFee          = 1200.0
+)
+
+// CalculateLoan computes amounts, schedule and effective rate for a set of loan terms.
+// It is the single source of loan figures for both quotes and created loans.
+// Due dates that land on a holiday in calendar are moved to the next working day.
+func CalculateLoan(req *models.LoanSimulationRequest, calendar *HolidayCalendar) (*models.LoanSimulation, error) {
+    if req.Principal <= 0 {
+        return nil, fmt.Errorf("principal must be greater than zero")
+    }
+    if req.Terms <= 0 {
+        return nil, fmt.Errorf("terms must be greater than zero")
+    }
+
+    rate := DefaultMonthlyInterestRate
+    if req.InterestRate != nil {
+        rate = *req.InterestRate
+    }
+    if rate < 0 {
+        return nil, fmt.Errorf("interest rate cannot be negative")
+    }
+
+    method, err := normalizeInterestMethod(req.InterestMethod)
+    if err != nil {
+        return nil, err
+    }
+    mode, err := normalizeLoanMode(req.Mode)
+    if err != nil {
+        return nil, err
+    }
+
+    deductionType := "Without"
+    if strings.EqualFold(req.DeductionType, "With") {
+        deductionType = "With"
+    }
+
+    // nil means "use the standard fee", an explicit empty list means no deductions
+    deductions := req.Deductions
+    if deductions == nil {
+        deductions = []models.LoanDeduction{{Name: "Service Fee", Amount: DefaultServiceFee}}
+    }
+    totalDeductions := 0.0
+    for _, d := range deductions {
+        if d.Amount < 0 {
+            return nil, fmt.Errorf("deduction %q cannot be negative", d.Name)
+        }
+        totalDeductions += d.Amount
+    }
+    totalDeductions = roundCurrency(totalDeductions)
+
+    // "With" deductions come out of the release, "Without" are added to the amount payable
+    financedCharges := 0.0
+    netRelease := req.Principal
+    if deductionType == "With" {
+        netRelease = roundCurrency(req.Principal - totalDeductions)
+    } else {
+        financedCharges = totalDeductions
+    }
+    if netRelease <= 0 {
+        return nil, fmt.Errorf("deductions exceed the principal")
+    }
+
+    releaseDate := time.Now()
+    if req.DateOfRelease != "" {
+        releaseDate, err = time.Parse("2006-01-02", req.DateOfRelease)
+        if err != nil {
+            return nil, fmt.Errorf("invalid date of release: %w", err)
+        }
+    }
+
+    count := installmentCount(mode, req.Terms)
+    dueDates := installmentDueDates(releaseDate, mode, req.DueDate, count, calendar)
+
+    var schedule []models.Installment
+    if method == models.InterestMethodDiminishing {
+        schedule = diminishingSchedule(req.Principal, rate/float64(periodsPerMonth(mode)), financedCharges, dueDates)
+    } else {
+        totalInterest := roundCurrency(req.Principal * rate * float64(req.Terms))
+        totalAmount := req.Principal + totalInterest + financedCharges
+        // Amortizations are to the centavo; the last installment takes only what rounding left over
+        ammortization := roundCurrency(totalAmount / float64(count))
+        schedule = flatSchedule(req.Principal, totalInterest, financedCharges, ammortization, dueDates)
+    }
+
+    sim := &models.LoanSimulation{
+        Principal:            req.Principal,
+        InterestRate:         rate,
+        InterestMethod:       method,
+        Mode:                 mode,
+        Terms:                req.Terms,
+        NumberOfInstallments: count,
+        DeductionType:        deductionType,
+        Deductions:           deductions,
+        TotalDeductions:      totalDeductions,
+        NetRelease:           netRelease,
+        Schedule:             schedule,
+    }
+    for _, inst := range schedule {
+        sim.TotalInterest += inst.Interest
+        sim.TotalAmount += inst.AmountDue
+    }
+    sim.TotalInterest = roundCurrency(sim.TotalInterest)
+    sim.TotalAmount = roundCurrency(sim.TotalAmount)
+    if len(schedule) > 0 {
+        sim.Ammortization = schedule[0].AmountDue
+    }
+
+    periodic := periodicEffectiveRate(netRelease, schedule)
+    perMonth := float64(periodsPerMonth(mode))
+    sim.EffectiveInterestRate = roundRate(math.Pow(1+periodic, perMonth) - 1)
+    sim.EffectiveAnnualRate = roundRate(math.Pow(1+periodic, perMonth*12) - 1)
+
+    return sim, nil
+}
+
+// applyLoanCalculation fills a new loan's amounts from the calculator when the request quotes a principal
+func applyLoanCalculation(loan *models.Loan, req *models.LoanCreate, calendar *HolidayCalendar) error {
+    if req.Principal <= 0 {
+        return nil
+    }
+
+    sim, err := CalculateLoan(&models.LoanSimulationRequest{
+        Principal:      req.Principal,
+        InterestRate:   req.InterestRate,
+        InterestMethod: req.InterestMethod,
+        Mode:           req.Mode,
+        Terms:          req.Terms,
+        DeductionType:  req.Deductions,
+        Deductions:     req.DeductionItems,
+        DateOfRelease:  req.DateOfRelease,
+        DueDate:        req.DueDate,
+    }, calendar)
+    if err != nil {
+        return err
+    }
+
+    loan.Principal = sim.Principal
+    loan.InterestRate = sim.InterestRate
+    loan.InterestMethod = sim.InterestMethod
+    loan.Mode = sim.Mode
+    loan.Deductions = sim.DeductionType
+    loan.TotalAmount = sim.TotalAmount
+    loan.Ammortization = sim.Ammortization
+    loan.OutstandingBalance = sim.TotalAmount
+    loan.AmountRelease = sim.NetRelease
+    loan.PaymentPeriodWeeks = sim.NumberOfInstallments
+    return nil
+}
+
+// ScheduleForLoan rebuilds the installment schedule of an existing loan from its stored figures
+func ScheduleForLoan(loan *models.Loan, calendar *HolidayCalendar) []models.Installment {
+    mode, err := normalizeLoanMode(loan.Mode)
+    if err != nil {
+        mode = models.LoanModeWeekly
+    }
+
+    count := loan.PaymentPeriodWeeks
+    if count <= 0 {
+        count = installmentCount(mode, loan.Terms)
+    }
+    if count <= 0 {
+        return nil
+    }
+
+    releaseDate := loan.DateOfRelease
+    if releaseDate.IsZero() {
+        releaseDate = loan.CreatedAt
+    }
+    dueDates := installmentDueDates(releaseDate, mode, loan.DueDate, count, calendar)
+
+    principal := loan.Principal
+    if principal <= 0 {
+        principal = loan.AmountRelease
+    }
+
+    if loan.InterestMethod == models.InterestMethodDiminishing && loan.Principal > 0 {
+        financed := loan.TotalAmount - diminishingTotal(loan.Principal, loan.InterestRate/float64(periodsPerMonth(mode)), count)
+        return diminishingSchedule(loan.Principal, loan.InterestRate/float64(periodsPerMonth(mode)), math.Max(financed, 0), dueDates)
+    }
+
+    interest := 0.0
+    charges := 0.0
+    if loan.Principal > 0 && loan.InterestRate > 0 {
+        interest = roundCurrency(loan.Principal * loan.InterestRate * float64(loan.Terms))
+        charges = math.Max(roundCurrency(loan.TotalAmount-loan.Principal-interest), 0)
+    } else {
+        interest = math.Max(roundCurrency(loan.TotalAmount-principal), 0)
+    }
+
+    ammortization := loan.Ammortization
+    if ammortization <= 0 {
+        ammortization = roundCurrency(loan.TotalAmount / float64(count))
+    }
+    return flatSchedule(principal, interest, charges, ammortization, dueDates)
+}
+
+// effectiveRatesForLoan returns the effective monthly and annual rates of a loan given its schedule
+func effectiveRatesForLoan(loan *models.Loan, schedule []models.Installment) (float64, float64) {
+    mode, err := normalizeLoanMode(loan.Mode)
+    if err != nil {
+        mode = models.LoanModeWeekly
+    }
+    periodic := periodicEffectiveRate(loan.AmountRelease, schedule)
+    perMonth := float64(periodsPerMonth(mode))
+    return roundRate(math.Pow(1+periodic, perMonth) - 1), roundRate(math.Pow(1+periodic, perMonth*12) - 1)
+}
+
+// flatSchedule spreads add-on interest and financed charges evenly over the installments. The last
+// installment is whatever the others leave of the total, so the amortization should be rounded to the
+// centavo: a coarser one piles its rounding up on that installment.
+func flatSchedule(principal, interest, charges, ammortization float64, dueDates []time.Time) []models.Installment {
+    count := len(dueDates)
+    total := roundCurrency(principal + interest + charges)
+    schedule := make([]models.Installment, 0, count)
+
+    remaining := total
+    paidPrincipal, paidInterest := 0.0, 0.0
+    for i, due := range dueDates {
+        amount := ammortization
+        if i == count-1 || amount > remaining {
+            amount = remaining
+        }
+
+        inst := models.Installment{Number: i + 1, DueDate: due, AmountDue: roundCurrency(amount)}
+        if i == count-1 {
+            inst.Principal = roundCurrency(principal - paidPrincipal)
+            inst.Interest = roundCurrency(interest - paidInterest)
+        } else if total > 0 {
+            inst.Principal = roundCurrency(amount * principal / total)
+            inst.Interest = roundCurrency(amount * interest / total)
+        }
+        inst.Charges = roundCurrency(inst.AmountDue - inst.Principal - inst.Interest)
+
+        paidPrincipal += inst.Principal
+        paidInterest += inst.Interest
+        remaining = roundCurrency(remaining - inst.AmountDue)
+        inst.RemainingBalance = remaining
+
+        schedule = append(schedule, inst)
+    }
+    return schedule
+}
+
+// diminishingSchedule builds a level-payment amortization on the declining principal
+func diminishingSchedule(principal, periodicRate, charges float64, dueDates []time.Time) []models.Installment {
+    count := len(dueDates)
+    payment := annuityPayment(principal, periodicRate, count)
+    chargePer := roundCurrency(charges / float64(count))
+
+    schedule := make([]models.Installment, 0, count)
+    balance := principal
+    chargesLeft := charges
+    total := 0.0
+    for i, due := range dueDates {
+        interest := roundCurrency(balance * periodicRate)
+        principalPart := roundCurrency(payment - interest)
+        charge := chargePer
+        if i == count-1 {
+            principalPart = roundCurrency(balance)
+            charge = roundCurrency(chargesLeft)
+        }
+        balance = roundCurrency(balance - principalPart)
+        chargesLeft -= charge
+
+        inst := models.Installment{
+            Number:    i + 1,
+            DueDate:   due,
+            Principal: principalPart,
+            Interest:  interest,
+            Charges:   charge,
+            AmountDue: roundCurrency(principalPart + interest + charge),
+        }
+        total += inst.AmountDue
+        schedule = append(schedule, inst)
+    }
+
+    remaining := roundCurrency(total)
+    for i := range schedule {
+        remaining = roundCurrency(remaining - schedule[i].AmountDue)
+        schedule[i].RemainingBalance = remaining
+    }
+    return schedule
+}
+
+// diminishingTotal returns principal plus interest payable under the diminishing method
+func diminishingTotal(principal, periodicRate float64, count int) float64 {
+    total := 0.0
+    for _, inst := range diminishingSchedule(principal, periodicRate, 0, make([]time.Time, count)) {
+        total += inst.AmountDue
+    }
+    return roundCurrency(total)
+}
+
+// annuityPayment is the level payment that retires principal over count periods
+func annuityPayment(principal, periodicRate float64, count int) float64 {
+    if periodicRate == 0 {
+        return roundCurrency(principal / float64(count))
+    }
+    return roundCurrency(principal * periodicRate / (1 - math.Pow(1+periodicRate, -float64(count))))
+}
+
+// periodicEffectiveRate solves for the per-installment rate that discounts the schedule to the net release
+func periodicEffectiveRate(netRelease float64, schedule []models.Installment) float64 {
+    presentValue := func(rate float64) float64 {
+        pv := 0.0
+        for i, inst := range schedule {
+            pv += inst.AmountDue / math.Pow(1+rate, float64(i+1))
+        }
+        return pv
+    }
+
+    if len(schedule) == 0 || presentValue(0) <= netRelease {
+        return 0
+    }
+
+    low, high := 0.0, 1.0
+    for presentValue(high) > netRelease && high < 1e6 {
+        high *= 2
+    }
+    for i := 0; i < 200; i++ {
+        mid := (low + high) / 2
+        if presentValue(mid) > netRelease {
+            low = mid
+        } else {
+            high = mid
+        }
+    }
+    return (low + high) / 2
+}
+
+// installmentDueDates lays out due dates from the release date according to the repayment mode.
+// A date falling on a holiday rolls forward to the next working day without shifting later dates.
+func installmentDueDates(releaseDate time.Time, mode, dueWeekday string, count int, calendar *HolidayCalendar) []time.Time {
+    start := time.Date(releaseDate.Year(), releaseDate.Month(), releaseDate.Day(), 0, 0, 0, 0, releaseDate.Location())
+
+    // Weekly collections fall on the agreed weekday, starting no earlier than a week after release
+    firstWeekly := start.AddDate(0, 0, 7)
+    if mode == models.LoanModeWeekly {
+        if weekday, ok := parseWeekday(dueWeekday); ok {
+            for firstWeekly.Weekday() != weekday {
+                firstWeekly = firstWeekly.AddDate(0, 0, 1)
+            }
+        }
+    }
+
+    dates := make([]time.Time, count)
+    for i := 0; i < count; i++ {
+        switch mode {
+        case models.LoanModeDaily:
+            dates[i] = start.AddDate(0, 0, i+1)
+        case models.LoanModeSemiMonthly:
+            dates[i] = start.AddDate(0, 0, 15*(i+1))
+        case models.LoanModeMonthly:
+            dates[i] = start.AddDate(0, i+1, 0)
+        default:
+            dates[i] = firstWeekly.AddDate(0, 0, 7*i)
+        }
+        dates[i] = calendar.NextWorkingDay(dates[i])
+    }
+    return dates
+}
+
+// installmentCount returns the number of installments for a term in months
+func installmentCount(mode string, terms int) int {
+    return terms * periodsPerMonth(mode)
+}
+
+// periodsPerMonth matches the collection conventions used on the loan form (4 weeks per month)
+func periodsPerMonth(mode string) int {
+    switch mode {
+    case models.LoanModeDaily:
+        return 30
+    case models.LoanModeSemiMonthly:
+        return 2
+    case models.LoanModeMonthly:
+        return 1
+    default:
+        return 4
+    }
+}
+
+// normalizeLoanMode maps user input onto one of the supported repayment modes
+func normalizeLoanMode(mode string) (string, error) {
+    switch strings.ToLower(strings.TrimSpace(mode)) {
+    case "", "weekly":
+        return models.LoanModeWeekly, nil
+    case "daily":
+        return models.LoanModeDaily, nil
+    case "semi-monthly", "semimonthly", "semi_monthly":
+        return models.LoanModeSemiMonthly, nil
+    case "monthly":
+        return models.LoanModeMonthly, nil
+    }
+    return "", fmt.Errorf("unsupported payment mode: %s", mode)
+}
+
+// normalizeInterestMethod maps user input onto one of the supported interest methods
+func normalizeInterestMethod(method string) (string, error) {
+    switch strings.ToLower(strings.TrimSpace(method)) {
+    case "", "flat", "add-on":
+        return models.InterestMethodFlat, nil
+    case "diminishing", "declining":
+        return models.InterestMethodDiminishing, nil
+    }
+    return "", fmt.Errorf("unsupported interest method: %s", method)
+}
+
+// parseWeekday parses a weekday name such as "Monday"
+func parseWeekday(name string) (time.Weekday, bool) {
+    for d := time.Sunday; d <= time.Saturday; d++ {
+        if strings.EqualFold(d.String(), strings.TrimSpace(name)) {
+            return d, true
+        }
+    }
+    return time.Sunday, false
+}
+
+// roundCurrency rounds an amount to centavos
+func roundCurrency(amount float64) float64 {
+    return math.Round(amount*100) / 100
+}
+
+// roundRate rounds a rate to six decimal places
+func roundRate(rate float64) float64 {
+    return math.Round(rate*1e6) / 1e6
+}
@@ -1,21 +1,41 @@
 package services
 
 import (
+    "errors"
     "fmt"
     "micro-lending-platform/backend/internal/models"
     "micro-lending-platform/backend/internal/repositories"
     "time"
 )
 
+// ErrVersionConflict means a record changed after it was read; reload it and try again
+var ErrVersionConflict = repositories.ErrVersionConflict
+
+// ErrVersionRequired means an update did not say which version it was made against
+var ErrVersionRequired = errors.New("the version being updated is required, send it in If-Match or the body")
+
+// maxVersionAttempts is how many times a write is tried against a fresh read when it hits a version conflict
+const maxVersionAttempts = 3
+
 type LoanService struct {
-    loanRepo   *repositories.LoanRepository
-    clientRepo *repositories.ClientRepository  // Add clientRepo
+    loanRepo          *repositories.LoanRepository
+    clientRepo        *repositories.ClientRepository  // Add clientRepo
+    chargeRepo        *repositories.LoanChargeRepository
+    paymentRepo       *repositories.PaymentRepository
+    calendarService   *CalendarService
+    complianceService *ComplianceService
+    ledgerService     *LedgerService
 }
 
-func NewLoanService(loanRepo *repositories.LoanRepository, clientRepo *repositories.ClientRepository) *LoanService {
+func NewLoanService(loanRepo *repositories.LoanRepository, clientRepo *repositories.ClientRepository, chargeRepo *repositories.LoanChargeRepository, paymentRepo *repositories.PaymentRepository, calendarService *CalendarService, complianceService *ComplianceService, ledgerService *LedgerService) *LoanService {
     return &LoanService{
-        loanRepo:   loanRepo,
-        clientRepo: clientRepo,
+        loanRepo:          loanRepo,
+        clientRepo:        clientRepo,
+        chargeRepo:        chargeRepo,
+        paymentRepo:       paymentRepo,
+        calendarService:   calendarService,
+        complianceService: complianceService,
+        ledgerService:     ledgerService,
     }
 }
 
@@ -28,6 +48,14 @@ type LoanStats struct {
     TotalOutstanding float64 `json:"total_outstanding"`
 }
 
+// OverdueRefreshResult summarizes one run of overdue detection
+type OverdueRefreshResult struct {
+    AsOf          string `json:"as_of"`
+    LoansChecked  int    `json:"loans_checked"`
+    MarkedOverdue int    `json:"marked_overdue"`
+    Cleared       int    `json:"cleared"`
+}
+
 // CreateLoan creates a new loan - UPDATED to accept ClientID
 func (s *LoanService) CreateLoan(req *models.LoanCreate, clientID uint) (*models.Loan, error) {
     // Parse dates
@@ -68,6 +96,29 @@ func (s *LoanService) CreateLoan(req *models.LoanCreate, clientID uint) (*models
         NameCI:                req.NameCI,
         NotedBy:               req.NotedBy,
         ApplicationDate:       applicationDate,
+        BranchID:              req.BranchID,
+        CollectorID:           req.CollectorID,
+        OfficerID:             req.OfficerID,
+        ReleasedByID:          req.ReleasedByID,
+    }
+
+    // Loans are booked in the client's branch unless the request says otherwise
+    if loan.BranchID == nil {
+        client, err := s.clientRepo.FindByID(clientID)
+        if err != nil {
+            return nil, fmt.Errorf("client not found")
+        }
+        loan.BranchID = client.BranchID
+    }
+
+    calendar, err := s.calendarService.CalendarForBranch(loan.BranchID)
+    if err != nil {
+        return nil, err
+    }
+
+    // Quoted loans take their figures from the calculator so they match the simulation
+    if err := applyLoanCalculation(loan, req, calendar); err != nil {
+        return nil, fmt.Errorf("failed to calculate loan: %w", err)
     }
 
     // Generate control number if not provided
@@ -85,20 +136,59 @@ func (s *LoanService) CreateLoan(req *models.LoanCreate, clientID uint) (*models
         loan.Status = models.LoanStatusActive
     }
 
-    createdLoan, err := s.loanRepo.Create(loan)
+    // Pricing must stay within the regulatory caps unless an admin overrides
+    check, err := s.complianceService.EnforceOnNewLoan(loan, ScheduleForLoan(loan, calendar), req.ComplianceOverride)
+    if err != nil {
+        return nil, err
+    }
+
+    var createdLoan *models.Loan
+    if loan.ComplianceStatus == models.ComplianceStatusOverridden {
+        createdLoan, err = s.loanRepo.CreateWithOverride(loan, s.complianceService.NewOverrideRecord(check, req.ComplianceOverride))
+    } else {
+        createdLoan, err = s.loanRepo.Create(loan)
+    }
     if err != nil {
         return nil, fmt.Errorf("failed to create loan: %w", err)
     }
+    s.ledgerService.RecordLoanChange(createdLoan.ID)
 
     return createdLoan, nil
 }
 
+// SimulateLoan computes a loan quote without persisting anything
+func (s *LoanService) SimulateLoan(req *models.LoanSimulationRequest) (*models.LoanSimulation, error) {
+    calendar, err := s.calendarService.CalendarForBranch(req.BranchID)
+    if err != nil {
+        return nil, err
+    }
+    sim, err := CalculateLoan(req, calendar)
+    if err != nil {
+        return nil, err
+    }
+
+    if sim.Compliance, err = s.complianceService.CheckSimulation(sim); err != nil {
+        return nil, err
+    }
+    return sim, nil
+}
+
+// GetLoanSchedule returns the installment schedule of an existing loan
+func (s *LoanService) GetLoanSchedule(id uint) ([]models.Installment, error) {
+    loan, err := s.GetLoanByID(id)
+    if err != nil {
+        return nil, err
+    }
+    return s.calendarService.ScheduleForLoan(loan)
+}
+
 // UpdateLoanFromHandler updates loan information from handler (for ClientID updates)
 func (s *LoanService) UpdateLoanFromHandler(loan *models.Loan) (*models.Loan, error) {
     updatedLoan, err := s.loanRepo.Update(loan)
     if err != nil {
         return nil, fmt.Errorf("failed to update loan: %w", err)
     }
+    s.ledgerService.RecordLoanChange(updatedLoan.ID)
 
     return updatedLoan, nil
 }
@@ -155,9 +245,17 @@ func (s *LoanService) UpdateLoan(req *models.LoanUpdateRequest) (*models.Loan, e
     if err != nil {
         return nil, fmt.Errorf("loan not found")
     }
+    if req.Version == 0 {
+        return nil, ErrVersionRequired
+    }
+    if req.Version != loan.Version {
+        return nil, ErrVersionConflict
+    }
 
     // Update only provided fields
+    balanceEdit := 0.0
     if req.OutstandingBalance != nil {
+        balanceEdit = *req.OutstandingBalance - loan.OutstandingBalance
         loan.OutstandingBalance = *req.OutstandingBalance
     }
     if req.Status != "" {
@@ -169,15 +267,162 @@ func (s *LoanService) UpdateLoan(req *models.LoanUpdateRequest) (*models.Loan, e
     if req.DueDate != "" {
         loan.DueDate = req.DueDate
     }
+    if req.CollectorID != nil {
+        if *req.CollectorID == 0 {
+            loan.CollectorID = nil
+        } else {
+            loan.CollectorID = req.CollectorID
+        }
+    }
+    if req.OfficerID != nil {
+        if *req.OfficerID == 0 {
+            loan.OfficerID = nil
+        } else {
+            loan.OfficerID = req.OfficerID
+        }
+    }
 
     updatedLoan, err := s.loanRepo.Update(loan)
     if err != nil {
         return nil, fmt.Errorf("failed to update loan: %w", err)
     }
+    // A balance set by hand is the one change the ledger cannot work out from the loan's events
+    if roundCurrency(balanceEdit) != 0 {
+        s.ledgerService.RecordBalanceEdit(updatedLoan.ID, balanceEdit)
+    } else {
+        s.ledgerService.RecordLoanChange(updatedLoan.ID)
+    }
 
     return updatedLoan, nil
 }
 
+// AddCharge records a penalty or fee against a loan and adds it to the outstanding balance
+func (s *LoanService) AddCharge(loanID uint, req *models.LoanChargeCreateRequest, username string) (*models.LoanCharge, error) {
+    chargeType := models.ChargeType(req.ChargeType)
+    if chargeType != models.ChargeTypePenalty && chargeType != models.ChargeTypeFee {
+        return nil, fmt.Errorf("charge type must be Penalty or Fee")
+    }
+    if req.Amount <= 0 {
+        return nil, fmt.Errorf("charge amount must be greater than zero")
+    }
+
+    loan, err := s.loanRepo.FindByID(loanID)
+    if err != nil {
+        return nil, fmt.Errorf("loan not found")
+    }
+
+    chargeDate, err := s.parseDate(req.ChargeDate)
+    if err != nil {
+        chargeDate = time.Now()
+    }
+
+    charge := &models.LoanCharge{
+        LoanID:      loan.ID,
+        ChargeType:  chargeType,
+        Amount:      req.Amount,
+        ChargeDate:  chargeDate,
+        Description: req.Description,
+        CreatedBy:   username,
+    }
+    // The charge and the balance it adds to are saved together; a loan that changed meanwhile is re-read and tried again
+    for attempt := 1; ; attempt++ {
+        // A charge on a settled loan reopens it
+        status := loan.Status
+        if status == models.LoanStatusPaid {
+            status = models.LoanStatusActive
+        }
+        err := s.chargeRepo.Transaction(func(chargeRepo *repositories.LoanChargeRepository, loanRepo *repositories.LoanRepository) error {
+            if _, err := chargeRepo.Create(charge); err != nil {
+                return fmt.Errorf("failed to create charge: %w", err)
+            }
+            return loanRepo.UpdateBalance(loan.ID, loan.Version, loan.OutstandingBalance+req.Amount, status)
+        })
+        if err == nil {
+            break
+        }
+        charge.ID = 0
+        if !errors.Is(err, ErrVersionConflict) || attempt == maxVersionAttempts {
+            return nil, fmt.Errorf("failed to add charge: %w", err)
+        }
+        if loan, err = s.loanRepo.FindByID(loanID); err != nil {
+            return nil, fmt.Errorf("failed to reload loan: %w", err)
+        }
+    }
+    s.ledgerService.RecordLoanChange(loan.ID)
+
+    return charge, nil
+}
+
+// GetCharges retrieves all charges recorded against a loan
+func (s *LoanService) GetCharges(loanID uint) ([]models.LoanCharge, error) {
+    charges, err := s.chargeRepo.FindByLoanID(loanID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get charges: %w", err)
+    }
+    return charges, nil
+}
+
+// RefreshOverdueStatuses marks active loans with unpaid installments past their due date as Overdue,
+// and returns overdue loans that have caught up to Active
+func (s *LoanService) RefreshOverdueStatuses(asOf time.Time) (*OverdueRefreshResult, error) {
+    loans, err := s.loanRepo.FindByStatuses([]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue})
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    loanIDs := make([]uint, len(loans))
+    for i, loan := range loans {
+        loanIDs[i] = loan.ID
+    }
+    paid, err := s.paymentRepo.SumPaidByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+
+    result := &OverdueRefreshResult{AsOf: asOf.Format("2006-01-02"), LoansChecked: len(loans)}
+    calendars := make(map[uint]*HolidayCalendar)
+    for i := range loans {
+        loan := &loans[i]
+
+        var branchKey uint
+        if loan.BranchID != nil {
+            branchKey = *loan.BranchID
+        }
+        calendar, ok := calendars[branchKey]
+        if !ok {
+            if calendar, err = s.calendarService.CalendarForBranch(loan.BranchID); err != nil {
+                return nil, err
+            }
+            calendars[branchKey] = calendar
+        }
+
+        arrears := computeArrears(ScheduleForLoan(loan, calendar), paid[loan.ID], asOf)
+
+        newStatus := models.LoanStatusActive
+        if arrears.AmountPastDue > 0 {
+            newStatus = models.LoanStatusOverdue
+        }
+        if newStatus == loan.Status {
+            continue
+        }
+
+        if err := s.loanRepo.UpdateStatus(loan.ID, loan.Version, newStatus); err != nil {
+            // A payment landed after the loans were read; the next run will see it
+            if errors.Is(err, ErrVersionConflict) {
+                continue
+            }
+            return nil, fmt.Errorf("failed to update loan %d: %w", loan.ID, err)
+        }
+        if newStatus == models.LoanStatusOverdue {
+            result.MarkedOverdue++
+        } else {
+            result.Cleared++
+        }
+    }
+
+    return result, nil
+}
+
 // DeleteLoan soft deletes a loan
 func (s *LoanService) DeleteLoan(id uint) error {
     // Check if loan exists
@@ -193,6 +438,7 @@ func (s *LoanService) DeleteLoan(id uint) error {
     if err != nil {
         return fmt.Errorf("failed to delete loan: %w", err)
     }
+    s.ledgerService.RecordLoanChange(id)
 
     return nil
 }
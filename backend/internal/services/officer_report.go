@@ -0,0 +1,211 @@
+package services
+
+import (
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "time"
+)
+
+// officerTally accumulates one officer's figures over one period
+type officerTally struct {
+    borrowers   map[uint]bool // Clients with a balance at the end of the period
+    outstanding float64
+    atRisk1     float64
+    atRisk30    float64
+    released    int
+    disbursed   float64
+    newClients  int
+    repeat      int
+    efficiency  models.CollectionEfficiency
+    completed   int
+    dropouts    int
+}
+
+// GetOfficerPerformance compares loan officers over a range of business days: their portfolio at the end
+// of it, what they disbursed and collected during it, and a trend over earlier weekly or monthly periods
+func (s *ReportService) GetOfficerPerformance(r period.Range, periodType string, periods int, opts period.Options) (*models.OfficerPerformanceReport, error) {
+    loans, err := s.loanHistories(r.LastDay(), r.Location())
+    if err != nil {
+        return nil, err
+    }
+    users, err := s.userNames()
+    if err != nil {
+        return nil, err
+    }
+
+    today := period.Today(opts).LastDay()
+    tallies := officerTallies(loans, r, today, r.Location())
+    report := &models.OfficerPerformanceReport{
+        From:        r.From(),
+        To:          r.To(),
+        Timezone:    r.Location().String(),
+        PeriodType:  periodType,
+        Portfolio:   officerPerformance(0, "Portfolio", mergeTallies(tallies)),
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }
+
+    trendPeriods := period.Months(r.End(), periods, opts)
+    if periodType == "weekly" {
+        trendPeriods = period.Weeks(r.End(), periods, opts)
+    }
+    trends := make(map[uint][]models.OfficerTrendPoint)
+    for _, tp := range trendPeriods {
+        label := tp.LastDay().Format("January 2006")
+        if periodType == "weekly" {
+            label = tp.FirstDay().Format("Jan 02") + " - " + tp.LastDay().Format("Jan 02, 2006")
+        }
+        for officerID, tally := range officerTallies(loans, tp, today, r.Location()) {
+            perf := officerPerformance(officerID, "", tally)
+            trends[officerID] = append(trends[officerID], models.OfficerTrendPoint{
+                Period:            label,
+                StartDate:         tp.From(),
+                EndDate:           tp.To(),
+                ActiveBorrowers:   perf.ActiveBorrowers,
+                Outstanding:       perf.Outstanding,
+                Disbursed:         perf.Disbursed,
+                Par30:             perf.Par30,
+                CurrentEfficiency: perf.CurrentEfficiency,
+                DropoutRate:       perf.DropoutRate,
+            })
+        }
+    }
+
+    report.Officers = []models.OfficerPerformance{}
+    for officerID, tally := range tallies {
+        perf := officerPerformance(officerID, groupName(users, officerID), tally)
+        perf.Trend = trends[officerID]
+        report.Officers = append(report.Officers, perf)
+    }
+    sort.Slice(report.Officers, func(i, j int) bool { return report.Officers[i].Name < report.Officers[j].Name })
+    return report, nil
+}
+
+// officerTallies works out every officer's figures for one period from loans in release order.
+// Portfolio figures are taken at the end of the period, or today while the period is still running.
+func officerTallies(loans []*loanHistory, r period.Range, today time.Time, loc *time.Location) map[uint]*officerTally {
+    from, to := r.FirstDay(), r.LastDay()
+    asOf := to
+    if today.Before(asOf) {
+        asOf = today
+    }
+    tallies := make(map[uint]*officerTally)
+    tally := func(officerID uint) *officerTally {
+        t, ok := tallies[officerID]
+        if !ok {
+            t = &officerTally{borrowers: make(map[uint]bool)}
+            tallies[officerID] = t
+        }
+        return t
+    }
+
+    // A client's first loan makes them new; any later loan is a repeat
+    firstLoan := make(map[uint]uint)
+    for _, ol := range loans {
+        if _, ok := firstLoan[ol.loan.ClientID]; !ok {
+            firstLoan[ol.loan.ClientID] = ol.loan.ID
+        }
+    }
+
+    for i, ol := range loans {
+        if ol.released.After(to) {
+            continue
+        }
+        t := tally(loanOfficerID(ol.loan))
+
+        if outstanding, arrears := ol.positionAt(asOf, loc); outstanding > 0 {
+            t.borrowers[ol.loan.ClientID] = true
+            t.outstanding += outstanding
+            if arrears.DaysPastDue > 0 {
+                t.atRisk1 += outstanding
+            }
+            if arrears.DaysPastDue > 30 {
+                t.atRisk30 += outstanding
+            }
+        }
+
+        if !ol.released.Before(from) {
+            t.released++
+            t.disbursed += ol.loan.AmountRelease
+            if firstLoan[ol.loan.ClientID] == ol.loan.ID {
+                t.newClients++
+            } else {
+                t.repeat++
+            }
+        }
+
+        if collection, ok := collectionFor(ol.efficiencyLoan, from, to, loc); ok {
+            addCollection(&t.efficiency, collection)
+        }
+
+        if ol.paidOff != nil && !ol.paidOff.Before(from) && !ol.paidOff.After(to) {
+            t.completed++
+            if !renewedBy(loans[i+1:], ol.loan.ClientID, to) {
+                t.dropouts++
+            }
+        }
+    }
+    return tallies
+}
+
+// renewedBy reports whether a client took another loan released by a day; later holds the loans
+// released after the one paid off, in release order
+func renewedBy(later []*loanHistory, clientID uint, through time.Time) bool {
+    for _, next := range later {
+        if next.released.After(through) {
+            return false
+        }
+        if next.loan.ClientID == clientID {
+            return true
+        }
+    }
+    return false
+}
+
+// mergeTallies adds every officer's tally together for the portfolio line
+func mergeTallies(tallies map[uint]*officerTally) *officerTally {
+    total := &officerTally{borrowers: make(map[uint]bool)}
+    for _, t := range tallies {
+        for clientID := range t.borrowers {
+            total.borrowers[clientID] = true
+        }
+        total.outstanding += t.outstanding
+        total.atRisk1 += t.atRisk1
+        total.atRisk30 += t.atRisk30
+        total.released += t.released
+        total.disbursed += t.disbursed
+        total.newClients += t.newClients
+        total.repeat += t.repeat
+        total.efficiency.Loans += t.efficiency.Loans
+        total.efficiency.DueInPeriod += t.efficiency.DueInPeriod
+        total.efficiency.PastDueAtStart += t.efficiency.PastDueAtStart
+        total.efficiency.CurrentCollected += t.efficiency.CurrentCollected
+        total.efficiency.PastDueCollected += t.efficiency.PastDueCollected
+        total.efficiency.AdvanceCollected += t.efficiency.AdvanceCollected
+        total.completed += t.completed
+        total.dropouts += t.dropouts
+    }
+    return total
+}
+
+// officerPerformance rounds a tally and works out its ratios
+func officerPerformance(officerID uint, name string, t *officerTally) models.OfficerPerformance {
+    finishEfficiency(&t.efficiency)
+    return models.OfficerPerformance{
+        OfficerID:         officerID,
+        Name:              name,
+        ActiveBorrowers:   len(t.borrowers),
+        Outstanding:       roundCurrency(t.outstanding),
+        LoansReleased:     t.released,
+        Disbursed:         roundCurrency(t.disbursed),
+        NewClients:        t.newClients,
+        RepeatClients:     t.repeat,
+        Par1:              percentOf(t.atRisk1, t.outstanding),
+        Par30:             percentOf(t.atRisk30, t.outstanding),
+        CurrentEfficiency: t.efficiency.CurrentEfficiency,
+        OverallEfficiency: t.efficiency.OverallEfficiency,
+        LoansCompleted:    t.completed,
+        Dropouts:          t.dropouts,
+        DropoutRate:       percentOf(float64(t.dropouts), float64(t.completed)),
+    }
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// parBuckets lists the arrears buckets from least to most delinquent
+var parBuckets = []string{
+    models.ParBucket1To7, models.ParBucket8To30, models.ParBucket31To60, models.ParBucket61To90, models.ParBucketOver90,
+}
+
+// parBucket places a number of days past due into its PAR bucket
+func parBucket(daysPastDue int) string {
+    switch {
+    case daysPastDue <= 0:
+        return models.ParBucketCurrent
+    case daysPastDue <= 7:
+        return models.ParBucket1To7
+    case daysPastDue <= 30:
+        return models.ParBucket8To30
+    case daysPastDue <= 60:
+        return models.ParBucket31To60
+    case daysPastDue <= 90:
+        return models.ParBucket61To90
+    default:
+        return models.ParBucketOver90
+    }
+}
+
+// GetParReport returns portfolio at risk as of the end of a business day, for the whole portfolio
+// and broken down by branch, loan officer and product
+func (s *ReportService) GetParReport(asOf period.Range) (*models.ParReport, error) {
+    loans, err := s.parLoans(asOf.LastDay())
+    if err != nil {
+        return nil, err
+    }
+
+    portfolio := newParSummary("", "Portfolio")
+    byBranch := make(map[string]*models.ParSummary)
+    byOfficer := make(map[string]*models.ParSummary)
+    byProduct := make(map[string]*models.ParSummary)
+
+    for _, loan := range loans {
+        addToPar(portfolio, loan)
+        addToParGroup(byBranch, strconv.FormatUint(uint64(loan.BranchID), 10), loan.BranchName, loan)
+        addToParGroup(byOfficer, strconv.FormatUint(uint64(loan.OfficerID), 10), loan.OfficerName, loan)
+        addToParGroup(byProduct, loan.Product, loan.Product, loan)
+    }
+
+    finishPar(portfolio)
+    return &models.ParReport{
+        AsOf:        asOf.To(),
+        Timezone:    asOf.Location().String(),
+        Portfolio:   *portfolio,
+        ByBranch:    sortedParGroups(byBranch),
+        ByOfficer:   sortedParGroups(byOfficer),
+        ByProduct:   sortedParGroups(byProduct),
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }, nil
+}
+
+// GetParLoans drills down from a PAR figure to the loans behind it, most delinquent first
+func (s *ReportService) GetParLoans(asOf period.Range, filter *models.ParLoanFilter) (*models.ParLoanList, error) {
+    if filter.Bucket != "" && filter.Bucket != models.ParBucketCurrent && parBucketIndex(filter.Bucket) < 0 {
+        return nil, fmt.Errorf("unknown bucket %q", filter.Bucket)
+    }
+
+    loans, err := s.parLoans(asOf.LastDay())
+    if err != nil {
+        return nil, err
+    }
+
+    list := &models.ParLoanList{AsOf: asOf.To(), Timezone: asOf.Location().String(), Loans: []models.ParLoan{}}
+    for _, loan := range loans {
+        if filter.Bucket != "" && loan.Bucket != filter.Bucket {
+            continue
+        }
+        if filter.BranchID != nil && loan.BranchID != *filter.BranchID {
+            continue
+        }
+        if filter.OfficerID != nil && loan.OfficerID != *filter.OfficerID {
+            continue
+        }
+        if filter.Product != "" && loan.Product != filter.Product {
+            continue
+        }
+        list.Loans = append(list.Loans, loan)
+        list.Outstanding += loan.Outstanding
+        list.PastDue += loan.AmountPastDue
+    }
+
+    sort.SliceStable(list.Loans, func(i, j int) bool {
+        if list.Loans[i].DaysPastDue != list.Loans[j].DaysPastDue {
+            return list.Loans[i].DaysPastDue > list.Loans[j].DaysPastDue
+        }
+        return list.Loans[i].Outstanding > list.Loans[j].Outstanding
+    })
+    list.Count = len(list.Loans)
+    list.Outstanding = roundCurrency(list.Outstanding)
+    list.PastDue = roundCurrency(list.PastDue)
+    return list, nil
+}
+
+// parLoans computes every open loan's arrears position as of a day
+func (s *ReportService) parLoans(asOf time.Time) ([]models.ParLoan, error) {
+    positions, err := s.portfolioAsOf(asOf)
+    if err != nil {
+        return nil, err
+    }
+    branches, err := s.branchNames()
+    if err != nil {
+        return nil, err
+    }
+    users, err := s.userNames()
+    if err != nil {
+        return nil, err
+    }
+
+    loans := make([]models.ParLoan, 0, len(positions))
+    for _, pos := range positions {
+        loan := pos.loan
+        branchID := loanBranchID(loan)
+        officerID := loanOfficerID(loan)
+        loans = append(loans, models.ParLoan{
+            LoanID:        loan.ID,
+            ControlNumber: loan.ControlNumber,
+            ClientID:      loan.ClientID,
+            ClientName:    loan.Client.LastName + ", " + loan.Client.FirstName,
+            BranchID:      branchID,
+            BranchName:    groupName(branches, branchID),
+            OfficerID:     officerID,
+            OfficerName:   groupName(users, officerID),
+            Product:       loanProduct(loan),
+            Outstanding:   pos.outstanding,
+            AmountPastDue: pos.arrears.AmountPastDue,
+            DaysPastDue:   pos.arrears.DaysPastDue,
+            Bucket:        parBucket(pos.arrears.DaysPastDue),
+            OldestDueDate: pos.arrears.OldestDueDate,
+        })
+    }
+    return loans, nil
+}
+
+// newParSummary starts a PAR grouping with every bucket present
+func newParSummary(key, name string) *models.ParSummary {
+    summary := &models.ParSummary{Key: key, Name: name}
+    for _, bucket := range parBuckets {
+        summary.Buckets = append(summary.Buckets, models.ParBucketTotal{Bucket: bucket})
+    }
+    return summary
+}
+
+// addToParGroup adds a loan to the grouping with the given key, starting it if needed
+func addToParGroup(groups map[string]*models.ParSummary, key, name string, loan models.ParLoan) {
+    summary, ok := groups[key]
+    if !ok {
+        summary = newParSummary(key, name)
+        groups[key] = summary
+    }
+    addToPar(summary, loan)
+}
+
+// addToPar adds a loan's outstanding balance to a grouping and its arrears bucket
+func addToPar(summary *models.ParSummary, loan models.ParLoan) {
+    summary.Loans++
+    summary.Outstanding += loan.Outstanding
+
+    i := parBucketIndex(loan.Bucket)
+    if i < 0 {
+        return
+    }
+    summary.AtRisk += loan.Outstanding
+    summary.Buckets[i].Loans++
+    summary.Buckets[i].Outstanding += loan.Outstanding
+}
+
+// finishPar rounds a grouping's totals and works out its PAR ratios
+func finishPar(summary *models.ParSummary) {
+    summary.Outstanding = roundCurrency(summary.Outstanding)
+    summary.AtRisk = roundCurrency(summary.AtRisk)
+
+    // PAR7 counts buckets from 8-30 on, PAR30 from 31-60 on
+    var over7, over30 float64
+    for i := range summary.Buckets {
+        bucket := &summary.Buckets[i]
+        bucket.Outstanding = roundCurrency(bucket.Outstanding)
+        bucket.Percent = percentOf(bucket.Outstanding, summary.Outstanding)
+        if i >= 1 {
+            over7 += bucket.Outstanding
+        }
+        if i >= 2 {
+            over30 += bucket.Outstanding
+        }
+    }
+    summary.Par1 = percentOf(summary.AtRisk, summary.Outstanding)
+    summary.Par7 = percentOf(over7, summary.Outstanding)
+    summary.Par30 = percentOf(over30, summary.Outstanding)
+}
+
+// sortedParGroups finishes each grouping and orders them by name
+func sortedParGroups(groups map[string]*models.ParSummary) []models.ParSummary {
+    summaries := make([]models.ParSummary, 0, len(groups))
+    for _, summary := range groups {
+        finishPar(summary)
+        summaries = append(summaries, *summary)
+    }
+    sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+    return summaries
+}
+
+// parBucketIndex returns a bucket's position in parBuckets, or -1 for current or unknown buckets
+func parBucketIndex(bucket string) int {
+    for i, b := range parBuckets {
+        if b == bucket {
+            return i
+        }
+    }
+    return -1
+}
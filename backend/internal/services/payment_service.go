@@ -1,6 +1,7 @@
 package services
 
 import (
+    "errors"
     "fmt"
     "micro-lending-platform/backend/internal/models"
     "micro-lending-platform/backend/internal/repositories"
@@ -8,14 +9,17 @@ import (
 )
 
 type PaymentService struct {
-    paymentRepo *repositories.PaymentRepository
-    loanRepo    *repositories.LoanRepository
+    paymentRepo   *repositories.PaymentRepository
+    loanRepo      *repositories.LoanRepository
+    ledgerService *LedgerService
+    paidLoans     []uint // Loans paid on inside a transaction, posted to the ledger once it commits
 }
 
-func NewPaymentService(paymentRepo *repositories.PaymentRepository, loanRepo *repositories.LoanRepository) *PaymentService {
+func NewPaymentService(paymentRepo *repositories.PaymentRepository, loanRepo *repositories.LoanRepository, ledgerService *LedgerService) *PaymentService {
     return &PaymentService{
-        paymentRepo: paymentRepo,
-        loanRepo:    loanRepo,
+        paymentRepo:   paymentRepo,
+        loanRepo:      loanRepo,
+        ledgerService: ledgerService,
     }
 }
 
@@ -41,39 +45,120 @@ func (s *PaymentService) GetPaymentByID(id uint) (*models.Payment, error) {
     return payment, nil
 }
 
-// UpdatePayment updates payment information
+// UpdatePayment updates payment information and applies any change in the amount paid to the loan's
+// outstanding balance, recounting its paid weeks, so the ledger's re-posting leaves the loan tied out
 func (s *PaymentService) UpdatePayment(payment *models.Payment) (*models.Payment, error) {
-    // Check if payment exists
-    _, err := s.paymentRepo.FindByID(payment.ID)
-    if err != nil {
-        return nil, fmt.Errorf("payment not found")
-    }
+    if payment.Version == 0 {
+        return nil, ErrVersionRequired
+    }
+    var updatedPayment *models.Payment
+    version := payment.Version
+    err := s.InTransaction(func(tx *PaymentService) error {
+        // Check if payment exists
+        existing, err := tx.paymentRepo.FindByID(payment.ID)
+        if err != nil {
+            return fmt.Errorf("payment not found")
+        }
+        loan, err := tx.loanRepo.FindByID(existing.LoanID)
+        if err != nil {
+            return fmt.Errorf("loan not found: %w", err)
+        }
 
-    updatedPayment, err := s.paymentRepo.Update(payment)
+        // A payment stays on its loan; a retry starts again from the version the caller sent
+        payment.LoanID = existing.LoanID
+        payment.Version = version
+        updated, err := tx.paymentRepo.Update(payment)
+        if err != nil {
+            return fmt.Errorf("failed to update payment: %w", err)
+        }
+        tx.paidLoans = append(tx.paidLoans, loan.ID)
+
+        newBalance := loan.OutstandingBalance + existing.AmountPaid - payment.AmountPaid
+        if err := tx.recountLoan(loan, newBalance); err != nil {
+            if errors.Is(err, ErrVersionConflict) {
+                return err
+            }
+            return fmt.Errorf("failed to update loan: %w", err)
+        }
+        updatedPayment = updated
+        return nil
+    })
     if err != nil {
-        return nil, fmt.Errorf("failed to update payment: %w", err)
+        return nil, err
     }
-
     return updatedPayment, nil
 }
 
-// DeletePayment soft deletes a payment
+// DeletePayment soft deletes a payment and gives its amount back to the loan's outstanding balance,
+// so the reversal the ledger posts for it leaves the loan tied out
 func (s *PaymentService) DeletePayment(id uint) error {
-    // Check if payment exists
-    _, err := s.paymentRepo.FindByID(id)
-    if err != nil {
-        if err.Error() == "record not found" {
-            return fmt.Errorf("payment not found")
+    return s.InTransaction(func(tx *PaymentService) error {
+        // Check if payment exists
+        payment, err := tx.paymentRepo.FindByID(id)
+        if err != nil {
+            if err.Error() == "record not found" {
+                return fmt.Errorf("payment not found")
+            }
+            return fmt.Errorf("failed to find payment: %w", err)
+        }
+        loan, err := tx.loanRepo.FindByID(payment.LoanID)
+        if err != nil {
+            return fmt.Errorf("loan not found: %w", err)
         }
-        return fmt.Errorf("failed to find payment: %w", err)
+
+        if err := tx.paymentRepo.Delete(id); err != nil {
+            return fmt.Errorf("failed to delete payment: %w", err)
+        }
+        tx.paidLoans = append(tx.paidLoans, loan.ID)
+
+        if err := tx.recountLoan(loan, loan.OutstandingBalance+payment.AmountPaid); err != nil {
+            if errors.Is(err, ErrVersionConflict) {
+                return err
+            }
+            return fmt.Errorf("failed to update loan: %w", err)
+        }
+        return nil
+    })
+}
+
+// recountLoan sets a loan's balance after one of its payments was edited or deleted. Paid weeks become
+// the last week its payments now complete, and its status follows: paid off when nothing is left
+// or every week is paid, reopened when a loan marked paid owes again.
+func (s *PaymentService) recountLoan(loan *models.Loan, newBalance float64) error {
+    newBalance = roundCurrency(newBalance)
+    if newBalance < 0 {
+        newBalance = 0
     }
 
-    err = s.paymentRepo.Delete(id)
+    payments, err := s.paymentRepo.FindByLoanID(loan.ID)
     if err != nil {
-        return fmt.Errorf("failed to delete payment: %w", err)
+        return err
+    }
+    completed := make(map[int]bool)
+    partials := make(map[int]float64)
+    for _, payment := range payments {
+        switch {
+        case payment.CompletesWeek || (!payment.IsPartial && payment.Status == models.PaymentStatusPaid):
+            completed[payment.WeekNumber] = true
+        case payment.IsPartial:
+            partials[payment.WeekNumber] += payment.AmountPaid
+            completed[payment.WeekNumber] = completed[payment.WeekNumber] || partials[payment.WeekNumber] >= loan.Ammortization
+        }
+    }
+    newPaidWeeks := 0
+    for week, done := range completed {
+        if done && week > newPaidWeeks {
+            newPaidWeeks = week
+        }
     }
 
-    return nil
+    newStatus := loan.Status
+    if newBalance == 0 || newPaidWeeks >= loan.PaymentPeriodWeeks {
+        newStatus = models.LoanStatusPaid
+    } else if newStatus == models.LoanStatusPaid {
+        newStatus = models.LoanStatusActive
+    }
+    return s.loanRepo.UpdateBalanceAndProgress(loan.ID, loan.Version, newBalance, newPaidWeeks, newStatus)
 }
 
 // GetAllPayments retrieves all payments with pagination
@@ -141,8 +226,71 @@ func (s *PaymentService) parseDate(dateStr string) (time.Time, error) {
 }
 
 
-// CreatePayment creates a new payment and updates the loan's outstanding balance
+// CreatePayment creates a new payment and updates the loan's outstanding balance.
+// If another payment changes the loan first, the payment is rolled back and re-applied to the new balance.
 func (s *PaymentService) CreatePayment(req *models.PaymentCreateRequest) (*models.Payment, error) {
+    var createdPayment *models.Payment
+    err := s.InTransaction(func(tx *PaymentService) error {
+        payment, err := tx.createPayment(req)
+        var loanErr *loanUpdateError
+        if errors.As(err, &loanErr) && !errors.Is(err, ErrVersionConflict) {
+            fmt.Printf("Warning: Failed to update loan: %v\n", loanErr.err)
+            err = nil
+        }
+        createdPayment = payment
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+    return createdPayment, nil
+}
+
+// InTransaction runs fn with a payment service whose reads and writes share one database transaction.
+// When fn fails on a version conflict the transaction is rolled back and fn runs again on fresh reads.
+// Loans paid on are posted to the ledger after the transaction commits.
+func (s *PaymentService) InTransaction(fn func(tx *PaymentService) error) error {
+    var err error
+    for attempt := 1; attempt <= maxVersionAttempts; attempt++ {
+        var paidLoans []uint
+        err = s.paymentRepo.Transaction(func(paymentRepo *repositories.PaymentRepository, loanRepo *repositories.LoanRepository) error {
+            tx := &PaymentService{paymentRepo: paymentRepo, loanRepo: loanRepo}
+            err := fn(tx)
+            paidLoans = tx.paidLoans
+            return err
+        })
+        if err == nil {
+            posted := make(map[uint]bool)
+            for _, loanID := range paidLoans {
+                if !posted[loanID] {
+                    posted[loanID] = true
+                    s.ledgerService.RecordLoanChange(loanID)
+                }
+            }
+        }
+        if !errors.Is(err, ErrVersionConflict) {
+            return err
+        }
+    }
+    return err
+}
+
+// loanUpdateError means the payment was saved but the loan's balance and progress were not updated
+type loanUpdateError struct {
+    err error
+}
+
+func (e *loanUpdateError) Error() string {
+    return fmt.Sprintf("failed to update loan: %v", e.err)
+}
+
+func (e *loanUpdateError) Unwrap() error {
+    return e.err
+}
+
+// createPayment records a payment and applies it to the loan. A failed loan update comes back
+// as a loanUpdateError alongside the payment, so single postings can keep it while batches roll back.
+func (s *PaymentService) createPayment(req *models.PaymentCreateRequest) (*models.Payment, error) {
     // Validate the loan exists
     loan, err := s.loanRepo.FindByID(req.LoanID)
     if err != nil {
@@ -193,6 +341,8 @@ func (s *PaymentService) CreatePayment(req *models.PaymentCreateRequest) (*model
         PaymentMethod:   req.PaymentMethod,
         IsPartial:       req.IsPartial,
         CompletesWeek:   req.CompletesWeek,
+        SyncID:          req.SyncID,
+        ReceivedBy:      req.ReceivedBy,
     }
 
     // Create payment in database
@@ -200,16 +350,71 @@ func (s *PaymentService) CreatePayment(req *models.PaymentCreateRequest) (*model
     if err != nil {
         return nil, fmt.Errorf("failed to create payment: %w", err)
     }
+    s.paidLoans = append(s.paidLoans, loan.ID)
 
     // Update loan balance and progress
-    err = s.updateLoanAfterPayment(loan, payment)
-    if err != nil {
-        fmt.Printf("Warning: Failed to update loan: %v\n", err)
+    if err := s.updateLoanAfterPayment(loan, payment); err != nil {
+        return createdPayment, &loanUpdateError{err: err}
     }
-
     return createdPayment, nil
 }
 
+// postCollectedAmount applies an amount collected in the field to the borrower's installments in order:
+// each is settled in turn, the last one reached as a partial payment if the amount runs out first, and
+// anything beyond the final installment stays on it. It returns the first payment, which carries the sync ID.
+func (s *PaymentService) postCollectedAmount(loan *models.Loan, amount float64, paymentDate, method string, syncID *string, receivedBy *uint) (*models.Payment, error) {
+    var first *models.Payment
+    for left := roundCurrency(amount); left > 0; {
+        week := loan.PaidWeeks + 1
+        remaining, err := s.CalculateRemainingBalance(loan.ID, week)
+        if err != nil {
+            return nil, err
+        }
+        portion := left
+        if portion > remaining && remaining > 0 && week < loan.PaymentPeriodWeeks {
+            portion = remaining
+        }
+
+        // Earlier partials on this installment mean this one is a partial too, even if it settles it
+        isPartial := portion < remaining || remaining < loan.Ammortization
+        status := models.PaymentStatusPaid
+        if isPartial {
+            status = models.PaymentStatusPartial
+        }
+
+        payment, err := s.createPayment(&models.PaymentCreateRequest{
+            LoanID:        loan.ID,
+            WeekNumber:    week,
+            PaymentDate:   paymentDate,
+            AmountDue:     loan.Ammortization,
+            AmountPaid:    portion,
+            Status:        string(status),
+            PaymentMethod: method,
+            IsPartial:     isPartial,
+            SyncID:        syncID,
+            ReceivedBy:    receivedBy,
+        })
+        if err != nil {
+            return nil, err
+        }
+        if first == nil {
+            first = payment
+            syncID = nil
+        }
+
+        if left = roundCurrency(left - portion); left > 0 {
+            // The payment moved the loan on; the next installment starts from where it is now
+            if loan, err = s.loanRepo.FindByID(loan.ID); err != nil {
+                return nil, fmt.Errorf("loan not found: %w", err)
+            }
+        }
+    }
+    if first == nil {
+        return nil, fmt.Errorf("amount must be greater than zero")
+    }
+    return first, nil
+}
+
 // updateLoanAfterPayment handles loan updates after payment creation
 func (s *PaymentService) updateLoanAfterPayment(loan *models.Loan, payment *models.Payment) error {
     // Calculate new outstanding balance
@@ -248,7 +453,7 @@ func (s *PaymentService) updateLoanAfterPayment(loan *models.Loan, payment *mode
     }
 
     // Update loan in database
-    return s.loanRepo.UpdateBalanceAndProgress(loan.ID, newBalance, newPaidWeeks, newStatus)
+    return s.loanRepo.UpdateBalanceAndProgress(loan.ID, loan.Version, newBalance, newPaidWeeks, newStatus)
 }
 
 // checkIfWeekCompleted checks if accumulated payments complete the week
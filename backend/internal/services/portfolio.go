@@ -0,0 +1,265 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "sort"
+    "time"
+)
+
+// loanPosition is where one loan stood at the end of a day
+type loanPosition struct {
+    loan        *models.Loan
+    outstanding float64
+    arrears     LoanArrears
+}
+
+// portfolioAsOf rebuilds every loan's balance and arrears at the end of a day. Payments and charges
+// dated after that day are backed out of the current balance, so past days can be reported on.
+func (s *ReportService) portfolioAsOf(asOf time.Time) ([]loanPosition, error) {
+    day := dateOnly(asOf)
+    next := day.AddDate(0, 0, 1)
+
+    loans, err := s.loanRepo.FindReleasedThrough(day, []models.LoanStatus{
+        models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusDefault, models.LoanStatusPaid,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    loanIDs := make([]uint, len(loans))
+    for i, loan := range loans {
+        loanIDs[i] = loan.ID
+    }
+    paidTotal, err := s.paymentRepo.SumPaidByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+    paidAsOf, err := s.paymentRepo.SumPaidByLoanIDsBefore(loanIDs, next)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+    charges, err := s.chargeRepo.FindByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get charges: %w", err)
+    }
+    chargedLater := make(map[uint]float64)
+    for _, charge := range charges {
+        if !charge.ChargeDate.Before(next) {
+            chargedLater[charge.LoanID] += charge.Amount
+        }
+    }
+
+    var positions []loanPosition
+    calendars := newCalendarCache(s.calendarService)
+    for i := range loans {
+        loan := &loans[i]
+        outstanding := roundCurrency(loan.OutstandingBalance + paidTotal[loan.ID] - paidAsOf[loan.ID] - chargedLater[loan.ID])
+        if outstanding <= 0 {
+            continue
+        }
+
+        schedule, err := calendars.schedule(loan)
+        if err != nil {
+            return nil, err
+        }
+        positions = append(positions, loanPosition{
+            loan:        loan,
+            outstanding: outstanding,
+            arrears:     computeArrears(schedule, paidAsOf[loan.ID], day),
+        })
+    }
+    return positions, nil
+}
+
+// loanHistory is a loan with everything needed to rebuild its position on any earlier day
+type loanHistory struct {
+    efficiencyLoan
+    released  time.Time // Release date as a calendar date
+    paidTotal float64   // Everything ever paid, including after the report
+    charges   []models.LoanCharge
+    paidOff   *time.Time // Business date of the last payment on a paid loan
+}
+
+// paidThrough totals the loan's payments up to the end of a business day
+func (ol *loanHistory) paidThrough(day time.Time, loc *time.Location) float64 {
+    paid := 0.0
+    for _, payment := range ol.payments {
+        if period.LocalDate(payment.PaymentDate, loc).After(day) {
+            break
+        }
+        paid += payment.AmountPaid
+    }
+    return paid
+}
+
+// positionAt rebuilds the loan's outstanding balance and arrears at the end of a business day
+func (ol *loanHistory) positionAt(day time.Time, loc *time.Location) (float64, LoanArrears) {
+    paid := ol.paidThrough(day, loc)
+    chargedLater := 0.0
+    for _, charge := range ol.charges {
+        if dateOnly(charge.ChargeDate).After(day) {
+            chargedLater += charge.Amount
+        }
+    }
+    outstanding := roundCurrency(ol.loan.OutstandingBalance + ol.paidTotal - paid - chargedLater)
+    return outstanding, computeArrears(ol.schedule, paid, day)
+}
+
+// loanHistories loads every loan released by a day with its schedule, payments and charges, in release order
+func (s *ReportService) loanHistories(through time.Time, loc *time.Location) ([]*loanHistory, error) {
+    loans, err := s.efficiencyLoans(through)
+    if err != nil {
+        return nil, err
+    }
+
+    loanIDs := make([]uint, len(loans))
+    for i, el := range loans {
+        loanIDs[i] = el.loan.ID
+    }
+    paidTotal, err := s.paymentRepo.SumPaidByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+    charges, err := s.chargeRepo.FindByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get charges: %w", err)
+    }
+    chargesByLoan := make(map[uint][]models.LoanCharge)
+    for _, charge := range charges {
+        chargesByLoan[charge.LoanID] = append(chargesByLoan[charge.LoanID], charge)
+    }
+
+    result := make([]*loanHistory, len(loans))
+    for i, el := range loans {
+        ol := &loanHistory{
+            efficiencyLoan: el,
+            released:       dateOnly(el.loan.DateOfRelease),
+            paidTotal:      paidTotal[el.loan.ID],
+            charges:        chargesByLoan[el.loan.ID],
+        }
+        // A loan paid off after the report still had a balance then: some of its payments fall later
+        loaded := 0.0
+        for _, payment := range el.payments {
+            loaded += payment.AmountPaid
+        }
+        if el.loan.Status == models.LoanStatusPaid && len(el.payments) > 0 && roundCurrency(loaded) >= roundCurrency(ol.paidTotal) {
+            last := period.LocalDate(el.payments[len(el.payments)-1].PaymentDate, loc)
+            ol.paidOff = &last
+        }
+        result[i] = ol
+    }
+
+    // Releases in order, so each client's first loan and renewals can be found
+    sort.SliceStable(result, func(i, j int) bool {
+        if !result[i].released.Equal(result[j].released) {
+            return result[i].released.Before(result[j].released)
+        }
+        return result[i].loan.ID < result[j].loan.ID
+    })
+    return result, nil
+}
+
+// calendarCache loads each branch's holiday calendar once while a report builds many schedules
+type calendarCache struct {
+    service   *CalendarService
+    calendars map[uint]*HolidayCalendar
+}
+
+func newCalendarCache(service *CalendarService) *calendarCache {
+    return &calendarCache{service: service, calendars: make(map[uint]*HolidayCalendar)}
+}
+
+// schedule returns a loan's installment schedule on its branch's calendar
+func (c *calendarCache) schedule(loan *models.Loan) ([]models.Installment, error) {
+    branchKey := loanBranchID(loan)
+    calendar, ok := c.calendars[branchKey]
+    if !ok {
+        var err error
+        if calendar, err = c.service.CalendarForBranch(loan.BranchID); err != nil {
+            return nil, err
+        }
+        c.calendars[branchKey] = calendar
+    }
+    return ScheduleForLoan(loan, calendar), nil
+}
+
+// branchNames maps branch IDs to names for report groupings
+func (s *ReportService) branchNames() (map[uint]string, error) {
+    branches, err := s.calendarService.GetBranches()
+    if err != nil {
+        return nil, err
+    }
+    names := make(map[uint]string, len(branches))
+    for _, branch := range branches {
+        names[branch.ID] = branch.Name
+    }
+    return names, nil
+}
+
+// userNames maps user IDs to usernames for report groupings
+func (s *ReportService) userNames() (map[uint]string, error) {
+    users, err := s.userRepo.ListAll()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get users: %w", err)
+    }
+    names := make(map[uint]string, len(users))
+    for _, user := range users {
+        names[user.ID] = user.Username
+    }
+    return names, nil
+}
+
+// loanBranchID returns a loan's branch, or 0 when it has none
+func loanBranchID(loan *models.Loan) uint {
+    if loan.BranchID == nil {
+        return 0
+    }
+    return *loan.BranchID
+}
+
+// loanCollectorID returns the loan's field collector, or 0 when unassigned
+func loanCollectorID(loan *models.Loan) uint {
+    if loan.CollectorID == nil {
+        return 0
+    }
+    return *loan.CollectorID
+}
+
+// loanOfficerID returns the loan officer responsible for a loan, or 0 when unassigned.
+// Loans booked before officers were recorded fall back to their field collector.
+func loanOfficerID(loan *models.Loan) uint {
+    if loan.OfficerID != nil {
+        return *loan.OfficerID
+    }
+    return loanCollectorID(loan)
+}
+
+// loanProduct names a loan's product by its repayment mode and term, e.g. "Weekly 3-month"
+func loanProduct(loan *models.Loan) string {
+    mode := loan.Mode
+    if mode == "" {
+        mode = models.LoanModeWeekly
+    }
+    return fmt.Sprintf("%s %d-month", mode, loan.Terms)
+}
+
+// groupName looks up a grouping's display name, naming ID 0 "Unassigned"
+func groupName(names map[uint]string, id uint) string {
+    if id == 0 {
+        return "Unassigned"
+    }
+    if name, ok := names[id]; ok {
+        return name
+    }
+    return fmt.Sprintf("#%d", id)
+}
+
+// percentOf returns part as a percentage of whole, to two decimals
+func percentOf(part, whole float64) float64 {
+    if whole == 0 {
+        return 0
+    }
+    return roundCurrency(part / whole * 100)
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "micro-lending-platform/backend/internal/repositories"
+    "time"
+)
+
+// defaultProvisioningSettings are used until rates are saved: a general 1% on current loans,
+// rising with arrears to the full balance once a loan is more than 90 days past due
+var defaultProvisioningSettings = models.ProvisioningSettings{
+    RateCurrent: 0.01,
+    Rate1To7:    0.02,
+    Rate8To30:   0.05,
+    Rate31To60:  0.25,
+    Rate61To90:  0.50,
+    RateOver90:  1,
+}
+
+// provisionBuckets are the days-past-due buckets a provision is worked out in, current loans first
+var provisionBuckets = append([]string{models.ParBucketCurrent}, parBuckets...)
+
+type ProvisioningService struct {
+    provisioningRepo *repositories.ProvisioningRepository
+    ledgerService    *LedgerService
+    reportService    *ReportService
+}
+
+func NewProvisioningService(provisioningRepo *repositories.ProvisioningRepository, ledgerService *LedgerService, reportService *ReportService) *ProvisioningService {
+    return &ProvisioningService{
+        provisioningRepo: provisioningRepo,
+        ledgerService:    ledgerService,
+        reportService:    reportService,
+    }
+}
+
+// GetSettings returns the provisioning rates in force
+func (s *ProvisioningService) GetSettings() (*models.ProvisioningSettings, error) {
+    saved, err := s.provisioningRepo.FindSettings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get provisioning settings: %w", err)
+    }
+    if saved != nil {
+        return saved, nil
+    }
+    defaults := defaultProvisioningSettings
+    return &defaults, nil
+}
+
+// UpdateSettings saves new provisioning rates; they apply from the next run
+func (s *ProvisioningService) UpdateSettings(req *models.ProvisioningSettingsRequest, username string) (*models.ProvisioningSettings, error) {
+    settings, err := s.provisioningRepo.FindSettings()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get provisioning settings: %w", err)
+    }
+    if settings == nil {
+        settings = &models.ProvisioningSettings{}
+    }
+    settings.RateCurrent = req.RateCurrent
+    settings.Rate1To7 = req.Rate1To7
+    settings.Rate8To30 = req.Rate8To30
+    settings.Rate31To60 = req.Rate31To60
+    settings.Rate61To90 = req.Rate61To90
+    settings.RateOver90 = req.RateOver90
+    settings.UpdatedBy = username
+
+    saved, err := s.provisioningRepo.SaveSettings(settings)
+    if err != nil {
+        return nil, fmt.Errorf("failed to save provisioning settings: %w", err)
+    }
+    return saved, nil
+}
+
+// PendingMonths lists the months that have ended since the latest provisioning run and can still be
+// posted to, oldest first, so runs missed while the server was down are made up
+func (s *ProvisioningService) PendingMonths() ([]string, error) {
+    latest, err := s.provisioningRepo.LatestRunMonth()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get provisioning runs: %w", err)
+    }
+    return s.ledgerService.monthsEndedSince(latest)
+}
+
+// RunProvisioning works out the allowance for loan losses at the end of a month that has ended and
+// posts the difference from the ledger's allowance, dated that month-end. Each loan's net balance in
+// the ledger (receivable less unearned interest) is provisioned at the rate for its days past due.
+// Running a month again posts only what changed since.
+func (s *ProvisioningService) RunProvisioning(month string, username string) (*models.ProvisionRun, error) {
+    start, err := time.Parse("2006-01", month)
+    if err != nil {
+        return nil, fmt.Errorf("invalid month, use YYYY-MM")
+    }
+    r := period.Between(start, start.AddDate(0, 1, -1), s.ledgerService.periods)
+    monthEnd := r.LastDay()
+    if !monthEnd.Before(period.Today(s.ledgerService.periods).FirstDay()) {
+        return nil, fmt.Errorf("%s has not ended yet", start.Format("January 2006"))
+    }
+    if err := s.ledgerService.checkOpen(monthEnd); err != nil {
+        return nil, err
+    }
+
+    settings, err := s.GetSettings()
+    if err != nil {
+        return nil, err
+    }
+    if _, err := s.ledgerService.SyncAll(); err != nil {
+        return nil, err
+    }
+    positions, err := s.reportService.portfolioAsOf(monthEnd)
+    if err != nil {
+        return nil, err
+    }
+    before := monthEnd.AddDate(0, 0, 1)
+    balances, err := s.ledgerService.netLoanBalances(before)
+    if err != nil {
+        return nil, err
+    }
+
+    run := &models.ProvisionRun{Month: start.Format("2006-01"), PeriodEnd: monthEnd, RunBy: username}
+    byBucket := make(map[string]*models.ProvisionRunBucket, len(provisionBuckets))
+    for _, bucket := range provisionBuckets {
+        byBucket[bucket] = &models.ProvisionRunBucket{Bucket: bucket, Rate: settings.RateFor(bucket)}
+    }
+    for _, pos := range positions {
+        // Written-off loans have nothing left in the ledger to provision for
+        balance := roundCurrency(balances[pos.loan.ID])
+        if balance <= 0 {
+            continue
+        }
+        bucket := byBucket[parBucket(pos.arrears.DaysPastDue)]
+        bucket.Loans++
+        bucket.NetBalance += balance
+    }
+    for _, name := range provisionBuckets {
+        bucket := byBucket[name]
+        bucket.NetBalance = roundCurrency(bucket.NetBalance)
+        bucket.Required = roundCurrency(bucket.NetBalance * bucket.Rate)
+        run.Loans += bucket.Loans
+        run.NetBalance += bucket.NetBalance
+        run.RequiredAllowance += bucket.Required
+        run.Buckets = append(run.Buckets, *bucket)
+    }
+    run.NetBalance = roundCurrency(run.NetBalance)
+    run.RequiredAllowance = roundCurrency(run.RequiredAllowance)
+
+    allowance, err := s.ledgerService.accountBalance(models.AccountLoanLossAllowance, time.Time{}, before)
+    if err != nil {
+        return nil, err
+    }
+    run.OpeningAllowance = roundCurrency(allowance.Credit - allowance.Debit)
+    run.Adjustment = roundCurrency(run.RequiredAllowance - run.OpeningAllowance)
+
+    if run.Adjustment != 0 {
+        runs, err := s.provisioningRepo.CountRunsForMonth(run.Month)
+        if err != nil {
+            return nil, fmt.Errorf("failed to count provisioning runs: %w", err)
+        }
+        var lines entryLines
+        lines.debit(models.AccountProvisionExpense, run.Adjustment, fmt.Sprintf("Allowance required %.2f", run.RequiredAllowance))
+        lines.credit(models.AccountLoanLossAllowance, run.Adjustment, "")
+        entry, err := s.ledgerService.postSystemEntry(fmt.Sprintf("provision:%s:%d", run.Month, runs+1), &models.JournalEntry{
+            EntryDate:   monthEnd,
+            Source:      models.JournalSourceProvision,
+            Reference:   run.Month,
+            Description: "Loan loss provision for " + start.Format("January 2006"),
+            Lines:       lines,
+        }, username)
+        if err != nil {
+            return nil, err
+        }
+        run.EntryID = &entry.ID
+    }
+
+    saved, err := s.provisioningRepo.CreateRun(run)
+    if err != nil {
+        return nil, fmt.Errorf("failed to save provisioning run: %w", err)
+    }
+    return saved, nil
+}
+
+// GetRuns lists provisioning runs with pagination, newest first
+func (s *ProvisioningService) GetRuns(page, limit int) ([]models.ProvisionRun, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+    runs, total, err := s.provisioningRepo.FindRuns((page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get provisioning runs: %w", err)
+    }
+    return runs, total, nil
+}
+
+// GetMovement reports how the allowance for loan losses moved in each month overlapping a range,
+// oldest first, against the allowance each month's latest run required
+func (s *ProvisioningService) GetMovement(r period.Range) (*models.ProvisionMovement, error) {
+    months := period.MonthsCovering(r, s.ledgerService.periods)
+    names := make([]string, len(months))
+    for i, month := range months {
+        names[i] = month.FirstDay().Format("2006-01")
+    }
+    runs, err := s.provisioningRepo.FindRunsForMonths(names)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get provisioning runs: %w", err)
+    }
+    required := make(map[string]float64, len(runs))
+    for _, run := range runs {
+        required[run.Month] = run.RequiredAllowance
+    }
+
+    report := &models.ProvisionMovement{
+        From:        months[len(months)-1].From(),
+        To:          months[0].To(),
+        Months:      []models.ProvisionMovementLine{},
+        GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+    }
+    for i := len(months) - 1; i >= 0; i-- {
+        first, next := months[i].FirstDay(), months[i].LastDay().AddDate(0, 0, 1)
+        opening, err := s.ledgerService.accountBalance(models.AccountLoanLossAllowance, time.Time{}, first)
+        if err != nil {
+            return nil, err
+        }
+        moved, err := s.ledgerService.accountBalance(models.AccountLoanLossAllowance, first, next)
+        if err != nil {
+            return nil, err
+        }
+        _, run := required[names[i]]
+        line := models.ProvisionMovementLine{
+            Month:    names[i],
+            Opening:  roundCurrency(opening.Credit - opening.Debit),
+            Charged:  roundCurrency(moved.Credit),
+            Released: roundCurrency(moved.Debit),
+            Required: required[names[i]],
+            Run:      run,
+        }
+        line.Closing = roundCurrency(line.Opening + line.Charged - line.Released)
+        if len(report.Months) == 0 {
+            report.Total.Opening = line.Opening
+        }
+        report.Total.Charged += line.Charged
+        report.Total.Released += line.Released
+        report.Total.Closing = line.Closing
+        report.Months = append(report.Months, line)
+    }
+    report.Total.Month = "Total"
+    report.Total.Charged = roundCurrency(report.Total.Charged)
+    report.Total.Released = roundCurrency(report.Total.Released)
+    return report, nil
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/export"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "strconv"
+    "time"
+)
+
+// allBranches is the footer branch for reports that cover the whole portfolio
+const allBranches = "All branches"
+
+// PeriodReportTable lays out the weekly or monthly summary as a two-column table
+func (s *ReportService) PeriodReportTable(title string, data *models.WeeklyReportData, r period.Range) *export.Table {
+    return &export.Table{
+        Title: title,
+        Subtitle: [][2]string{
+            {"Period", r.From() + " to " + r.To()},
+            {"Timezone", r.Location().String()},
+        },
+        Columns: []export.Column{
+            {Header: "Measure", Width: 110, Align: "L"},
+            {Header: "Value", Width: 60, Align: "R"},
+        },
+        Rows: [][]string{
+            {"Payments collected", export.Money(data.WeeklyPaymentTotal)},
+            {"Loans released", export.Money(data.WeeklyReleaseTotal)},
+            {"Net flow", export.Money(data.WeeklyPaymentTotal - data.WeeklyReleaseTotal)},
+            {"Active clients", strconv.FormatInt(data.ActiveClients, 10)},
+            {"Overdue clients", strconv.FormatInt(data.OverdueClients, 10)},
+        },
+        Totals:  []string{"Total clients", strconv.FormatInt(data.TotalClients, 10)},
+        Footer:  reportFooter(allBranches, r.From(), r.To()),
+        Printed: time.Now().In(r.Location()),
+    }
+}
+
+// HistoryTable lays out the historical report, one row per period, newest first
+func (s *ReportService) HistoryTable(history *models.HistoricalReportResponse, opts period.Options) *export.Table {
+    title := "Weekly History"
+    if history.Metadata.PeriodType == "monthly" {
+        title = "Monthly History"
+    }
+
+    table := &export.Table{
+        Title: title,
+        Subtitle: [][2]string{
+            {"Periods", strconv.Itoa(history.Metadata.PeriodsCount)},
+            {"Average payments", export.Money(history.Metadata.AveragePayment)},
+            {"Average releases", export.Money(history.Metadata.AverageRelease)},
+            {"Timezone", history.Metadata.Timezone},
+        },
+        Columns: []export.Column{
+            {Header: "Period", Width: 44, Align: "L"},
+            {Header: "Start", Width: 22, Align: "C"},
+            {Header: "End", Width: 22, Align: "C"},
+            {Header: "Payments", Width: 30, Align: "R"},
+            {Header: "Releases", Width: 30, Align: "R"},
+            {Header: "Net Flow", Width: 30, Align: "R"},
+            {Header: "Active", Width: 18, Align: "R"},
+            {Header: "Overdue", Width: 18, Align: "R"},
+            {Header: "Source", Width: 20, Align: "C"},
+        },
+        Landscape: true,
+        Printed:   time.Now().In(opts.Location),
+    }
+
+    netFlow := 0.0
+    for _, record := range history.Data {
+        netFlow += record.NetFlow
+        table.Rows = append(table.Rows, []string{
+            record.Period,
+            record.StartDate,
+            record.EndDate,
+            export.Money(record.Payments),
+            export.Money(record.Releases),
+            export.Money(record.NetFlow),
+            strconv.FormatInt(record.ActiveClients, 10),
+            strconv.FormatInt(record.OverdueClients, 10),
+            record.Source,
+        })
+    }
+    table.Totals = []string{"Total", "", "", export.Money(history.Metadata.TotalPayments), export.Money(history.Metadata.TotalReleases), export.Money(netFlow), "", "", ""}
+
+    from, to := "", ""
+    if n := len(history.Data); n > 0 {
+        from, to = history.Data[n-1].StartDate, history.Data[0].EndDate
+    }
+    table.Footer = reportFooter(allBranches, from, to)
+    return table
+}
+
+// ParReportTable lays out the PAR report with one section per breakdown and the portfolio as the totals row
+func (s *ReportService) ParReportTable(report *models.ParReport, asOf period.Range) *export.Table {
+    columns := []export.Column{
+        {Header: "Name", Width: 40, Align: "L"},
+        {Header: "Loans", Width: 12, Align: "R"},
+        {Header: "Outstanding", Width: 26, Align: "R"},
+        {Header: "At Risk", Width: 24, Align: "R"},
+        {Header: "PAR1", Width: 14, Align: "R"},
+        {Header: "PAR7", Width: 14, Align: "R"},
+        {Header: "PAR30", Width: 14, Align: "R"},
+    }
+    for _, bucket := range parBuckets {
+        columns = append(columns, export.Column{Header: bucket + " days", Width: 21, Align: "R"})
+    }
+
+    table := &export.Table{
+        Title: "Portfolio at Risk",
+        Subtitle: [][2]string{
+            {"As of", report.AsOf},
+            {"Outstanding", export.Money(report.Portfolio.Outstanding)},
+            {"PAR30", export.Percent(report.Portfolio.Par30)},
+        },
+        Columns:     columns,
+        GroupHeader: "Breakdown",
+        Groups: []export.Group{
+            {Title: "Branch", Rows: parRows(report.ByBranch)},
+            {Title: "Loan officer", Rows: parRows(report.ByOfficer)},
+            {Title: "Product", Rows: parRows(report.ByProduct)},
+        },
+        Totals:    parRow(report.Portfolio),
+        Footer:    reportFooter(allBranches, "", report.AsOf),
+        Printed:   time.Now().In(asOf.Location()),
+        Landscape: true,
+    }
+    return table
+}
+
+// ParLoansTable lays out a PAR drill-down, one row per loan with the list totals
+func (s *ReportService) ParLoansTable(list *models.ParLoanList, filter *models.ParLoanFilter, asOf period.Range) (*export.Table, error) {
+    branch := allBranches
+    if filter.BranchID != nil {
+        branches, err := s.branchNames()
+        if err != nil {
+            return nil, err
+        }
+        branch = groupName(branches, *filter.BranchID)
+    }
+
+    table := &export.Table{
+        Title: "Portfolio at Risk - Loans",
+        Subtitle: [][2]string{
+            {"As of", list.AsOf},
+            {"Loans", strconv.Itoa(list.Count)},
+        },
+        Columns: []export.Column{
+            {Header: "Loan", Width: 26, Align: "L"},
+            {Header: "Client", Width: 40, Align: "L"},
+            {Header: "Branch", Width: 26, Align: "L"},
+            {Header: "Officer", Width: 30, Align: "L"},
+            {Header: "Product", Width: 26, Align: "L"},
+            {Header: "Outstanding", Width: 26, Align: "R"},
+            {Header: "Past Due", Width: 24, Align: "R"},
+            {Header: "DPD", Width: 12, Align: "R"},
+            {Header: "Bucket", Width: 16, Align: "C"},
+            {Header: "Oldest Due", Width: 22, Align: "C"},
+        },
+        Totals:    []string{"Total", "", "", "", "", export.Money(list.Outstanding), export.Money(list.PastDue), "", "", ""},
+        Footer:    reportFooter(branch, "", list.AsOf),
+        Printed:   time.Now().In(asOf.Location()),
+        Landscape: true,
+    }
+    if filter.Bucket != "" {
+        table.Subtitle = append(table.Subtitle, [2]string{"Bucket", filter.Bucket})
+    }
+    if filter.Product != "" {
+        table.Subtitle = append(table.Subtitle, [2]string{"Product", filter.Product})
+    }
+
+    for _, loan := range list.Loans {
+        oldest := ""
+        if loan.OldestDueDate != nil {
+            oldest = loan.OldestDueDate.Format("2006-01-02")
+        }
+        table.Rows = append(table.Rows, []string{
+            loan.ControlNumber,
+            loan.ClientName,
+            loan.BranchName,
+            loan.OfficerName,
+            loan.Product,
+            export.Money(loan.Outstanding),
+            export.Money(loan.AmountPastDue),
+            strconv.Itoa(loan.DaysPastDue),
+            loan.Bucket,
+            oldest,
+        })
+    }
+    return table, nil
+}
+
+// CollectionEfficiencyTable lays out collection efficiency by collector and branch plus the weekly trend,
+// with the portfolio as the totals row
+func (s *ReportService) CollectionEfficiencyTable(report *models.CollectionEfficiencyReport, r period.Range) *export.Table {
+    trend := export.Group{Title: "Week"}
+    for _, week := range report.Trend {
+        trend.Rows = append(trend.Rows, efficiencyRow(models.CollectionEfficiency{
+            Name:              week.Period,
+            DueInPeriod:       week.DueInPeriod,
+            PastDueAtStart:    week.PastDueAtStart,
+            CurrentCollected:  week.CurrentCollected,
+            PastDueCollected:  week.PastDueCollected,
+            AdvanceCollected:  week.AdvanceCollected,
+            TotalCollected:    roundCurrency(week.CurrentCollected + week.PastDueCollected + week.AdvanceCollected),
+            CurrentEfficiency: week.CurrentEfficiency,
+            OverallEfficiency: week.OverallEfficiency,
+        }, false))
+    }
+
+    return &export.Table{
+        Title: "Collection Efficiency",
+        Subtitle: [][2]string{
+            {"Period", report.From + " to " + report.To},
+            {"Current efficiency", export.Percent(report.Portfolio.CurrentEfficiency)},
+            {"Overall efficiency", export.Percent(report.Portfolio.OverallEfficiency)},
+        },
+        Columns: []export.Column{
+            {Header: "Name", Width: 44, Align: "L"},
+            {Header: "Loans", Width: 14, Align: "R"},
+            {Header: "Due", Width: 24, Align: "R"},
+            {Header: "Past Due at Start", Width: 26, Align: "R"},
+            {Header: "Current", Width: 24, Align: "R"},
+            {Header: "Past Due", Width: 24, Align: "R"},
+            {Header: "Advance", Width: 24, Align: "R"},
+            {Header: "Total", Width: 24, Align: "R"},
+            {Header: "Current %", Width: 18, Align: "R"},
+            {Header: "Overall %", Width: 18, Align: "R"},
+        },
+        GroupHeader: "Breakdown",
+        Groups: []export.Group{
+            {Title: "Collector", Rows: efficiencyRows(report.ByCollector)},
+            {Title: "Branch", Rows: efficiencyRows(report.ByBranch)},
+            trend,
+        },
+        Totals:    efficiencyRow(report.Portfolio, true),
+        Footer:    reportFooter(allBranches, report.From, report.To),
+        Printed:   time.Now().In(r.Location()),
+        Landscape: true,
+    }
+}
+
+// OfficerPerformanceTable lays out one row per loan officer with the portfolio as the totals row
+func (s *ReportService) OfficerPerformanceTable(report *models.OfficerPerformanceReport, r period.Range) *export.Table {
+    table := &export.Table{
+        Title: "Loan Officer Performance",
+        Subtitle: [][2]string{
+            {"Period", report.From + " to " + report.To},
+            {"Outstanding", export.Money(report.Portfolio.Outstanding)},
+            {"Disbursed", export.Money(report.Portfolio.Disbursed)},
+        },
+        Columns: []export.Column{
+            {Header: "Officer", Width: 34, Align: "L"},
+            {Header: "Borrowers", Width: 18, Align: "R"},
+            {Header: "Outstanding", Width: 26, Align: "R"},
+            {Header: "Released", Width: 16, Align: "R"},
+            {Header: "Disbursed", Width: 26, Align: "R"},
+            {Header: "New", Width: 12, Align: "R"},
+            {Header: "Repeat", Width: 14, Align: "R"},
+            {Header: "PAR1", Width: 16, Align: "R"},
+            {Header: "PAR30", Width: 16, Align: "R"},
+            {Header: "Current %", Width: 18, Align: "R"},
+            {Header: "Overall %", Width: 18, Align: "R"},
+            {Header: "Dropout %", Width: 18, Align: "R"},
+        },
+        Totals:    officerRow(report.Portfolio),
+        Footer:    reportFooter(allBranches, report.From, report.To),
+        Printed:   time.Now().In(r.Location()),
+        Landscape: true,
+    }
+    for _, officer := range report.Officers {
+        table.Rows = append(table.Rows, officerRow(officer))
+    }
+    return table
+}
+
+// CohortTable lays out one section per cohort with a row for each month on book
+func (s *ReportService) CohortTable(report *models.CohortReport, r period.Range) *export.Table {
+    table := &export.Table{
+        Title: "Cohort Analysis",
+        Subtitle: [][2]string{
+            {"Release months", report.From + " to " + report.To},
+            {"Cohorts", strconv.Itoa(len(report.Cohorts))},
+        },
+        Columns: []export.Column{
+            {Header: "Month on Book", Width: 24, Align: "R"},
+            {Header: "As of", Width: 24, Align: "C"},
+            {Header: "Repaid", Width: 28, Align: "R"},
+            {Header: "Repaid %", Width: 20, Align: "R"},
+            {Header: "Outstanding", Width: 28, Align: "R"},
+            {Header: "PAR30", Width: 18, Align: "R"},
+            {Header: "90+ Loans", Width: 18, Align: "R"},
+            {Header: "Default %", Width: 20, Align: "R"},
+        },
+        GroupHeader: "Cohort",
+        Footer:      reportFooter(allBranches, report.From, report.To),
+        Printed:     time.Now().In(r.Location()),
+    }
+    for _, cohort := range report.Cohorts {
+        group := export.Group{Title: fmt.Sprintf("%s %s (%d loans, %s disbursed, %s written off)",
+            cohort.Cohort, cohort.Product, cohort.Loans, export.Money(cohort.Disbursed), export.Percent(cohort.WrittenOffRate))}
+        for _, month := range cohort.Months {
+            group.Rows = append(group.Rows, []string{
+                strconv.Itoa(month.MonthOnBook),
+                month.AsOf,
+                export.Money(month.Repaid),
+                export.Percent(month.RepaidRate),
+                export.Money(month.Outstanding),
+                export.Percent(month.Par30),
+                strconv.Itoa(month.DefaultLoans),
+                export.Percent(month.DefaultRate),
+            })
+        }
+        table.Groups = append(table.Groups, group)
+    }
+    return table
+}
+
+// CashFlowTable lays out one section per forecast week with a row for each day and the week as its subtotal
+func (s *ReportService) CashFlowTable(report *models.CashFlowForecast, opts period.Options) *export.Table {
+    shortfall := "None"
+    if report.ShortfallDate != "" {
+        shortfall = report.ShortfallDate
+    }
+    table := &export.Table{
+        Title: "Cash Flow Forecast",
+        Subtitle: [][2]string{
+            {"Forecast", report.From + " to " + report.To},
+            {"Efficiency measured", report.LookbackFrom + " to " + report.LookbackTo},
+            {"Opening balance", export.Money(report.OpeningBalance)},
+            {"First shortfall", shortfall},
+        },
+        Columns: []export.Column{
+            {Header: "Day", Width: 30, Align: "L"},
+            {Header: "Scheduled", Width: 26, Align: "R"},
+            {Header: "Expected", Width: 26, Align: "R"},
+            {Header: "Releases", Width: 26, Align: "R"},
+            {Header: "Net Flow", Width: 26, Align: "R"},
+            {Header: "Cash Position", Width: 28, Align: "R"},
+        },
+        GroupHeader: "Week",
+        Totals:      append([]string{""}, cashFlowRow(report.Total)[1:]...),
+        Footer:      reportFooter(allBranches, report.From, report.To),
+        Printed:     time.Now().In(period.Today(opts).Location()),
+    }
+    for _, week := range report.Weekly {
+        group := export.Group{Title: week.Period, Subtotal: cashFlowRow(week)}
+        for _, day := range report.Daily {
+            if day.StartDate >= week.StartDate && day.EndDate <= week.EndDate {
+                group.Rows = append(group.Rows, cashFlowRow(day))
+            }
+        }
+        table.Groups = append(table.Groups, group)
+    }
+    return table
+}
+
+func cashFlowRow(line models.CashFlowLine) []string {
+    return []string{
+        line.Period,
+        export.Money(line.Scheduled),
+        export.Money(line.Expected),
+        export.Money(line.Releases),
+        export.Money(line.NetFlow),
+        export.Money(line.Position),
+    }
+}
+
+// reportFooter names the branch a report covers and its dates
+func reportFooter(branch, from, to string) string {
+    switch {
+    case from != "" && from != to:
+        return fmt.Sprintf("%s | %s to %s", branch, from, to)
+    case to != "":
+        return fmt.Sprintf("%s | As of %s", branch, to)
+    }
+    return branch
+}
+
+func parRows(groups []models.ParSummary) [][]string {
+    rows := make([][]string, len(groups))
+    for i, group := range groups {
+        rows[i] = parRow(group)
+    }
+    return rows
+}
+
+func parRow(summary models.ParSummary) []string {
+    row := []string{
+        summary.Name,
+        strconv.Itoa(summary.Loans),
+        export.Money(summary.Outstanding),
+        export.Money(summary.AtRisk),
+        export.Percent(summary.Par1),
+        export.Percent(summary.Par7),
+        export.Percent(summary.Par30),
+    }
+    for _, bucket := range summary.Buckets {
+        row = append(row, export.Money(bucket.Outstanding))
+    }
+    return row
+}
+
+func efficiencyRows(groups []models.CollectionEfficiency) [][]string {
+    rows := make([][]string, len(groups))
+    for i, group := range groups {
+        rows[i] = efficiencyRow(group, true)
+    }
+    return rows
+}
+
+// efficiencyRow formats one grouping; trend weeks have no loan count
+func efficiencyRow(group models.CollectionEfficiency, withLoans bool) []string {
+    loans := ""
+    if withLoans {
+        loans = strconv.Itoa(group.Loans)
+    }
+    return []string{
+        group.Name,
+        loans,
+        export.Money(group.DueInPeriod),
+        export.Money(group.PastDueAtStart),
+        export.Money(group.CurrentCollected),
+        export.Money(group.PastDueCollected),
+        export.Money(group.AdvanceCollected),
+        export.Money(group.TotalCollected),
+        export.Percent(group.CurrentEfficiency),
+        export.Percent(group.OverallEfficiency),
+    }
+}
+
+func officerRow(officer models.OfficerPerformance) []string {
+    return []string{
+        officer.Name,
+        strconv.Itoa(officer.ActiveBorrowers),
+        export.Money(officer.Outstanding),
+        strconv.Itoa(officer.LoansReleased),
+        export.Money(officer.Disbursed),
+        strconv.Itoa(officer.NewClients),
+        strconv.Itoa(officer.RepeatClients),
+        export.Percent(officer.Par1),
+        export.Percent(officer.Par30),
+        export.Percent(officer.CurrentEfficiency),
+        export.Percent(officer.OverallEfficiency),
+        export.Percent(officer.DropoutRate),
+    }
+}
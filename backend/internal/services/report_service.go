@@ -4,96 +4,63 @@ package services
 import (
 	"fmt"
 	"micro-lending-platform/backend/internal/models"
+	"micro-lending-platform/backend/internal/period"
 	"micro-lending-platform/backend/internal/repositories"
 	"time"
 )
 
 type ReportService struct {
-	repo *repositories.ReportRepository
+	repo            *repositories.ReportRepository
+	loanRepo        *repositories.LoanRepository
+	paymentRepo     *repositories.PaymentRepository
+	chargeRepo      *repositories.LoanChargeRepository
+	userRepo        *repositories.UserRepository
+	snapshotRepo    *repositories.SnapshotRepository
+	calendarService *CalendarService
+	periods         period.Options
 }
 
-func NewReportService(repo *repositories.ReportRepository) *ReportService {
-	return &ReportService{repo: repo}
-}
-
-// GetWeeklyReport returns weekly report data for the current week
-func (s *ReportService) GetWeeklyReport() (*models.WeeklyReportData, error) {
-	now := time.Now().UTC()
-	
-	// Get start of current week (Sunday)
-	startOfWeek := now.AddDate(0, 0, -int(now.Weekday()))
-	startOfWeek = time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, startOfWeek.Location())
-	
-	// Get end of current week (Saturday)
-	endOfWeek := startOfWeek.AddDate(0, 0, 6)
-	endOfWeek = time.Date(endOfWeek.Year(), endOfWeek.Month(), endOfWeek.Day(), 23, 59, 59, 0, endOfWeek.Location())
-
-	// Get payment total for this week
-	payments, err := s.repo.GetPaymentTotalForPeriod(startOfWeek, endOfWeek)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get payment total: %w", err)
-	}
-
-	// Get release total for this week
-	releases, err := s.repo.GetReleaseTotalForPeriod(startOfWeek, endOfWeek)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get release total: %w", err)
-	}
-
-	// Get active clients
-	activeClients, err := s.repo.GetActiveClientsForPeriod(endOfWeek)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get active clients: %w", err)
-	}
-
-	// Get overdue clients
-	overdueClients, err := s.repo.GetOverdueClientsForPeriod(endOfWeek)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get overdue clients: %w", err)
+func NewReportService(repo *repositories.ReportRepository, loanRepo *repositories.LoanRepository, paymentRepo *repositories.PaymentRepository, chargeRepo *repositories.LoanChargeRepository, userRepo *repositories.UserRepository, snapshotRepo *repositories.SnapshotRepository, calendarService *CalendarService, periods period.Options) *ReportService {
+	return &ReportService{
+		repo:            repo,
+		loanRepo:        loanRepo,
+		paymentRepo:     paymentRepo,
+		chargeRepo:      chargeRepo,
+		userRepo:        userRepo,
+		snapshotRepo:    snapshotRepo,
+		calendarService: calendarService,
+		periods:         periods,
 	}
+}
 
-	return &models.WeeklyReportData{
-		WeeklyPaymentTotal:   payments,
-		WeeklyReleaseTotal:   releases,
-		TotalClients:         activeClients + overdueClients,
-		ActiveClients:        activeClients,
-		OverdueClients:       overdueClients,
-		ActivePaymentTotal:   payments,
-		TotalPaymentThisWeek: payments,
-	}, nil
+// PeriodOptions is the configured business timezone and week-start day reports default to
+func (s *ReportService) PeriodOptions() period.Options {
+	return s.periods
 }
 
-// GetMonthlyReport returns monthly report data for the current month
-func (s *ReportService) GetMonthlyReport() (*models.WeeklyReportData, error) {
-	now := time.Now().UTC()
-	
-	// Get start of current month
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	
-	// Get end of current month
-	endOfMonth := startOfMonth.AddDate(0, 1, -1)
-	endOfMonth = time.Date(endOfMonth.Year(), endOfMonth.Month(), endOfMonth.Day(), 23, 59, 59, 0, endOfMonth.Location())
-
-	// Get payment total for this month
-	payments, err := s.repo.GetPaymentTotalForPeriod(startOfMonth, endOfMonth)
+// GetPeriodReport returns payment, release and client totals for a range of business days,
+// such as the current week or month
+func (s *ReportService) GetPeriodReport(r period.Range) (*models.WeeklyReportData, error) {
+	// Get payment total for the period
+	payments, err := s.repo.GetPaymentTotalForPeriod(r.Start(), r.End())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get payment total: %w", err)
 	}
 
-	// Get release total for this month
-	releases, err := s.repo.GetReleaseTotalForPeriod(startOfMonth, endOfMonth)
+	// Get release total for the period
+	releases, err := s.repo.GetReleaseTotalForPeriod(r.Start(), r.End())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get release total: %w", err)
 	}
 
 	// Get active clients
-	activeClients, err := s.repo.GetActiveClientsForPeriod(endOfMonth)
+	activeClients, err := s.repo.GetActiveClientsForPeriod(r.End())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active clients: %w", err)
 	}
 
 	// Get overdue clients
-	overdueClients, err := s.repo.GetOverdueClientsForPeriod(endOfMonth)
+	overdueClients, err := s.repo.GetOverdueClientsForPeriod(r.End())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get overdue clients: %w", err)
 	}
@@ -109,44 +76,16 @@ func (s *ReportService) GetMonthlyReport() (*models.WeeklyReportData, error) {
 	}, nil
 }
 
-// GetHistoricalReport returns historical data for multiple periods
-func (s *ReportService) GetHistoricalReport(periodType string, periods int) (*models.HistoricalReportResponse, error) {
+// GetHistoricalReport returns historical data for the given periods, newest first
+func (s *ReportService) GetHistoricalReport(periodType string, periods []period.Range, opts period.Options) (*models.HistoricalReportResponse, error) {
 	var records []models.HistoricalRecord
-	now := time.Now().UTC()
 	var totalPayments, totalReleases float64
 
-	for i := 0; i < periods; i++ {
-		var startDate, endDate time.Time
-		var periodLabel string
-
+	for _, r := range periods {
+		startDate, endDate := r.Start(), r.End()
+		periodLabel := r.LastDay().Format("January 2006")
 		if periodType == "weekly" {
-			// Calculate weekly period (Sunday to Saturday)
-			daysBack := i * 7
-			baseDate := now.AddDate(0, 0, -daysBack)
-			
-			// Find Sunday (start of week)
-			daysUntilSunday := int(baseDate.Weekday())
-			startDate = baseDate.AddDate(0, 0, -daysUntilSunday)
-			startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
-			
-			// Saturday is 6 days after Sunday
-			endDate = startDate.AddDate(0, 0, 6)
-			endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
-
-			periodLabel = startDate.Format("Jan 02") + " - " + endDate.Format("Jan 02, 2006")
-		} else {
-			// Calculate monthly period (first to last day of month)
-			// Go back i months
-			monthDate := now.AddDate(0, -i, 0)
-			
-			// Set to first day of that month
-			startDate = time.Date(monthDate.Year(), monthDate.Month(), 1, 0, 0, 0, 0, monthDate.Location())
-			
-			// Set to last day of that month
-			endDate = startDate.AddDate(0, 1, -1)
-			endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
-
-			periodLabel = endDate.Format("January 2006")
+			periodLabel = r.FirstDay().Format("Jan 02") + " - " + r.LastDay().Format("Jan 02, 2006")
 		}
 
 		// Get data for this period using repository methods
@@ -160,14 +99,9 @@ func (s *ReportService) GetHistoricalReport(periodType string, periods int) (*mo
 			return nil, fmt.Errorf("failed to get releases for period %s: %w", periodLabel, err)
 		}
 
-		activeClients, err := s.repo.GetActiveClientsForPeriod(endDate)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get active clients for period %s: %w", periodLabel, err)
-		}
-
-		overdueClients, err := s.repo.GetOverdueClientsForPeriod(endDate)
+		activeClients, overdueClients, source, err := s.clientCountsFor(r)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get overdue clients for period %s: %w", periodLabel, err)
+			return nil, fmt.Errorf("failed to get clients for period %s: %w", periodLabel, err)
 		}
 
 		netFlow := payments - releases
@@ -176,13 +110,14 @@ func (s *ReportService) GetHistoricalReport(periodType string, periods int) (*mo
 
 		record := models.HistoricalRecord{
 			Period:         periodLabel,
-			StartDate:      startDate.Format("2006-01-02"),
-			EndDate:        endDate.Format("2006-01-02"),
+			StartDate:      r.From(),
+			EndDate:        r.To(),
 			Payments:       payments,
 			Releases:       releases,
 			ActiveClients:  activeClients,
 			OverdueClients: overdueClients,
 			NetFlow:        netFlow,
+			Source:         source,
 		}
 
 		records = append(records, record)
@@ -198,6 +133,8 @@ func (s *ReportService) GetHistoricalReport(periodType string, periods int) (*mo
 
 	metadata := models.HistoryMetadata{
 		PeriodType:     periodType,
+		Timezone:       opts.Location.String(),
+		WeekStart:      opts.WeekStart.String(),
 		PeriodsCount:   len(records),
 		AveragePayment: avgPayment,
 		AverageRelease: avgRelease,
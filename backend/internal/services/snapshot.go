@@ -0,0 +1,123 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/period"
+    "time"
+)
+
+// Where a historical period's client counts came from
+const (
+    HistorySourceSnapshot = "snapshot" // Frozen at the end of the period
+    HistorySourceLive     = "live"     // Current loan statuses, for the open period or days before snapshots began
+)
+
+// TakeSnapshot records every open loan's balance, status and days past due at the end of a day.
+// Re-running it for the same day replaces that day's snapshot.
+func (s *ReportService) TakeSnapshot(date time.Time) (*models.SnapshotSummary, error) {
+    day := dateOnly(date)
+    positions, err := s.portfolioAsOf(day)
+    if err != nil {
+        return nil, err
+    }
+
+    summary := &models.SnapshotSummary{SnapshotDate: day.Format("2006-01-02")}
+    snapshots := make([]models.PortfolioSnapshot, 0, len(positions))
+    for _, pos := range positions {
+        // Status comes from the schedule as of that day, not from whatever the loan says now
+        status := models.LoanStatusActive
+        if pos.loan.Status == models.LoanStatusDefault {
+            status = models.LoanStatusDefault
+        } else if pos.arrears.AmountPastDue > 0 {
+            status = models.LoanStatusOverdue
+        }
+
+        snapshots = append(snapshots, models.PortfolioSnapshot{
+            SnapshotDate:  day,
+            LoanID:        pos.loan.ID,
+            ClientID:      pos.loan.ClientID,
+            BranchID:      pos.loan.BranchID,
+            CollectorID:   pos.loan.CollectorID,
+            Status:        status,
+            Outstanding:   pos.outstanding,
+            AmountPastDue: pos.arrears.AmountPastDue,
+            DaysPastDue:   pos.arrears.DaysPastDue,
+        })
+        summary.Loans++
+        summary.Outstanding += pos.outstanding
+        summary.AmountPastDue += pos.arrears.AmountPastDue
+    }
+
+    if err := s.snapshotRepo.ReplaceForDate(day, snapshots); err != nil {
+        return nil, fmt.Errorf("failed to save snapshot: %w", err)
+    }
+    summary.Outstanding = roundCurrency(summary.Outstanding)
+    summary.AmountPastDue = roundCurrency(summary.AmountPastDue)
+    return summary, nil
+}
+
+// CatchUpSnapshots takes a snapshot for every day after the latest one up to and including a day,
+// oldest first, filling in days missed while the server was down. With none taken yet it takes that day.
+func (s *ReportService) CatchUpSnapshots(through time.Time) ([]models.SnapshotSummary, error) {
+    through = dateOnly(through)
+    latest, err := s.snapshotRepo.LatestDateBetween(time.Time{}, through)
+    if err != nil {
+        return nil, fmt.Errorf("failed to find snapshot: %w", err)
+    }
+    day := through
+    if latest != nil {
+        day = dateOnly(*latest).AddDate(0, 0, 1)
+    }
+
+    var summaries []models.SnapshotSummary
+    for ; !day.After(through); day = day.AddDate(0, 0, 1) {
+        summary, err := s.TakeSnapshot(day)
+        if err != nil {
+            return summaries, err
+        }
+        summaries = append(summaries, *summary)
+    }
+    return summaries, nil
+}
+
+// GetSnapshots lists the most recent snapshot days
+func (s *ReportService) GetSnapshots(limit int) ([]models.SnapshotSummary, error) {
+    if limit < 1 || limit > 366 {
+        limit = 30
+    }
+    summaries, err := s.snapshotRepo.Summaries(limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get snapshots: %w", err)
+    }
+    return summaries, nil
+}
+
+// clientCountsFor counts active and overdue clients at the end of a period. A finished period reads
+// its last snapshot so its figures stay frozen; the open period, and periods from before snapshots
+// began, fall back to current loan statuses.
+func (s *ReportService) clientCountsFor(r period.Range) (active, overdue int64, source string, err error) {
+    if !r.Next().After(time.Now()) {
+        snapshotDate, err := s.snapshotRepo.LatestDateBetween(r.FirstDay(), r.LastDay())
+        if err != nil {
+            return 0, 0, "", fmt.Errorf("failed to find snapshot: %w", err)
+        }
+        if snapshotDate != nil {
+            if active, err = s.snapshotRepo.CountClientsByStatus(*snapshotDate, []models.LoanStatus{models.LoanStatusActive}); err != nil {
+                return 0, 0, "", err
+            }
+            if overdue, err = s.snapshotRepo.CountClientsByStatus(*snapshotDate, []models.LoanStatus{models.LoanStatusOverdue, models.LoanStatusDefault}); err != nil {
+                return 0, 0, "", err
+            }
+            return active, overdue, HistorySourceSnapshot, nil
+        }
+    }
+
+    if active, err = s.repo.GetActiveClientsForPeriod(r.End()); err != nil {
+        return 0, 0, "", err
+    }
+    if overdue, err = s.repo.GetOverdueClientsForPeriod(r.End()); err != nil {
+        return 0, 0, "", err
+    }
+    return active, overdue, HistorySourceLive, nil
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+    "fmt"
+    "micro-lending-platform/backend/internal/export"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "sort"
+    "strings"
+    "time"
+)
+
+type StatementService struct {
+    clientRepo      *repositories.ClientRepository
+    loanRepo        *repositories.LoanRepository
+    paymentRepo     *repositories.PaymentRepository
+    chargeRepo      *repositories.LoanChargeRepository
+    calendarService *CalendarService
+}
+
+func NewStatementService(clientRepo *repositories.ClientRepository, loanRepo *repositories.LoanRepository, paymentRepo *repositories.PaymentRepository, chargeRepo *repositories.LoanChargeRepository, calendarService *CalendarService) *StatementService {
+    return &StatementService{
+        clientRepo:      clientRepo,
+        loanRepo:        loanRepo,
+        paymentRepo:     paymentRepo,
+        chargeRepo:      chargeRepo,
+        calendarService: calendarService,
+    }
+}
+
+// statementEntryOrder keeps same-day entries in a natural reading order
+var statementEntryOrder = map[string]int{
+    models.StatementEntryRelease:     0,
+    models.StatementEntryInstallment: 1,
+    models.StatementEntryCharge:      2,
+    models.StatementEntryPayment:     3,
+    models.StatementEntryReversal:    4,
+}
+
+// GetClientStatement builds a client's statement of account for a date range.
+// Empty from starts at the first release; empty to ends today.
+func (s *StatementService) GetClientStatement(clientID uint, from, to string) (*models.ClientStatement, error) {
+    client, err := s.clientRepo.FindByID(clientID)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("client not found")
+        }
+        return nil, fmt.Errorf("failed to get client: %w", err)
+    }
+
+    var fromDate time.Time
+    if from != "" {
+        if fromDate, err = time.Parse("2006-01-02", from); err != nil {
+            return nil, fmt.Errorf("invalid from date: %w", err)
+        }
+    }
+    toDate := time.Now()
+    if to != "" {
+        if toDate, err = time.Parse("2006-01-02", to); err != nil {
+            return nil, fmt.Errorf("invalid to date: %w", err)
+        }
+    }
+    // Include everything that happened on the last day
+    endOfTo := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 0, toDate.Location())
+    if !fromDate.IsZero() && fromDate.After(endOfTo) {
+        return nil, fmt.Errorf("from date must not be after to date")
+    }
+
+    loans, err := s.loanRepo.FindByClientID(clientID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    entries, err := s.buildEntries(loans)
+    if err != nil {
+        return nil, err
+    }
+
+    statement := &models.ClientStatement{
+        ClientID:      client.ID,
+        ClientName:    strings.TrimSpace(client.FirstName + " " + client.LastName),
+        ControlNumber: client.ControlNumber,
+        From:          from,
+        To:            endOfTo.Format("2006-01-02"),
+        Entries:       []models.StatementEntry{},
+    }
+
+    balance := 0.0
+    for _, entry := range entries {
+        if entry.Date.After(endOfTo) {
+            break
+        }
+        balance = roundCurrency(balance + entry.Debit - entry.Credit)
+        entry.Balance = balance
+
+        // Activity before the period is carried forward as the opening balance
+        if !fromDate.IsZero() && entry.Date.Before(fromDate) {
+            statement.OpeningBalance = balance
+            continue
+        }
+
+        statement.TotalDebits += entry.Debit
+        statement.TotalCredits += entry.Credit
+        statement.Entries = append(statement.Entries, entry)
+    }
+    statement.TotalDebits = roundCurrency(statement.TotalDebits)
+    statement.TotalCredits = roundCurrency(statement.TotalCredits)
+    statement.ClosingBalance = balance
+
+    return statement, nil
+}
+
+// buildEntries collects releases, installments, charges, payments and reversals in date order
+func (s *StatementService) buildEntries(loans []models.Loan) ([]models.StatementEntry, error) {
+    loanIDs := make([]uint, 0, len(loans))
+    controlNumbers := make(map[uint]string, len(loans))
+    var entries []models.StatementEntry
+
+    for i := range loans {
+        loan := &loans[i]
+        loanIDs = append(loanIDs, loan.ID)
+        controlNumbers[loan.ID] = loan.ControlNumber
+
+        releaseDate := loan.DateOfRelease
+        if releaseDate.IsZero() {
+            releaseDate = loan.CreatedAt
+        }
+        entries = append(entries, models.StatementEntry{
+            Date:              releaseDate,
+            LoanID:            loan.ID,
+            LoanControlNumber: loan.ControlNumber,
+            EntryType:         models.StatementEntryRelease,
+            Description:       fmt.Sprintf("Loan released, net proceeds %s", export.Money(loan.AmountRelease)),
+            Debit:             loan.TotalAmount,
+        })
+
+        schedule, err := s.calendarService.ScheduleForLoan(loan)
+        if err != nil {
+            return nil, err
+        }
+        for _, inst := range schedule {
+            entries = append(entries, models.StatementEntry{
+                Date:              inst.DueDate,
+                LoanID:            loan.ID,
+                LoanControlNumber: loan.ControlNumber,
+                EntryType:         models.StatementEntryInstallment,
+                Description:       fmt.Sprintf("Installment %d of %d due", inst.Number, loan.PaymentPeriodWeeks),
+                AmountDue:         inst.AmountDue,
+            })
+        }
+    }
+
+    charges, err := s.chargeRepo.FindByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get charges: %w", err)
+    }
+    for _, charge := range charges {
+        description := string(charge.ChargeType)
+        if charge.Description != "" {
+            description += ": " + charge.Description
+        }
+        entries = append(entries, models.StatementEntry{
+            Date:              charge.ChargeDate,
+            LoanID:            charge.LoanID,
+            LoanControlNumber: controlNumbers[charge.LoanID],
+            EntryType:         models.StatementEntryCharge,
+            Description:       description,
+            Debit:             charge.Amount,
+        })
+    }
+
+    payments, err := s.paymentRepo.FindByLoanIDsWithDeleted(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+    for _, payment := range payments {
+        paidOn := payment.PaymentDate
+        if paidOn.IsZero() {
+            paidOn = payment.CreatedAt
+        }
+        description := fmt.Sprintf("Payment for installment %d", payment.WeekNumber)
+        if payment.IsPartial {
+            description = fmt.Sprintf("Partial payment for installment %d", payment.WeekNumber)
+        }
+        if payment.PaymentMethod != "" {
+            description += " (" + payment.PaymentMethod + ")"
+        }
+        entries = append(entries, models.StatementEntry{
+            Date:              paidOn,
+            LoanID:            payment.LoanID,
+            LoanControlNumber: controlNumbers[payment.LoanID],
+            EntryType:         models.StatementEntryPayment,
+            Description:       description,
+            Credit:            payment.AmountPaid,
+        })
+
+        // Deleted payments stay on the statement, offset by a reversal on the day they were removed
+        if payment.DeletedAt.Valid {
+            entries = append(entries, models.StatementEntry{
+                Date:              payment.DeletedAt.Time,
+                LoanID:            payment.LoanID,
+                LoanControlNumber: controlNumbers[payment.LoanID],
+                EntryType:         models.StatementEntryReversal,
+                Description:       fmt.Sprintf("Reversal of payment #%d", payment.ID),
+                Debit:             payment.AmountPaid,
+            })
+        }
+    }
+
+    sort.SliceStable(entries, func(i, j int) bool {
+        di := dateOnly(entries[i].Date)
+        dj := dateOnly(entries[j].Date)
+        if !di.Equal(dj) {
+            return di.Before(dj)
+        }
+        return statementEntryOrder[entries[i].EntryType] < statementEntryOrder[entries[j].EntryType]
+    })
+
+    return entries, nil
+}
+
+// StatementTable lays out a statement for CSV and PDF export
+func (s *StatementService) StatementTable(statement *models.ClientStatement) *export.Table {
+    from := statement.From
+    if from == "" {
+        from = "Beginning"
+    }
+
+    table := &export.Table{
+        Title: "Statement of Account",
+        Subtitle: [][2]string{
+            {"Client", statement.ClientName},
+            {"Client Control No.", statement.ControlNumber},
+            {"Period", from + " to " + statement.To},
+            {"Opening Balance", export.Money(statement.OpeningBalance)},
+            {"Closing Balance", export.Money(statement.ClosingBalance)},
+        },
+        Columns: []export.Column{
+            {Header: "Date", Width: 22, Align: "C"},
+            {Header: "Loan", Width: 32, Align: "L"},
+            {Header: "Type", Width: 26, Align: "L"},
+            {Header: "Description", Width: 70, Align: "L"},
+            {Header: "Amount Due", Width: 24, Align: "R"},
+            {Header: "Debit", Width: 24, Align: "R"},
+            {Header: "Credit", Width: 24, Align: "R"},
+            {Header: "Balance", Width: 26, Align: "R"},
+        },
+        Totals:    []string{"", "", "", "Totals", "", export.Money(statement.TotalDebits), export.Money(statement.TotalCredits), export.Money(statement.ClosingBalance)},
+        Footer:    "Client " + statement.ControlNumber,
+        Landscape: true,
+    }
+
+    table.Rows = append(table.Rows, []string{"", "", "", "Balance brought forward", "", "", "", export.Money(statement.OpeningBalance)})
+    for _, entry := range statement.Entries {
+        table.Rows = append(table.Rows, []string{
+            entry.Date.Format("2006-01-02"),
+            entry.LoanControlNumber,
+            entry.EntryType,
+            entry.Description,
+            blankIfZero(entry.AmountDue),
+            blankIfZero(entry.Debit),
+            blankIfZero(entry.Credit),
+            export.Money(entry.Balance),
+        })
+    }
+    return table
+}
+
+// dateOnly truncates a timestamp to its calendar day
+func dateOnly(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// blankIfZero formats an amount, leaving zero cells empty for readability
+func blankIfZero(amount float64) string {
+    if amount == 0 {
+        return ""
+    }
+    return export.Money(amount)
+}
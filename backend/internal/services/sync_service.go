@@ -0,0 +1,212 @@
+package services
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "time"
+)
+
+type SyncService struct {
+    loanRepo        *repositories.LoanRepository
+    paymentRepo     *repositories.PaymentRepository
+    userRepo        *repositories.UserRepository
+    calendarService *CalendarService
+    paymentService  *PaymentService
+}
+
+func NewSyncService(loanRepo *repositories.LoanRepository, paymentRepo *repositories.PaymentRepository, userRepo *repositories.UserRepository, calendarService *CalendarService, paymentService *PaymentService) *SyncService {
+    return &SyncService{
+        loanRepo:        loanRepo,
+        paymentRepo:     paymentRepo,
+        userRepo:        userRepo,
+        calendarService: calendarService,
+        paymentService:  paymentService,
+    }
+}
+
+// syncConflict is a pushed payment the server refused, with a machine-readable code
+type syncConflict struct {
+    code   string
+    reason string
+}
+
+func (c *syncConflict) Error() string {
+    return c.reason
+}
+
+// GetSnapshot returns a collector's clients, open loans and schedules for offline use
+func (s *SyncService) GetSnapshot(collectorID uint) (*models.SyncSnapshot, error) {
+    user, err := s.userRepo.FindByID(collectorID)
+    if err != nil {
+        return nil, fmt.Errorf("collector not found")
+    }
+
+    now := time.Now()
+    loans, err := s.loanRepo.FindByCollector(user.ID, []models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue})
+    if err != nil {
+        return nil, fmt.Errorf("failed to get loans: %w", err)
+    }
+
+    loanIDs := make([]uint, len(loans))
+    for i, loan := range loans {
+        loanIDs[i] = loan.ID
+    }
+    paid, err := s.paymentRepo.SumPaidByLoanIDs(loanIDs)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get payments: %w", err)
+    }
+
+    snapshot := &models.SyncSnapshot{
+        CollectorID:   user.ID,
+        CollectorName: user.Username,
+        GeneratedAt:   now,
+        Clients:       []models.Client{},
+        Loans:         make([]models.SyncLoan, 0, len(loans)),
+    }
+    seenClients := make(map[uint]bool)
+
+    for i := range loans {
+        loan := &loans[i]
+        schedule, err := s.calendarService.ScheduleForLoan(loan)
+        if err != nil {
+            return nil, err
+        }
+        arrears := computeArrears(schedule, paid[loan.ID], now)
+        weekBalance, err := s.paymentService.CalculateRemainingBalance(loan.ID, loan.PaidWeeks+1)
+        if err != nil {
+            return nil, fmt.Errorf("failed to get current balance: %w", err)
+        }
+
+        if !seenClients[loan.ClientID] {
+            seenClients[loan.ClientID] = true
+            snapshot.Clients = append(snapshot.Clients, loan.Client)
+        }
+
+        // Clients are listed once above rather than inside every loan
+        synced := *loan
+        synced.Client = models.Client{}
+        snapshot.Loans = append(snapshot.Loans, models.SyncLoan{
+            Loan:               synced,
+            ScheduleVersion:    scheduleVersion(loan),
+            Schedule:           schedule,
+            AmountPaid:         roundCurrency(paid[loan.ID]),
+            CurrentWeekBalance: roundCurrency(weekBalance),
+            AmountPastDue:      arrears.AmountPastDue,
+            DaysPastDue:        arrears.DaysPastDue,
+        })
+    }
+    return snapshot, nil
+}
+
+// PushPayments applies payments recorded offline. Each is applied on its own, so one conflict
+// does not hold back the rest, and a UUID seen before is reported as a duplicate instead of
+// being posted twice, which makes retrying a push after a dropped connection safe.
+func (s *SyncService) PushPayments(collectorID uint, isAdmin bool, req *models.SyncPushRequest) (*models.SyncPushResult, error) {
+    result := &models.SyncPushResult{
+        SyncedAt:  time.Now(),
+        Results:   make([]models.SyncPaymentResult, 0, len(req.Payments)),
+        Conflicts: []models.SyncPaymentResult{},
+    }
+
+    for _, item := range req.Payments {
+        outcome, err := s.applySyncPayment(collectorID, isAdmin, item)
+        if err != nil {
+            return nil, err
+        }
+
+        switch outcome.Status {
+        case models.SyncStatusApplied:
+            result.Applied++
+        case models.SyncStatusDuplicate:
+            result.Duplicates++
+        case models.SyncStatusConflict:
+            result.Conflicts = append(result.Conflicts, outcome)
+        }
+        result.Results = append(result.Results, outcome)
+    }
+    return result, nil
+}
+
+// applySyncPayment posts one offline payment; only database failures are returned as errors
+func (s *SyncService) applySyncPayment(collectorID uint, isAdmin bool, item models.SyncPayment) (models.SyncPaymentResult, error) {
+    outcome := models.SyncPaymentResult{ID: item.ID, LoanID: item.LoanID}
+
+    method := item.PaymentMethod
+    if method == "" {
+        method = "Cash"
+    }
+    syncID := item.ID
+
+    err := s.paymentService.InTransaction(func(tx *PaymentService) error {
+        existing, err := tx.paymentRepo.FindBySyncID(item.ID)
+        if err != nil {
+            return err
+        }
+        if existing != nil {
+            if existing.LoanID != item.LoanID || roundCurrency(existing.AmountPaid) != roundCurrency(item.AmountPaid) {
+                return &syncConflict{models.SyncConflictIDReused, "payment ID was already used for a different payment"}
+            }
+            outcome.Status = models.SyncStatusDuplicate
+            outcome.PaymentID = existing.ID
+            outcome.WeekNumber = existing.WeekNumber
+            return nil
+        }
+
+        loan, err := tx.loanRepo.FindByID(item.LoanID)
+        if err != nil {
+            return &syncConflict{models.SyncConflictLoanNotFound, "loan not found"}
+        }
+        if !isAdmin && (loan.CollectorID == nil || *loan.CollectorID != collectorID) {
+            return &syncConflict{models.SyncConflictNotAssigned, "loan is no longer assigned to this collector"}
+        }
+        if item.ScheduleVersion != "" && item.ScheduleVersion != scheduleVersion(loan) {
+            return &syncConflict{models.SyncConflictLoanChanged, "loan terms changed since the device's snapshot"}
+        }
+        if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusOverdue {
+            return &syncConflict{models.SyncConflictLoanClosed, fmt.Sprintf("loan is %s", loan.Status)}
+        }
+        if roundCurrency(item.AmountPaid) > roundCurrency(loan.OutstandingBalance) {
+            return &syncConflict{models.SyncConflictOverpayment, fmt.Sprintf("amount exceeds the outstanding balance of %.2f", loan.OutstandingBalance)}
+        }
+
+        payment, err := tx.postCollectedAmount(loan, item.AmountPaid, item.PaymentDate, method, &syncID, &collectorID)
+        if err != nil {
+            return err
+        }
+        outcome.Status = models.SyncStatusApplied
+        outcome.PaymentID = payment.ID
+        outcome.WeekNumber = payment.WeekNumber
+        return nil
+    })
+
+    if conflict, ok := err.(*syncConflict); ok {
+        outcome.Status = models.SyncStatusConflict
+        outcome.Conflict = conflict.code
+        outcome.Reason = conflict.reason
+        return outcome, nil
+    }
+    if err != nil {
+        // A concurrent push of the same payment wins the unique sync ID; report it as a duplicate
+        if existing, findErr := s.paymentRepo.FindBySyncID(item.ID); findErr == nil && existing != nil {
+            outcome.Status = models.SyncStatusDuplicate
+            outcome.PaymentID = existing.ID
+            outcome.WeekNumber = existing.WeekNumber
+            return outcome, nil
+        }
+        return outcome, fmt.Errorf("failed to apply payment %s: %w", item.ID, err)
+    }
+    return outcome, nil
+}
+
+// scheduleVersion fingerprints the loan terms a device's schedule was built from.
+// Payments do not change it; restructuring, re-pricing or a new release date does.
+func scheduleVersion(loan *models.Loan) string {
+    terms := fmt.Sprintf("%d|%s|%.2f|%.2f|%d|%s|%d",
+        loan.ID, loan.DateOfRelease.Format("2006-01-02"), loan.TotalAmount, loan.Ammortization,
+        loan.Terms, loan.Mode, loan.PaymentPeriodWeeks)
+    sum := sha256.Sum256([]byte(terms))
+    return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "micro-lending-platform/backend/internal/models"
+    "micro-lending-platform/backend/internal/repositories"
+    "strconv"
+    "strings"
+    "time"
+)
+
+var (
+    // ErrUnknownWalletProvider is returned for webhooks from a provider without a configured secret
+    ErrUnknownWalletProvider = errors.New("unknown wallet provider")
+    // ErrInvalidWebhookSignature is returned when a webhook's signature or timestamp does not check out
+    ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+    // ErrInvalidWalletPayload is returned for a notification body the provider should not send again as is
+    ErrInvalidWalletPayload = errors.New("invalid payload")
+)
+
+// walletWebhookTolerance bounds how old a signed webhook may be, so captured requests cannot be replayed later
+const walletWebhookTolerance = 5 * time.Minute
+
+type WalletService struct {
+    walletRepo     *repositories.WalletRepository
+    loanRepo       *repositories.LoanRepository
+    paymentService *PaymentService
+    secrets        map[string]string
+}
+
+func NewWalletService(walletRepo *repositories.WalletRepository, loanRepo *repositories.LoanRepository, paymentService *PaymentService, secrets map[string]string) *WalletService {
+    return &WalletService{
+        walletRepo:     walletRepo,
+        loanRepo:       loanRepo,
+        paymentService: paymentService,
+        secrets:        secrets,
+    }
+}
+
+// walletReview holds a notification back for manual review instead of posting it
+type walletReview struct {
+    status string
+    reason string
+}
+
+func (r *walletReview) Error() string {
+    return r.reason
+}
+
+// SignWalletWebhook computes the signature a provider sends with a webhook: the hex HMAC-SHA256
+// of "<timestamp>.<body>" under the provider's secret
+func SignWalletWebhook(secret, timestamp string, body []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(timestamp + "."))
+    mac.Write(body)
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a webhook's signature against the provider's secret and rejects stale timestamps
+func (s *WalletService) VerifySignature(provider, timestamp, signature string, body []byte) error {
+    secret, ok := s.secrets[strings.ToLower(provider)]
+    if !ok || secret == "" {
+        return ErrUnknownWalletProvider
+    }
+
+    unix, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil {
+        return fmt.Errorf("%w: missing or invalid timestamp", ErrInvalidWebhookSignature)
+    }
+    age := time.Since(time.Unix(unix, 0))
+    if age > walletWebhookTolerance || age < -walletWebhookTolerance {
+        return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidWebhookSignature)
+    }
+
+    expected := SignWalletWebhook(secret, timestamp, body)
+    if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256="))) {
+        return ErrInvalidWebhookSignature
+    }
+    return nil
+}
+
+// ReceiveNotification stores a verified notification and posts it to the loan whose control number
+// it references. Redeliveries of an event return the stored notification without posting again, except
+// that one whose posting failed is tried again, and one whose processing was cut short is queued for review
+// since it may have been posted.
+func (s *WalletService) ReceiveNotification(provider string, body []byte) (*models.WalletNotification, error) {
+    provider = strings.ToLower(provider)
+
+    var payload models.WalletNotificationPayload
+    if err := json.Unmarshal(body, &payload); err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidWalletPayload, err)
+    }
+    if strings.TrimSpace(payload.EventID) == "" {
+        return nil, fmt.Errorf("%w: event_id is required", ErrInvalidWalletPayload)
+    }
+    if payload.Amount <= 0 {
+        return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidWalletPayload)
+    }
+
+    existing, err := s.walletRepo.FindByEvent(provider, payload.EventID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to check notification: %w", err)
+    }
+    if existing != nil {
+        return s.redelivered(existing)
+    }
+
+    paidAt := time.Now()
+    if payload.PaidAt != "" {
+        if paidAt, err = time.Parse(time.RFC3339, payload.PaidAt); err != nil {
+            return nil, fmt.Errorf("%w: paid_at must be RFC 3339: %v", ErrInvalidWalletPayload, err)
+        }
+    }
+
+    notification := &models.WalletNotification{
+        Provider:     provider,
+        EventID:      payload.EventID,
+        Reference:    strings.TrimSpace(payload.Reference),
+        Amount:       roundCurrency(payload.Amount),
+        Currency:     strings.ToUpper(payload.Currency),
+        PaidAt:       paidAt,
+        PayerName:    payload.PayerName,
+        PayerAccount: payload.PayerAccount,
+        Status:       models.WalletNotificationReceived,
+        RawPayload:   string(body),
+    }
+    if _, err := s.walletRepo.Create(notification); err != nil {
+        // A concurrent delivery of the same event got there first
+        if existing, findErr := s.walletRepo.FindByEvent(provider, payload.EventID); findErr == nil && existing != nil {
+            return existing, nil
+        }
+        return nil, fmt.Errorf("failed to store notification: %w", err)
+    }
+
+    if err := s.processNotification(notification); err != nil {
+        return nil, err
+    }
+    return notification, nil
+}
+
+// redelivered handles an event the provider sent again
+func (s *WalletService) redelivered(notification *models.WalletNotification) (*models.WalletNotification, error) {
+    switch notification.Status {
+    case models.WalletNotificationFailed:
+        notification.LoanID = nil
+        notification.PaymentID = nil
+        if err := s.processNotification(notification); err != nil {
+            return nil, err
+        }
+    case models.WalletNotificationReceived:
+        // The payment may have been saved before the status was, so a reviewer checks the loan
+        notification.Status = models.WalletNotificationUnmatched
+        notification.Reason = "processing was interrupted, check the loan's payments before posting"
+        if _, err := s.walletRepo.Update(notification); err != nil {
+            return nil, fmt.Errorf("failed to update notification: %w", err)
+        }
+    }
+    return notification, nil
+}
+
+// processNotification matches a stored notification to its loan and posts it, or queues it for review.
+// When posting fails the notification is marked failed, so it is listed for review and tried again
+// when the provider redelivers it.
+func (s *WalletService) processNotification(notification *models.WalletNotification) error {
+    duplicate, err := s.walletRepo.FindPostedMatch(notification.Provider, notification.Reference, notification.Amount, notification.PaidAt, notification.ID)
+    if err != nil {
+        return s.failNotification(notification, fmt.Errorf("failed to check for duplicates: %w", err))
+    }
+
+    err = s.paymentService.InTransaction(func(tx *PaymentService) error {
+        if notification.Currency != "" && notification.Currency != "PHP" {
+            return &walletReview{models.WalletNotificationUnmatched, fmt.Sprintf("unsupported currency %s", notification.Currency)}
+        }
+        if notification.Reference == "" {
+            return &walletReview{models.WalletNotificationUnmatched, "no loan reference given"}
+        }
+        loan, err := tx.loanRepo.FindByControlNumber(notification.Reference)
+        if err != nil {
+            return &walletReview{models.WalletNotificationUnmatched, fmt.Sprintf("no loan with control number %s", notification.Reference)}
+        }
+        notification.LoanID = &loan.ID
+
+        if duplicate != nil {
+            return &walletReview{models.WalletNotificationDuplicate, fmt.Sprintf("same payment already posted from notification %d", duplicate.ID)}
+        }
+        if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusOverdue {
+            return &walletReview{models.WalletNotificationUnmatched, fmt.Sprintf("loan is %s", loan.Status)}
+        }
+        if notification.Amount > roundCurrency(loan.OutstandingBalance) {
+            return &walletReview{models.WalletNotificationUnmatched, fmt.Sprintf("amount exceeds the outstanding balance of %.2f", loan.OutstandingBalance)}
+        }
+
+        return s.postNotification(tx, notification, loan)
+    })
+
+    var review *walletReview
+    if errors.As(err, &review) {
+        notification.Status = review.status
+        notification.Reason = review.reason
+        err = nil
+    } else if err == nil {
+        notification.Status = models.WalletNotificationPosted
+        notification.Reason = ""
+    }
+    if err != nil {
+        return s.failNotification(notification, fmt.Errorf("failed to post notification: %w", err))
+    }
+
+    if _, err := s.walletRepo.Update(notification); err != nil {
+        return fmt.Errorf("failed to update notification: %w", err)
+    }
+    return nil
+}
+
+// failNotification records why a notification could not be posted and returns the error
+func (s *WalletService) failNotification(notification *models.WalletNotification, err error) error {
+    notification.Status = models.WalletNotificationFailed
+    notification.Reason = err.Error()
+    notification.LoanID = nil
+    notification.PaymentID = nil
+    if _, updateErr := s.walletRepo.Update(notification); updateErr != nil {
+        return fmt.Errorf("%w (and failed to update notification: %v)", err, updateErr)
+    }
+    return err
+}
+
+// postNotification records the notification's amount as a payment on the loan
+func (s *WalletService) postNotification(tx *PaymentService, notification *models.WalletNotification, loan *models.Loan) error {
+    payment, err := tx.postCollectedAmount(loan, notification.Amount, notification.PaidAt.Format("2006-01-02"), notification.Provider, nil, nil)
+    if err != nil {
+        return err
+    }
+    notification.LoanID = &loan.ID
+    notification.PaymentID = &payment.ID
+    return nil
+}
+
+// GetReviewQueue lists notifications by status; with no status it returns everything awaiting review
+func (s *WalletService) GetReviewQueue(status string, page, limit int) ([]models.WalletNotification, int64, error) {
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+
+    statuses := []string{models.WalletNotificationUnmatched, models.WalletNotificationDuplicate, models.WalletNotificationFailed}
+    if status != "" {
+        statuses = []string{status}
+    }
+
+    notifications, total, err := s.walletRepo.FindByStatuses(statuses, (page-1)*limit, limit)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+    }
+    return notifications, total, nil
+}
+
+// ResolveNotification posts a queued notification to the loan a reviewer chose
+func (s *WalletService) ResolveNotification(id uint, req *models.WalletResolveRequest, username string) (*models.WalletNotification, error) {
+    notification, err := s.findQueued(id)
+    if err != nil {
+        return nil, err
+    }
+    // Claim it first, so a second reviewer resolving it at the same time cannot post it again
+    queued := *notification
+    if err := s.claim(notification, models.WalletNotificationResolved, username, req.Notes); err != nil {
+        return nil, err
+    }
+
+    err = s.paymentService.InTransaction(func(tx *PaymentService) error {
+        loan, err := tx.loanRepo.FindByID(req.LoanID)
+        if err != nil {
+            return fmt.Errorf("loan not found")
+        }
+        if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusOverdue {
+            return fmt.Errorf("loan is %s", loan.Status)
+        }
+        return s.postNotification(tx, notification, loan)
+    })
+    if err != nil {
+        // Nothing was posted; put it back in the queue
+        if _, updateErr := s.walletRepo.Update(&queued); updateErr != nil {
+            return nil, fmt.Errorf("%w (and failed to return notification to the queue: %v)", err, updateErr)
+        }
+        return nil, err
+    }
+    return s.walletRepo.Update(notification)
+}
+
+// DismissNotification closes a queued notification without posting it
+func (s *WalletService) DismissNotification(id uint, req *models.WalletDismissRequest, username string) (*models.WalletNotification, error) {
+    notification, err := s.findQueued(id)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := s.claim(notification, models.WalletNotificationDismissed, username, req.Notes); err != nil {
+        return nil, err
+    }
+    return notification, nil
+}
+
+// findQueued loads a notification that is waiting for review
+func (s *WalletService) findQueued(id uint) (*models.WalletNotification, error) {
+    notification, err := s.walletRepo.FindByID(id)
+    if err != nil {
+        if err.Error() == "record not found" {
+            return nil, fmt.Errorf("notification not found")
+        }
+        return nil, fmt.Errorf("failed to get notification: %w", err)
+    }
+    switch notification.Status {
+    case models.WalletNotificationUnmatched, models.WalletNotificationDuplicate, models.WalletNotificationFailed:
+    default:
+        return nil, fmt.Errorf("notification is not awaiting review")
+    }
+    return notification, nil
+}
+
+// claim stamps a reviewer's decision on a queued notification, failing when another reviewer
+// took it out of the queue since it was read
+func (s *WalletService) claim(notification *models.WalletNotification, status, username, notes string) error {
+    from := notification.Status
+    now := time.Now()
+    notification.Status = status
+    notification.ReviewedBy = username
+    notification.ReviewedAt = &now
+    notification.ReviewNotes = notes
+
+    claimed, err := s.walletRepo.ClaimForReview(notification, from)
+    if err != nil {
+        return fmt.Errorf("failed to update notification: %w", err)
+    }
+    if !claimed {
+        return fmt.Errorf("notification is not awaiting review")
+    }
+    return nil
+}
@@ -0,0 +1,4 @@
+-- Store the quoted pricing terms so schedules can be rebuilt from the loan calculator
+ALTER TABLE loans ADD COLUMN principal DECIMAL(10,2) DEFAULT 0;
+ALTER TABLE loans ADD COLUMN interest_rate DECIMAL(10,4) DEFAULT 0;
+ALTER TABLE loans ADD COLUMN interest_method VARCHAR(20) DEFAULT 'Flat';
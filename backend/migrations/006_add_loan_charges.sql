@@ -0,0 +1,16 @@
+-- Penalties and other charges added to a loan after release
+CREATE TABLE IF NOT EXISTS loan_charges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    loan_id INTEGER NOT NULL,
+    charge_type VARCHAR(20) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    charge_date DATETIME NOT NULL,
+    description VARCHAR(255),
+    created_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_loan_charges_loan_id ON loan_charges(loan_id);
@@ -0,0 +1,33 @@
+-- Branches and the holiday calendar used when laying out due dates
+CREATE TABLE IF NOT EXISTS branches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    address TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS holidays (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    branch_id INTEGER NULL,
+    name VARCHAR(100) NOT NULL,
+    date DATETIME NOT NULL,
+    recurring BOOLEAN DEFAULT FALSE,
+    type VARCHAR(20) DEFAULT 'Regular',
+    created_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (branch_id) REFERENCES branches(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_holidays_branch_id ON holidays(branch_id);
+CREATE INDEX IF NOT EXISTS idx_holidays_date ON holidays(date);
+
+ALTER TABLE clients ADD COLUMN branch_id INTEGER NULL REFERENCES branches(id);
+ALTER TABLE loans ADD COLUMN branch_id INTEGER NULL REFERENCES branches(id);
+
+CREATE INDEX IF NOT EXISTS idx_clients_branch_id ON clients(branch_id);
+CREATE INDEX IF NOT EXISTS idx_loans_branch_id ON loans(branch_id);
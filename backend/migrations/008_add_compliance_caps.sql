@@ -0,0 +1,33 @@
+-- Pricing caps checked when loans are booked, and the audit trail of admin overrides
+CREATE TABLE IF NOT EXISTS compliance_settings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    max_nominal_monthly_rate DECIMAL(10,4) DEFAULT 0,
+    max_effective_monthly_rate DECIMAL(10,4) DEFAULT 0,
+    max_fee_rate DECIMAL(10,4) DEFAULT 0,
+    action VARCHAR(20) DEFAULT 'reject',
+    updated_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS compliance_overrides (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    loan_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    approved_by VARCHAR(50) NOT NULL,
+    approved_by_id INTEGER,
+    nominal_monthly_rate DECIMAL(10,4),
+    effective_monthly_rate DECIMAL(10,4),
+    fee_rate DECIMAL(10,4),
+    violations TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_compliance_overrides_loan_id ON compliance_overrides(loan_id);
+
+ALTER TABLE loans ADD COLUMN effective_monthly_rate DECIMAL(10,4) DEFAULT 0;
+ALTER TABLE loans ADD COLUMN compliance_status VARCHAR(20) DEFAULT 'Compliant';
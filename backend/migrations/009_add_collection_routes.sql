@@ -0,0 +1,6 @@
+-- Field collection: the area a client lives in and the collector assigned to each loan
+ALTER TABLE clients ADD COLUMN area VARCHAR(100);
+ALTER TABLE loans ADD COLUMN collector_id INTEGER NULL REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_clients_area ON clients(area);
+CREATE INDEX IF NOT EXISTS idx_loans_collector_id ON loans(collector_id);
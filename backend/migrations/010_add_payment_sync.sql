@@ -0,0 +1,39 @@
+-- Offline sync: payments recorded on collectors' devices carry a device-generated UUID.
+-- SQLite cannot drop a table constraint, so the payments table is rebuilt to add the
+-- column and to drop UNIQUE(loan_id, week_number), which blocked a second partial
+-- payment towards the same installment.
+CREATE TABLE payments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    loan_id INTEGER NOT NULL,
+    week_number INTEGER NOT NULL,
+    payment_date DATETIME,
+    amount_due DECIMAL(10,2) NOT NULL,
+    amount_paid DECIMAL(10,2),
+    status VARCHAR(20) DEFAULT 'Pending',
+    payment_method VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    is_partial BOOLEAN DEFAULT FALSE,
+    completes_week BOOLEAN DEFAULT FALSE,
+    remaining_balance DECIMAL(10,2) DEFAULT 0,
+    sync_id VARCHAR(64) NULL,
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE
+);
+
+INSERT INTO payments_new (id, loan_id, week_number, payment_date, amount_due, amount_paid, status, payment_method,
+    created_at, updated_at, deleted_at, is_partial, completes_week, remaining_balance)
+SELECT id, loan_id, week_number, payment_date, amount_due, amount_paid, status, payment_method,
+    created_at, updated_at, deleted_at, is_partial, completes_week, remaining_balance
+FROM payments;
+
+DROP TABLE payments;
+ALTER TABLE payments_new RENAME TO payments;
+
+CREATE INDEX IF NOT EXISTS idx_payments_loan_id ON payments(loan_id);
+CREATE INDEX IF NOT EXISTS idx_payments_status ON payments(status);
+CREATE INDEX IF NOT EXISTS idx_payments_loan_week ON payments(loan_id, week_number);
+CREATE INDEX IF NOT EXISTS idx_payments_partial ON payments(loan_id, week_number, is_partial);
+CREATE INDEX IF NOT EXISTS idx_payments_remaining_balance ON payments(loan_id, week_number, remaining_balance);
+CREATE INDEX IF NOT EXISTS idx_payments_deleted_at ON payments(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_sync_id ON payments(sync_id);
@@ -0,0 +1,40 @@
+-- Cashier end-of-day reconciliation: who received each payment, who released each loan,
+-- and each cashier's drawer sessions
+ALTER TABLE payments ADD COLUMN received_by INTEGER NULL REFERENCES users(id);
+ALTER TABLE loans ADD COLUMN released_by_id INTEGER NULL REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_payments_received_by ON payments(received_by, created_at);
+CREATE INDEX IF NOT EXISTS idx_loans_released_by_id ON loans(released_by_id, created_at);
+
+CREATE TABLE IF NOT EXISTS cashier_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    username VARCHAR(50),
+    branch_id INTEGER NULL,
+    status VARCHAR(20) DEFAULT 'Open',
+    opened_at DATETIME NOT NULL,
+    closed_at DATETIME NULL,
+    opening_balance DECIMAL(12,2) DEFAULT 0,
+    payments_received DECIMAL(12,2) DEFAULT 0,
+    non_cash_received DECIMAL(12,2) DEFAULT 0,
+    payment_count INTEGER DEFAULT 0,
+    disbursements_released DECIMAL(12,2) DEFAULT 0,
+    disbursement_count INTEGER DEFAULT 0,
+    expected_closing DECIMAL(12,2) DEFAULT 0,
+    actual_count DECIMAL(12,2) NULL,
+    variance DECIMAL(12,2) DEFAULT 0,
+    flagged BOOLEAN DEFAULT FALSE,
+    notes TEXT,
+    reviewed_by VARCHAR(50),
+    reviewed_at DATETIME NULL,
+    review_notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (branch_id) REFERENCES branches(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cashier_sessions_user_id ON cashier_sessions(user_id, status);
+CREATE INDEX IF NOT EXISTS idx_cashier_sessions_branch_id ON cashier_sessions(branch_id);
+CREATE INDEX IF NOT EXISTS idx_cashier_sessions_flagged ON cashier_sessions(flagged);
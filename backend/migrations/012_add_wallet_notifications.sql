@@ -0,0 +1,30 @@
+-- E-wallet payment notifications received by webhook, with the review queue for unmatched ones
+CREATE TABLE IF NOT EXISTS wallet_notifications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    reference VARCHAR(100),
+    amount DECIMAL(12,2),
+    currency VARCHAR(3),
+    paid_at DATETIME,
+    payer_name VARCHAR(150),
+    payer_account VARCHAR(100),
+    status VARCHAR(20),
+    reason VARCHAR(255),
+    loan_id INTEGER NULL,
+    payment_id INTEGER NULL,
+    raw_payload TEXT,
+    reviewed_by VARCHAR(50),
+    reviewed_at DATETIME NULL,
+    review_notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (loan_id) REFERENCES loans(id),
+    FOREIGN KEY (payment_id) REFERENCES payments(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_notifications_event ON wallet_notifications(provider, event_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_notifications_reference ON wallet_notifications(reference);
+CREATE INDEX IF NOT EXISTS idx_wallet_notifications_status ON wallet_notifications(status);
+CREATE INDEX IF NOT EXISTS idx_wallet_notifications_loan_id ON wallet_notifications(loan_id);
@@ -0,0 +1,47 @@
+-- Bank statement imports and the credit lines matched to loan payments
+CREATE TABLE IF NOT EXISTS bank_imports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_name VARCHAR(255),
+    imported_by VARCHAR(50),
+    imported_by_id INTEGER NULL,
+    mapping TEXT,
+    row_count INTEGER DEFAULT 0,
+    credit_count INTEGER DEFAULT 0,
+    posted_count INTEGER DEFAULT 0,
+    review_count INTEGER DEFAULT 0,
+    unmatched_count INTEGER DEFAULT 0,
+    duplicate_count INTEGER DEFAULT 0,
+    total_credits DECIMAL(12,2) DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS bank_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    import_id INTEGER NOT NULL,
+    row_number INTEGER,
+    date DATETIME,
+    description VARCHAR(255),
+    reference VARCHAR(100),
+    amount DECIMAL(12,2),
+    status VARCHAR(20),
+    reason VARCHAR(255),
+    candidates TEXT,
+    loan_id INTEGER NULL,
+    payment_id INTEGER NULL,
+    reviewed_by VARCHAR(50),
+    reviewed_at DATETIME NULL,
+    review_notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (import_id) REFERENCES bank_imports(id) ON DELETE CASCADE,
+    FOREIGN KEY (loan_id) REFERENCES loans(id),
+    FOREIGN KEY (payment_id) REFERENCES payments(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_import_id ON bank_transactions(import_id);
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_status ON bank_transactions(status);
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_lookup ON bank_transactions(date, amount, reference);
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_loan_id ON bank_transactions(loan_id);
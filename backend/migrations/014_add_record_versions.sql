@@ -0,0 +1,5 @@
+-- Optimistic locking: every write to a loan, client or payment bumps its version,
+-- and updates only apply when the row still has the version that was read
+ALTER TABLE loans ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE clients ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE payments ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,21 @@
+-- Nightly per-loan portfolio snapshots, so historical reports read frozen figures
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    snapshot_date DATETIME NOT NULL,
+    loan_id INTEGER NOT NULL,
+    client_id INTEGER NOT NULL,
+    branch_id INTEGER NULL,
+    collector_id INTEGER NULL,
+    status VARCHAR(20),
+    outstanding DECIMAL(12,2) DEFAULT 0,
+    amount_past_due DECIMAL(12,2) DEFAULT 0,
+    days_past_due INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (loan_id) REFERENCES loans(id),
+    FOREIGN KEY (client_id) REFERENCES clients(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portfolio_snapshots_date_loan ON portfolio_snapshots(snapshot_date, loan_id);
+CREATE INDEX IF NOT EXISTS idx_portfolio_snapshots_client_id ON portfolio_snapshots(client_id);
+CREATE INDEX IF NOT EXISTS idx_portfolio_snapshots_branch_id ON portfolio_snapshots(branch_id);
+CREATE INDEX IF NOT EXISTS idx_portfolio_snapshots_collector_id ON portfolio_snapshots(collector_id);
@@ -0,0 +1,9 @@
+-- Link each loan to the loan officer's user account for performance reporting
+ALTER TABLE loans ADD COLUMN officer_id INTEGER NULL REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_loans_officer_id ON loans(officer_id);
+
+-- Existing loans only named the officer in recommended_by, so link those that match a username
+UPDATE loans
+SET officer_id = (SELECT users.id FROM users WHERE users.username = loans.recommended_by AND users.deleted_at IS NULL)
+WHERE officer_id IS NULL AND recommended_by IS NOT NULL AND recommended_by != '';
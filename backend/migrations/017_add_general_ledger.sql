@@ -0,0 +1,57 @@
+-- Chart of accounts and the double-entry journal posted from loan events
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    contra BOOLEAN DEFAULT 0,
+    system BOOLEAN DEFAULT 0,
+    description VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_code ON ledger_accounts(code);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entry_date DATETIME NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    source_key VARCHAR(100) NULL,
+    reference VARCHAR(50),
+    description VARCHAR(255),
+    loan_id INTEGER NULL,
+    payment_id INTEGER NULL,
+    reverses_id INTEGER NULL,
+    reversed_by_id INTEGER NULL,
+    posted_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (loan_id) REFERENCES loans(id),
+    FOREIGN KEY (payment_id) REFERENCES payments(id),
+    FOREIGN KEY (reverses_id) REFERENCES journal_entries(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_source_key ON journal_entries(source_key);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_entry_date ON journal_entries(entry_date);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_source ON journal_entries(source);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_loan_id ON journal_entries(loan_id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_payment_id ON journal_entries(payment_id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_reverses_id ON journal_entries(reverses_id);
+
+CREATE TABLE IF NOT EXISTS journal_lines (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entry_id INTEGER NOT NULL,
+    account_code VARCHAR(20) NOT NULL,
+    loan_id INTEGER NULL,
+    debit DECIMAL(12,2) DEFAULT 0,
+    credit DECIMAL(12,2) DEFAULT 0,
+    memo VARCHAR(255),
+    FOREIGN KEY (entry_id) REFERENCES journal_entries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_lines_entry_id ON journal_lines(entry_id);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account_code ON journal_lines(account_code);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_loan_id ON journal_lines(loan_id);
@@ -0,0 +1,17 @@
+-- Closed accounting periods. Nothing posts on or before the latest period_end still closed
+CREATE TABLE IF NOT EXISTS ledger_period_closes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    period_start DATETIME NOT NULL,
+    period_end DATETIME NOT NULL,
+    net_income DECIMAL(12,2) DEFAULT 0,
+    closed_by VARCHAR(50),
+    notes VARCHAR(255),
+    reopened_at DATETIME NULL,
+    reopened_by VARCHAR(50),
+    reopen_reason VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_period_closes_period_end ON ledger_period_closes(period_end);
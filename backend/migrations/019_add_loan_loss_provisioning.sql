@@ -0,0 +1,44 @@
+-- Month-end allowance for loan losses by days-past-due bucket
+CREATE TABLE IF NOT EXISTS provisioning_settings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rate_current DECIMAL(10,4) DEFAULT 0,
+    rate_1_7 DECIMAL(10,4) DEFAULT 0,
+    rate_8_30 DECIMAL(10,4) DEFAULT 0,
+    rate_31_60 DECIMAL(10,4) DEFAULT 0,
+    rate_61_90 DECIMAL(10,4) DEFAULT 0,
+    rate_over_90 DECIMAL(10,4) DEFAULT 0,
+    updated_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS provision_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    month VARCHAR(7) NOT NULL,
+    period_end DATETIME NOT NULL,
+    loans INTEGER DEFAULT 0,
+    net_balance DECIMAL(12,2) DEFAULT 0,
+    opening_allowance DECIMAL(12,2) DEFAULT 0,
+    required_allowance DECIMAL(12,2) DEFAULT 0,
+    adjustment DECIMAL(12,2) DEFAULT 0,
+    entry_id INTEGER NULL REFERENCES journal_entries(id),
+    run_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_provision_runs_month ON provision_runs(month);
+
+CREATE TABLE IF NOT EXISTS provision_run_buckets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id INTEGER NOT NULL REFERENCES provision_runs(id),
+    bucket VARCHAR(20) NOT NULL,
+    rate DECIMAL(10,4) DEFAULT 0,
+    loans INTEGER DEFAULT 0,
+    net_balance DECIMAL(12,2) DEFAULT 0,
+    required DECIMAL(12,2) DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_provision_run_buckets_run_id ON provision_run_buckets(run_id);
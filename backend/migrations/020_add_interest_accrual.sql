@@ -0,0 +1,38 @@
+-- Month-end interest accrual by product method, on a cash basis for non-performing loans
+CREATE TABLE IF NOT EXISTS interest_accrual_settings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    default_method VARCHAR(20) NOT NULL,
+    non_performing_days INTEGER DEFAULT 90,
+    updated_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS accrual_product_methods (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product VARCHAR(50) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accrual_product_methods_product ON accrual_product_methods(product);
+
+CREATE TABLE IF NOT EXISTS interest_accrual_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    month VARCHAR(7) NOT NULL,
+    period_end DATETIME NOT NULL,
+    loans INTEGER DEFAULT 0,
+    non_performing INTEGER DEFAULT 0,
+    accrued DECIMAL(12,2) DEFAULT 0,
+    reversed DECIMAL(12,2) DEFAULT 0,
+    entries INTEGER DEFAULT 0,
+    run_by VARCHAR(50),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accrual_runs_month ON interest_accrual_runs(month);
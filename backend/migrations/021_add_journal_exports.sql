@@ -0,0 +1,34 @@
+-- Journal exports to external accounting software, with the account mapping they use
+CREATE TABLE IF NOT EXISTS ledger_account_mappings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_code VARCHAR(20) NOT NULL,
+    external_code VARCHAR(50),
+    external_name VARCHAR(100),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_account_mappings_account_code ON ledger_account_mappings(account_code);
+
+CREATE TABLE IF NOT EXISTS journal_export_batches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    format VARCHAR(10) NOT NULL,
+    period_start DATETIME NOT NULL,
+    period_end DATETIME NOT NULL,
+    entries INTEGER DEFAULT 0,
+    total_debit DECIMAL(14,2) DEFAULT 0,
+    content TEXT,
+    exported_by VARCHAR(50),
+    voided_at DATETIME NULL,
+    voided_by VARCHAR(50),
+    void_reason VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL
+);
+
+-- Entries already exported carry their batch
+ALTER TABLE journal_entries ADD COLUMN export_batch_id INTEGER NULL REFERENCES journal_export_batches(id);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_export_batch_id ON journal_entries(export_batch_id);
@@ -99,9 +99,9 @@ func loadMigrations(migrationsPath string) ([]Migration, error) {
 
         filename := file.Name()
         // Look for files like "001_initial_schema.sql"
-        if strings.HasSuffix(filename, ".sql") && strings.HasPrefix(filename, "00") {
+        if strings.HasSuffix(filename, ".sql") {
             parts := strings.Split(filename, "_")
-            if len(parts) < 2 {
+            if len(parts) < 2 || !isVersion(parts[0]) {
                 continue // Skip invalid filenames
             }
 
@@ -130,6 +130,19 @@ func loadMigrations(migrationsPath string) ([]Migration, error) {
     return migrations, nil
 }
 
+// isVersion reports whether a filename prefix is a zero-padded version like "001" or "010"
+func isVersion(prefix string) bool {
+    if len(prefix) != 3 {
+        return false
+    }
+    for _, r := range prefix {
+        if r < '0' || r > '9' {
+            return false
+        }
+    }
+    return true
+}
+
 // createMigrationTable creates the migration tracking table
 func createMigrationTable(db *sql.DB) error {
     query := `
@@ -0,0 +1,110 @@
+package main
+
+// Fake e-wallet provider for local testing of the payment webhook.
+//
+//   go run ./scripts/fake_wallet -loan LOAN-1700000000 -amount 560
+//
+// The server accepts the "fake" provider outside production, and only when it
+// was started with FAKE_WALLET_SECRET set; sign with the same secret.
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "micro-lending-platform/backend/internal/services"
+    "net/http"
+    "os"
+    "strconv"
+    "time"
+)
+
+type notification struct {
+    EventID      string  `json:"event_id"`
+    Reference    string  `json:"reference"`
+    Amount       float64 `json:"amount"`
+    Currency     string  `json:"currency"`
+    PaidAt       string  `json:"paid_at"`
+    PayerName    string  `json:"payer_name"`
+    PayerAccount string  `json:"payer_account"`
+}
+
+func main() {
+    url := flag.String("url", "http://localhost:8080/api/v1/webhooks/wallet/fake", "webhook URL")
+    secret := flag.String("secret", envOr("FAKE_WALLET_SECRET", ""), "signing secret")
+    loan := flag.String("loan", "", "loan control number the payer entered")
+    amount := flag.Float64("amount", 0, "amount paid")
+    eventID := flag.String("event", "", "event ID (random if empty)")
+    payer := flag.String("payer", "Juan Dela Cruz", "payer name")
+    times := flag.Int("times", 1, "deliveries of the same event, to exercise redelivery handling")
+    badSignature := flag.Bool("bad-signature", false, "send a wrong signature")
+    flag.Parse()
+
+    if *loan == "" || *amount <= 0 {
+        fmt.Println("usage: go run ./scripts/fake_wallet -loan <control number> -amount <amount>")
+        os.Exit(1)
+    }
+    if *secret == "" {
+        fmt.Println("set FAKE_WALLET_SECRET (or -secret) to the secret the server was started with")
+        os.Exit(1)
+    }
+    if *eventID == "" {
+        *eventID = "fake-" + randomHex(8)
+    }
+
+    body, err := json.Marshal(notification{
+        EventID:      *eventID,
+        Reference:    *loan,
+        Amount:       *amount,
+        Currency:     "PHP",
+        PaidAt:       time.Now().Format(time.RFC3339),
+        PayerName:    *payer,
+        PayerAccount: "09170000000",
+    })
+    if err != nil {
+        fmt.Printf("Failed to build notification: %v\n", err)
+        os.Exit(1)
+    }
+
+    for i := 0; i < *times; i++ {
+        timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+        signature := services.SignWalletWebhook(*secret, timestamp, body)
+        if *badSignature {
+            signature = randomHex(32)
+        }
+
+        req, err := http.NewRequest(http.MethodPost, *url, bytes.NewReader(body))
+        if err != nil {
+            fmt.Printf("Failed to build request: %v\n", err)
+            os.Exit(1)
+        }
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("X-Webhook-Timestamp", timestamp)
+        req.Header.Set("X-Webhook-Signature", "sha256="+signature)
+
+        resp, err := http.DefaultClient.Do(req)
+        if err != nil {
+            fmt.Printf("Failed to send notification: %v\n", err)
+            os.Exit(1)
+        }
+        respBody, _ := io.ReadAll(resp.Body)
+        resp.Body.Close()
+        fmt.Printf("Delivery %d of %s: %d %s\n", i+1, *eventID, resp.StatusCode, string(respBody))
+    }
+}
+
+func randomHex(n int) string {
+    b := make([]byte, n)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
+
+func envOr(key, fallback string) string {
+    if value := os.Getenv(key); value != "" {
+        return value
+    }
+    return fallback
+}
@@ -56,6 +56,35 @@ export const reportService = {
     }
   },
 
+  /**
+   * Download a report rendered by the server
+   * @param {string} report - Report path under /reports, e.g. 'weekly' or 'par/loans'
+   * @param {string} format - 'csv', 'xlsx' or 'pdf'
+   * @param {Object} params - Extra query parameters such as from, to or period
+   */
+  async downloadReport(report, format = 'csv', params = {}) {
+    try {
+      const response = await api.get(`/reports/${report}`, {
+        params: { ...params, format },
+        responseType: 'blob',
+        timeout: 120000
+      })
+      const disposition = response.headers['content-disposition'] || ''
+      const match = disposition.match(/filename="([^"]+)"/)
+      const url = URL.createObjectURL(response.data)
+      const link = document.createElement('a')
+      link.href = url
+      link.download = match ? match[1] : `${report.replace('/', '-')}.${format}`
+      document.body.appendChild(link)
+      link.click()
+      document.body.removeChild(link)
+      URL.revokeObjectURL(url)
+    } catch (error) {
+      console.error('Error downloading report:', error)
+      throw new Error('Failed to download report')
+    }
+  },
+
   /**
    * Export report as JSON
    * @param {Object} reportData - Report data to export